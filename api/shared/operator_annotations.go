@@ -12,4 +12,34 @@ const (
 	IsClusterScopedAnnotation  = OperatorGroup + Separator + "is-cluster-scoped"
 	CustomStateCheckAnnotation = OperatorGroup + Separator + "custom-state-check"
 	ModuleVersionAnnotation    = OperatorGroup + Separator + "module-version"
+	// PlanAnnotation set to "true" on a Kyma suspends the reconciliation of its modules and instead records
+	// the planned changes in the Kyma status.
+	PlanAnnotation = OperatorGroup + Separator + "plan"
+	// DependenciesAnnotation lists the comma-separated names of the modules a Manifest depends on,
+	// so the Manifests of a Kyma can be deleted in reverse dependency order.
+	DependenciesAnnotation = OperatorGroup + Separator + "dependencies"
+	// LastKnownGoodAnnotation holds the Manifest spec of the last version of a module that was Ready
+	// before an upgrade, so a failed upgrade can be rolled back to it.
+	LastKnownGoodAnnotation = OperatorGroup + Separator + "last-known-good"
+	// RollbackToAnnotation set on a Manifest to the version in its LastKnownGoodAnnotation requests the rollback
+	// of the module to that version.
+	RollbackToAnnotation = OperatorGroup + Separator + "rollback-to"
+	// ModuleConfigAnnotation holds the module config of the Kyma spec that is merged into the module CR of a Manifest,
+	// so the configured fields are kept in sync in the module CR.
+	ModuleConfigAnnotation = OperatorGroup + Separator + "module-config"
+	// ModuleConfigAppliedAnnotation is set to "true" on a Manifest as long as a module config is applied to its
+	// module CR, so the fields of a removed module config are reset without reading the module CR of every Manifest.
+	ModuleConfigAppliedAnnotation = OperatorGroup + Separator + "module-config-applied"
+	// CustomResourcePolicyAnnotation is set on a Manifest to the CustomResourcePolicy of the module
+	// if its module CR has to be reconciled against the default data of the ModuleTemplate.
+	CustomResourcePolicyAnnotation = OperatorGroup + Separator + "custom-resource-policy"
+	// InstallTimeoutAnnotation is set on a Manifest to the install timeout of the ModuleTemplate,
+	// which is the maximum duration for the module to become Ready after it is installed or upgraded.
+	InstallTimeoutAnnotation = OperatorGroup + Separator + "install-timeout"
+	// DeleteTimeoutAnnotation is set on a Manifest to the delete timeout of the ModuleTemplate,
+	// which is the maximum duration for the deletion of the module.
+	DeleteTimeoutAnnotation = OperatorGroup + Separator + "delete-timeout"
+	// TraceParentAnnotation is set on a Manifest to the W3C traceparent of the Kyma reconciliation that last
+	// changed it, so the traces of the Manifest reconciliations can be linked to it.
+	TraceParentAnnotation = OperatorGroup + Separator + "traceparent"
 )
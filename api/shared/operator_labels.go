@@ -35,6 +35,10 @@ const (
 	// If put on a single ModuleTemplate, allows to disable sync just for this object.
 	SyncLabel = OperatorGroup + Separator + "sync"
 
+	// DryRunLabel indicates this specific resource will only be reconciled with server-side dry-run,
+	// recording the changes instead of applying them.
+	DryRunLabel = OperatorGroup + Separator + "dry-run"
+
 	EnableLabelValue  = "true"
 	DisableLabelValue = "false"
 )
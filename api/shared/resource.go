@@ -1,6 +1,7 @@
 package shared
 
 import (
+	"fmt"
 	"strings"
 
 	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,6 +24,43 @@ func (r Resource) ToUnstructured() *unstructured.Unstructured {
 	return &obj
 }
 
+// NamespacedName returns the name of the Resource, prefixed with its namespace if it is namespaced.
+func (r Resource) NamespacedName() string {
+	if r.Namespace == "" {
+		return r.Name
+	}
+	return r.Namespace + "/" + r.Name
+}
+
 func (r Resource) ID() string {
 	return strings.Join([]string{r.Namespace, r.Name, r.Group, r.Version, r.Kind}, "/")
 }
+
+const (
+	// MaxUnhealthyResources is the maximum number of UnhealthyResources in a Status.
+	MaxUnhealthyResources = 10
+	// MaxUnhealthyResourceMessageLength is the maximum length of the Message of an UnhealthyResource.
+	MaxUnhealthyResourceMessageLength = 256
+)
+
+// UnhealthyResource is a synced Resource that is not ready.
+// +k8s:deepcopy-gen=true
+type UnhealthyResource struct {
+	Resource `json:",inline"`
+
+	// Reason is a brief CamelCase reason why the Resource is not ready, e.g. the reason of its Reconciling
+	// or Stalled condition, or its status, such as InProgress or Failed.
+	Reason string `json:"reason"`
+
+	// Message is a human-readable message indicating why the Resource is not ready.
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+func (r UnhealthyResource) String() string {
+	if r.Message == "" {
+		return fmt.Sprintf("%s %s (%s)", r.Kind, r.NamespacedName(), r.Reason)
+	}
+	return fmt.Sprintf("%s %s (%s)", r.Kind, r.NamespacedName(), r.Message)
+}
@@ -26,6 +26,42 @@ type Status struct {
 	// +listType=atomic
 	Synced        []Resource `json:"synced,omitempty"`
 	LastOperation `json:"lastOperation,omitempty"`
+
+	// DryRun contains the changes the last reconciliation would have applied to the target cluster.
+	// It is only set while the CustomObject is labeled with operator.kyma-project.io/dry-run=true.
+	// +optional
+	DryRun *DryRun `json:"dryRun,omitempty"`
+
+	// UnhealthyResources contains the synced resources that were not ready in the last readiness check.
+	// If resources failed, only the failed resources are listed. The list is limited to MaxUnhealthyResources.
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	UnhealthyResources []UnhealthyResource `json:"unhealthyResources,omitempty"`
+
+	// UnhealthyResourcesCount is the number of all resources that were not ready in the last readiness check,
+	// including the ones that are not listed in UnhealthyResources.
+	// +optional
+	UnhealthyResourcesCount int `json:"unhealthyResourcesCount,omitempty"`
+}
+
+// DryRun contains the result of a reconciliation that was applied with server-side dry-run.
+// +k8s:deepcopy-gen=true
+type DryRun struct {
+	// LastDryRunTime is the time the dry-run was executed.
+	LastDryRunTime apimetav1.Time `json:"lastDryRunTime,omitempty"`
+
+	// Created contains the Resources that do not exist yet and would be created.
+	// +listType=atomic
+	Created []Resource `json:"created,omitempty"`
+
+	// Updated contains the Resources that exist and would be changed.
+	// +listType=atomic
+	Updated []Resource `json:"updated,omitempty"`
+
+	// Pruned contains the Resources that are no longer rendered and would be deleted.
+	// +listType=atomic
+	Pruned []Resource `json:"pruned,omitempty"`
 }
 
 func (s Status) WithState(state State) Status {
@@ -38,6 +74,17 @@ func (s Status) WithErr(err error) Status {
 	return s
 }
 
+// WithUnhealthyResources sets the UnhealthyResources, limited to the first MaxUnhealthyResources,
+// and the UnhealthyResourcesCount.
+func (s Status) WithUnhealthyResources(resources []UnhealthyResource) Status {
+	s.UnhealthyResourcesCount = len(resources)
+	if len(resources) > MaxUnhealthyResources {
+		resources = resources[:MaxUnhealthyResources]
+	}
+	s.UnhealthyResources = resources
+	return s
+}
+
 func (s Status) WithOperation(operation string) Status {
 	s.LastOperation = LastOperation{Operation: operation, LastUpdateTime: apimetav1.NewTime(time.Now())}
 	return s
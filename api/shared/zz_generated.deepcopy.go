@@ -24,6 +24,37 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRun) DeepCopyInto(out *DryRun) {
+	*out = *in
+	in.LastDryRunTime.DeepCopyInto(&out.LastDryRunTime)
+	if in.Created != nil {
+		in, out := &in.Created, &out.Created
+		*out = make([]Resource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Updated != nil {
+		in, out := &in.Updated, &out.Updated
+		*out = make([]Resource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pruned != nil {
+		in, out := &in.Pruned, &out.Pruned
+		*out = make([]Resource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRun.
+func (in *DryRun) DeepCopy() *DryRun {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LastOperation) DeepCopyInto(out *LastOperation) {
 	*out = *in
@@ -72,6 +103,16 @@ func (in *Status) DeepCopyInto(out *Status) {
 		copy(*out, *in)
 	}
 	in.LastOperation.DeepCopyInto(&out.LastOperation)
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(DryRun)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UnhealthyResources != nil {
+		in, out := &in.UnhealthyResources, &out.UnhealthyResources
+		*out = make([]UnhealthyResource, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
@@ -83,3 +124,19 @@ func (in *Status) DeepCopy() *Status {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnhealthyResource) DeepCopyInto(out *UnhealthyResource) {
+	*out = *in
+	out.Resource = in.Resource
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnhealthyResource.
+func (in *UnhealthyResource) DeepCopy() *UnhealthyResource {
+	if in == nil {
+		return nil
+	}
+	out := new(UnhealthyResource)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -90,7 +90,9 @@ func (in *KymaSpec) DeepCopyInto(out *KymaSpec) {
 	if in.Modules != nil {
 		in, out := &in.Modules, &out.Modules
 		*out = make([]v1beta2.Module, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	out.Sync = in.Sync
 }
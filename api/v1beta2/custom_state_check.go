@@ -0,0 +1,51 @@
+package v1beta2
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+)
+
+// StateCheckObjectVariable is the variable the Module CR is bound to in the Expression of a CustomStateCheck.
+const StateCheckObjectVariable = "object"
+
+var ErrInvalidCustomStateCheck = errors.New("invalid customStateCheck")
+
+// StateCheckExpressionValidator validates the Expression of a CustomStateCheck.
+type StateCheckExpressionValidator interface {
+	ValidateExpression(expression string) error
+}
+
+// Validate checks that the CustomStateCheck either uses a JSONPath or an Expression.
+// The Expression itself is only validated if an expression validator is given.
+func (c *CustomStateCheck) Validate(expressions StateCheckExpressionValidator) error {
+	if c.Expression == "" {
+		if c.JSONPath == "" {
+			return fmt.Errorf("%w: either jsonPath or expression has to be set", ErrInvalidCustomStateCheck)
+		}
+		return nil
+	}
+	if c.JSONPath != "" || c.Value != "" {
+		return fmt.Errorf("%w: expression cannot be combined with jsonPath and value", ErrInvalidCustomStateCheck)
+	}
+	if expressions == nil {
+		return nil
+	}
+	return expressions.ValidateExpression(c.Expression)
+}
+
+// MissingRequiredStates returns the states every set of CustomStateChecks has to map to, Ready and Error,
+// that none of the CustomStateChecks is mapped to.
+func MissingRequiredStates(stateChecks []*CustomStateCheck) []shared.State {
+	var missing []shared.State
+	for _, required := range []shared.State{shared.StateReady, shared.StateError} {
+		if !slices.ContainsFunc(stateChecks, func(stateCheck *CustomStateCheck) bool {
+			return stateCheck != nil && stateCheck.MappedState == required
+		}) {
+			missing = append(missing, required)
+		}
+	}
+	return missing
+}
@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	machineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/kyma-project/lifecycle-manager/api/shared"
@@ -53,8 +54,37 @@ type KymaSpec struct {
 	// +listType=map
 	// +listMapKey=name
 	Modules []Module `json:"modules,omitempty"`
+
+	// MaintenanceWindows restrict upgrades of already installed modules to the given time ranges.
+	// Installations of new modules and deletions are not deferred. If empty, upgrades are applied immediately.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// MaintenanceWindow is a weekly recurring time range in which module upgrades are applied.
+type MaintenanceWindow struct {
+	// Days are the weekdays on which the window opens, e.g. Saturday. If empty, the window opens every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start is the time of day the window opens at, in the format HH:MM.
+	// +kubebuilder:validation:Pattern:=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// End is the time of day the window closes at, in the format HH:MM.
+	// If End is not after Start, the window closes on the next day.
+	// +kubebuilder:validation:Pattern:=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+
+	// TimeZone is the IANA time zone of Start and End, e.g. Europe/Berlin. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
+// Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
 // Module defines the components to be installed.
 type Module struct {
 	// Name is a unique identifier of the module.
@@ -78,6 +108,12 @@ type Module struct {
 	// +kubebuilder:validation:MinLength:=3
 	Channel string `json:"channel,omitempty"`
 
+	// Version is an optional semantic version constraint of the Module, e.g. 1.4.2, ~1.4 or ">= 1.2, < 2.0".
+	// If set, the ModuleTemplate with the highest descriptor version satisfying the constraint is resolved among
+	// all ModuleTemplates of the Module, regardless of their Channel.
+	// +kubebuilder:validation:MaxLength:=64
+	Version string `json:"version,omitempty"`
+
 	// RemoteModuleTemplateRef is the reference (FQDN, Namespace/Name, Module Name Label)
 	// to the module template on the remote cluster.
 	// If specified, the module template will be fetched from the SKR and reconciled.
@@ -85,12 +121,20 @@ type Module struct {
 
 	// +kubebuilder:default:=CreateAndDelete
 	CustomResourcePolicy `json:"customResourcePolicy,omitempty"`
+
+	// Config is deep-merged over the default data of the ModuleTemplate when the module CR is generated,
+	// e.g. {"spec": {"replicas": 3}}. Objects are merged recursively, all other values replace the defaults.
+	// The fields set in Config are kept in sync in the module CR, while all other fields can still be changed.
+	// Config is ignored if the CustomResourcePolicy is Ignore.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Config *machineryruntime.RawExtension `json:"config,omitempty"`
 }
 
 // CustomResourcePolicy determines how a ModuleTemplate should be parsed. When CustomResourcePolicy is set to
 // CustomResourcePolicyCreateAndDelete, the Manifest will receive instructions to create it on installation with
 // the default values provided in ModuleTemplate, and to remove it when the module or Kyma is deleted.
-// +kubebuilder:validation:Enum=CreateAndDelete;Ignore
+// +kubebuilder:validation:Enum=CreateAndDelete;Ignore;Reconcile
 type CustomResourcePolicy string
 
 const (
@@ -102,6 +146,10 @@ const (
 	// This is useful if another controller should manage module configuration as data and not be auto-initialized.
 	// It can also be used to initialize controllers without interacting with them.
 	CustomResourcePolicyIgnore = "Ignore"
+	// CustomResourcePolicyReconcile causes the Manifest to contain the default data provided in ModuleTemplate,
+	// which is server-side applied to the resource on every reconciliation, so updates from the Data are propagated.
+	// Fields set by other field managers stay untouched. The resource is deleted on module removal.
+	CustomResourcePolicyReconcile = "Reconcile"
 )
 
 // SyncStrategy determines how the Remote Cluster is synchronized with the Control Plane. This can influence secret
@@ -111,6 +159,13 @@ type SyncStrategy string
 const (
 	SyncStrategyLocalSecret = "local-secret"
 	SyncStrategyLocalClient = "local-client"
+	// SyncStrategyKubeconfigFile reads the kubeconfig of the remote cluster from a file mounted by the platform.
+	SyncStrategyKubeconfigFile = "kubeconfig-file"
+	// SyncStrategyTokenFile authenticates against the remote cluster with a rotated bearer token file.
+	SyncStrategyTokenFile = "token-file"
+	// SyncStrategyServiceAccountToken authenticates against the remote cluster with a short-lived
+	// ServiceAccount token minted in the Control Plane.
+	SyncStrategyServiceAccountToken = "service-account-token"
 )
 
 func (kyma *Kyma) GetModuleStatusMap() map[string]*ModuleStatus {
@@ -142,9 +197,77 @@ type KymaStatus struct {
 	// +optional
 	ActiveChannel string `json:"activeChannel,omitempty"`
 
+	// Plan contains the changes that would be applied to the modules if the Kyma was reconciled.
+	// It is only calculated while the Kyma is annotated with operator.kyma-project.io/plan=true.
+	// +optional
+	Plan *KymaPlan `json:"plan,omitempty"`
+
+	// History contains the last transitions of the State of the Kyma, the oldest first.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=20
+	History []StateTransition `json:"history,omitempty"`
+
 	shared.LastOperation `json:"lastOperation,omitempty"`
 }
 
+// KymaPlan is the result of a dry-run of the module reconciliation of a Kyma.
+type KymaPlan struct {
+	// ObservedGeneration is the generation of the Kyma the plan was calculated for.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastPlanTime is the time the plan was calculated.
+	LastPlanTime apimetav1.Time `json:"lastPlanTime,omitempty"`
+
+	// Modules contains the planned action for each module.
+	// +listType=map
+	// +listMapKey=name
+	Modules []ModulePlan `json:"modules,omitempty"`
+}
+
+// ModulePlanAction describes what the reconciliation would do with the Manifest of a module.
+// +kubebuilder:validation:Enum=Create;Update;Delete;Unchanged;Error
+type ModulePlanAction string
+
+const (
+	ModulePlanActionCreate    ModulePlanAction = "Create"
+	ModulePlanActionUpdate    ModulePlanAction = "Update"
+	ModulePlanActionDelete    ModulePlanAction = "Delete"
+	ModulePlanActionUnchanged ModulePlanAction = "Unchanged"
+	ModulePlanActionError     ModulePlanAction = "Error"
+)
+
+type ModulePlan struct {
+	// Name defines the name of the Module in the Spec that the plan is calculated for.
+	Name string `json:"name"`
+
+	// FQDN is the fully qualified domain name of the resolved module.
+	FQDN string `json:"fqdn,omitempty"`
+
+	// Action is the change that would be applied to the Manifest of the module.
+	Action ModulePlanAction `json:"action"`
+
+	// Template is the ModuleTemplate the module would be resolved to.
+	// +optional
+	Template *TrackingObject `json:"template,omitempty"`
+
+	// Manifest is the Manifest that would be created, updated or deleted.
+	// +optional
+	Manifest *TrackingObject `json:"manifest,omitempty"`
+
+	// Channel is the channel of the resolved ModuleTemplate.
+	Channel string `json:"channel,omitempty"`
+
+	// Version is the version of the module after applying the plan.
+	Version string `json:"version,omitempty"`
+
+	// PreviousVersion is the version of the module currently installed.
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
+	// Message is a human-readable message indicating details about the Action.
+	Message string `json:"message,omitempty"`
+}
+
 type ModuleStatus struct {
 	// Name defines the name of the Module in the Spec that the status is used for.
 	// It can be any kind of Reference format supported by Module.Name.
@@ -170,6 +293,10 @@ type ModuleStatus struct {
 	// Channel tracks the active Version of the Module.
 	Version string `json:"version,omitempty"`
 
+	// LastReadyVersion is the Version the Module was last Ready in, so upgrades of the Module can be reported.
+	// +optional
+	LastReadyVersion string `json:"lastReadyVersion,omitempty"`
+
 	// Message is a human-readable message indicating details about the State.
 	Message string `json:"message,omitempty"`
 
@@ -178,6 +305,60 @@ type ModuleStatus struct {
 
 	// Resource contains information about the created module CR.
 	Resource *TrackingObject `json:"resource,omitempty"`
+
+	// DeferredUpgrade is the upgrade of the Module that waits for the next maintenance window of the Kyma,
+	// for the release by a ModuleRollout, or for the retry of a failed upgrade.
+	// +optional
+	DeferredUpgrade *DeferredUpgrade `json:"deferredUpgrade,omitempty"`
+
+	// FailedUpgrade is the last upgrade of the Module that was rolled back to the last known good version.
+	// +optional
+	FailedUpgrade *FailedUpgrade `json:"failedUpgrade,omitempty"`
+
+	// History contains the last transitions of the State of the Module, the oldest first.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=10
+	History []StateTransition `json:"history,omitempty"`
+}
+
+// DeferredUpgrade describes a module upgrade that waits for the next maintenance window, for a ModuleRollout,
+// or for the retry of a failed upgrade.
+type DeferredUpgrade struct {
+	// Version is the version the Module will be upgraded to.
+	Version string `json:"version"`
+
+	// Channel is the channel the version of the upgrade is resolved from.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// NextWindow is the time the next maintenance window opens at, if the upgrade waits for a maintenance window.
+	// +optional
+	NextWindow *apimetav1.Time `json:"nextWindow,omitempty"`
+
+	// Rollout is the name of the ModuleRollout that has not released the upgrade to the Kyma yet.
+	// +optional
+	Rollout string `json:"rollout,omitempty"`
+
+	// RetryAfter is the time the upgrade is retried at, if the version was rolled back after a failed upgrade.
+	// +optional
+	RetryAfter *apimetav1.Time `json:"retryAfter,omitempty"`
+}
+
+// FailedUpgrade describes a module upgrade that was rolled back.
+type FailedUpgrade struct {
+	// Version is the version of the failed upgrade.
+	Version string `json:"version"`
+
+	// RolledBackTo is the version the Module was rolled back to.
+	RolledBackTo string `json:"rolledBackTo"`
+
+	// Time is the time the upgrade was rolled back at.
+	Time apimetav1.Time `json:"time"`
+
+	// Reason describes why the upgrade was rolled back.
+	// +optional
+	Reason string `json:"reason,omitempty"`
 }
 
 // TrackingObject contains TypeMeta and PartialMeta to allow a generation based object tracking.
@@ -384,6 +565,11 @@ func (kyma *Kyma) SkipReconciliation() bool {
 	return found && strings.ToLower(skip) == shared.EnableLabelValue
 }
 
+func (kyma *Kyma) IsPlanRequested() bool {
+	plan, found := kyma.Annotations[shared.PlanAnnotation]
+	return found && strings.ToLower(plan) == shared.EnableLabelValue
+}
+
 func (kyma *Kyma) IsInternal() bool {
 	internal, found := kyma.Labels[shared.InternalLabel]
 	return found && strings.ToLower(internal) == shared.EnableLabelValue
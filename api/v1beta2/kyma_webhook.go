@@ -17,17 +17,125 @@ limitations under the License.
 package v1beta2
 
 import (
+	"context"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	machineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
 )
 
-func (kyma *Kyma) SetupWebhookWithManager(mgr ctrl.Manager) error {
+func (kyma *Kyma) SetupWebhookWithManager(mgr ctrl.Manager, validator *KymaValidator) error {
 	err := ctrl.NewWebhookManagedBy(mgr).
 		For(kyma).
+		WithValidator(validator).
 		Complete()
 	if err != nil {
 		return fmt.Errorf("failed to setup webhook with manager: %w", err)
 	}
 	return nil
 }
+
+// KymaModuleValidator validates the modules of a Kyma against the ModuleTemplates they are resolved to.
+type KymaModuleValidator interface {
+	ValidateModules(ctx context.Context, kyma *Kyma) field.ErrorList
+}
+
+// +kubebuilder:webhook:path=/validate-operator-kyma-project-io-v1beta2-kyma,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.kyma-project.io,resources=kymas,verbs=create;update,versions=v1beta2,name=v1beta2.vkyma.kb.io,admissionReviewVersions=v1
+
+// KymaValidator rejects Kymas with modules that could otherwise only be reported in the module status
+// after the reconciliation.
+type KymaValidator struct {
+	// SyncEnabled is set if Kymas are synchronized to their runtime clusters,
+	// which is required for modules with a RemoteModuleTemplateRef.
+	SyncEnabled bool
+	// Modules validates the modules against the available ModuleTemplates. It is skipped if not set.
+	Modules KymaModuleValidator
+}
+
+var _ webhook.CustomValidator = &KymaValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (v *KymaValidator) ValidateCreate(ctx context.Context, obj machineryruntime.Object) (admission.Warnings, error) {
+	kyma, ok := obj.(*Kyma)
+	if !ok {
+		return nil, ErrTypeAssertKyma
+	}
+	logf.Log.WithName("kyma-resource").Info("validate create", "name", kyma.Name)
+	return nil, v.validate(ctx, kyma)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+// Updates that neither change the spec nor the labels enabling sync, beta or internal modules are accepted,
+// so a Kyma whose modules became invalid, e.g. because a ModuleTemplate was removed, can still be updated
+// by the reconciliation and deleted.
+func (v *KymaValidator) ValidateUpdate(ctx context.Context, oldObj, newObj machineryruntime.Object) (
+	admission.Warnings, error,
+) {
+	kyma, ok := newObj.(*Kyma)
+	if !ok {
+		return nil, ErrTypeAssertKyma
+	}
+	oldKyma, ok := oldObj.(*Kyma)
+	if !ok {
+		return nil, ErrTypeAssertKyma
+	}
+	logf.Log.WithName("kyma-resource").Info("validate update", "name", kyma.Name)
+	if !kyma.DeletionTimestamp.IsZero() ||
+		(equality.Semantic.DeepEqual(oldKyma.Spec, kyma.Spec) && !moduleLabelsChanged(oldKyma, kyma)) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, kyma)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (v *KymaValidator) ValidateDelete(_ context.Context, _ machineryruntime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *KymaValidator) validate(ctx context.Context, kyma *Kyma) error {
+	errs := v.validateModules(kyma)
+	if v.Modules != nil {
+		errs = append(errs, v.Modules.ValidateModules(ctx, kyma)...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: string(shared.KymaKind)},
+		kyma.Name, errs)
+}
+
+// validateModules rejects duplicate module names and remote ModuleTemplates of Kymas that are not synchronized.
+func (v *KymaValidator) validateModules(kyma *Kyma) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]bool, len(kyma.Spec.Modules))
+	for i, module := range kyma.Spec.Modules {
+		path := field.NewPath("spec").Child("modules").Index(i)
+		if names[module.Name] {
+			errs = append(errs, field.Duplicate(path.Child("name"), module.Name))
+		}
+		names[module.Name] = true
+		if module.RemoteModuleTemplateRef != "" && (!v.SyncEnabled || !kyma.HasSyncLabelEnabled()) {
+			errs = append(errs, field.Forbidden(path.Child("remoteModuleTemplateRef"),
+				"remote ModuleTemplates require the sync of the Kyma to be enabled"))
+		}
+	}
+	return errs
+}
+
+func moduleLabelsChanged(oldKyma, kyma *Kyma) bool {
+	for _, label := range []string{shared.SyncLabel, shared.BetaLabel, shared.InternalLabel} {
+		if oldKyma.Labels[label] != kyma.Labels[label] {
+			return true
+		}
+	}
+	return false
+}
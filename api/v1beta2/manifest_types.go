@@ -26,6 +26,8 @@ import (
 
 const (
 	RawManifestLayerName = "raw-manifest"
+	HelmChartLayerName   = "helm-chart"
+	KustomizeLayerName   = "kustomize"
 )
 
 // InstallInfo defines installation information.
@@ -91,7 +93,9 @@ type ImageSpec struct {
 type RefTypeMetadata string
 
 const (
-	OciRefType RefTypeMetadata = "oci-ref"
+	OciRefType    RefTypeMetadata = "oci-ref"
+	HelmChartType RefTypeMetadata = "helm-chart"
+	KustomizeType RefTypeMetadata = "kustomize"
 )
 
 // +kubebuilder:object:root=true
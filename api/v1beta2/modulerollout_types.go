@@ -0,0 +1,162 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+)
+
+// ModuleRolloutSpec defines the desired rollout of a module version across all Kymas.
+type ModuleRolloutSpec struct {
+	// Module is the name of the module that is rolled out, as used in the Kyma spec.
+	// +kubebuilder:validation:MinLength:=1
+	Module string `json:"module"`
+
+	// Channel restricts the rollout to the ModuleTemplate of the given channel. If empty, all channels are included.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// Version is the module version that is released in waves. Upgrades of Kymas to this version are deferred
+	// until the Kyma is part of a released wave. Changing the version restarts the rollout with the first wave.
+	// +kubebuilder:validation:MinLength:=1
+	Version string `json:"version"`
+
+	// Waves are released one after another. A Kyma belongs to the first wave it matches.
+	// Kymas matching no wave are upgraded once the rollout is completed.
+	// +kubebuilder:validation:MinItems:=1
+	Waves []RolloutWave `json:"waves"`
+
+	// WaveInterval is the minimum time between the release of two waves.
+	// +optional
+	WaveInterval apimetav1.Duration `json:"waveInterval,omitempty"`
+
+	// ErrorThreshold is the percentage of upgraded Kymas in which the module may be in the Error state
+	// or rolled back.
+	// The rollout is paused as soon as the threshold is exceeded. The default of 0 pauses the rollout
+	// on the first failed upgrade.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	ErrorThreshold int `json:"errorThreshold,omitempty"`
+
+	// Paused stops the release of further waves. It is set automatically if the ErrorThreshold is exceeded
+	// and has to be unset to continue the rollout.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// RolloutWave selects the Kymas that are upgraded together, by labels or by a percentage of all Kymas.
+type RolloutWave struct {
+	// Name identifies the wave in the status.
+	Name string `json:"name"`
+
+	// Selector selects the Kymas of the wave by their labels.
+	// +optional
+	Selector *apimetav1.LabelSelector `json:"selector,omitempty"`
+
+	// Percentage selects the given percentage of all Kymas, based on a stable hash of their names,
+	// so a higher percentage in a later wave includes the Kymas of the earlier waves.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	Percentage int `json:"percentage,omitempty"`
+}
+
+// ModuleRolloutStatus defines the observed progress of a ModuleRollout.
+type ModuleRolloutStatus struct {
+	// State is Processing while waves are rolled out, Warning while the rollout is paused,
+	// and Ready once all waves are released.
+	State shared.State `json:"state,omitempty"`
+
+	// Version is the version the progress is reported for.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// CurrentWave is the index of the last released wave.
+	// +optional
+	CurrentWave int `json:"currentWave"`
+
+	// LastWaveTransitionTime is the time the current wave was released.
+	// +optional
+	LastWaveTransitionTime apimetav1.Time `json:"lastWaveTransitionTime,omitempty"`
+
+	// ErrorRate is the percentage of upgraded Kymas in which the module is in the Error state
+	// or in which the upgrade was rolled back.
+	// +optional
+	ErrorRate int `json:"errorRate"`
+
+	// Waves reports the progress of every wave.
+	// +optional
+	Waves []RolloutWaveStatus `json:"waves,omitempty"`
+
+	// Message is a human-readable message indicating details about the State.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation of the ModuleRollout the status was calculated for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// RolloutWaveStatus reports the progress of a single wave.
+type RolloutWaveStatus struct {
+	Name string `json:"name"`
+	// Kymas is the number of Kymas with the module that belong to the wave.
+	Kymas int `json:"kymas"`
+	// Upgraded is the number of Kymas of the wave in which the module runs the version and is Ready.
+	Upgraded int `json:"upgraded"`
+	// Failed is the number of Kymas of the wave in which the module runs the version and is in the Error state,
+	// or in which the upgrade to the version was rolled back.
+	Failed int `json:"failed"`
+	// Deferred is the number of Kymas of the wave whose upgrade waits for their next maintenance window.
+	// They do not hold back the release of the next wave.
+	// +optional
+	Deferred int `json:"deferred,omitempty"`
+}
+
+// ModuleRollout releases a new version of a module in waves of Kymas.
+//
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Module",type="string",JSONPath=".spec.module"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.version"
+// +kubebuilder:printcolumn:name="Wave",type="integer",JSONPath=".status.currentWave"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type ModuleRollout struct {
+	apimetav1.TypeMeta   `json:",inline"`
+	apimetav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ModuleRolloutSpec   `json:"spec,omitempty"`
+	Status ModuleRolloutStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ModuleRolloutList contains a list of ModuleRollout.
+type ModuleRolloutList struct {
+	apimetav1.TypeMeta `json:",inline"`
+	apimetav1.ListMeta `json:"metadata,omitempty"`
+	Items              []ModuleRollout `json:"items"`
+}
+
+//nolint:gochecknoinits // registers ModuleRollout CRD on startup
+func init() {
+	SchemeBuilder.Register(&ModuleRollout{}, &ModuleRolloutList{})
+}
@@ -103,21 +103,66 @@ type ModuleTemplateSpec struct {
 	// This means for upgrades of the Descriptor, downstream controllers will also update the dependant modules
 	// (e.g. by updating the controller binary linked in a chart referenced in the descriptor)
 	//
-	// NOTE: Only Raw Rendering is Supported for the layers. So previously used "config" layers for the helm
+	// NOTE: Only Raw Rendering, packaged helm charts in a "helm-chart" layer and kustomization tarballs
+	// in a "kustomize" layer are supported for the layers. So previously used "config" layers for the helm
 	// charts and kustomize renderers are deprecated and ignored.
 	//
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Descriptor machineryruntime.RawExtension `json:"descriptor"`
 
 	CustomStateCheck []*CustomStateCheck `json:"customStateCheck,omitempty"`
+
+	// Dependencies are the modules that have to be enabled in the same Kyma and be Ready before this module
+	// is installed. The module is deleted before its dependencies.
+	// +optional
+	Dependencies []ModuleDependency `json:"dependencies,omitempty"`
+
+	// Timeouts define how long the installation and deletion of the module may take.
+	// +optional
+	Timeouts *ModuleTimeouts `json:"timeouts,omitempty"`
+}
+
+// ModuleTimeouts define the deadlines of the module operations. A Manifest that exceeds a deadline is reported
+// with a Warning event and the lifecycle_mgr_manifest_timeout_exceeded metric.
+type ModuleTimeouts struct {
+	// Install is the maximum duration for the module to become Ready after it is installed or upgraded,
+	// e.g. "10m". If it is exceeded, the Manifest is set to the Error state with the InstallationTimeout reason.
+	// +optional
+	Install *apimetav1.Duration `json:"install,omitempty"`
+
+	// Delete is the maximum duration for the deletion of the module, e.g. "5m".
+	// If it is exceeded, the Manifest is set to the Warning state with the DeletionTimeout reason.
+	// +optional
+	Delete *apimetav1.Duration `json:"delete,omitempty"`
+}
+
+// ModuleDependency references a module another module depends on.
+type ModuleDependency struct {
+	// Name is the name of the module, as used in the Kyma spec.
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Version is an optional semantic version constraint (e.g. ">=1.2.0 <2.0.0") that the
+	// installed version of the module has to satisfy.
+	// +optional
+	Version string `json:"version,omitempty"`
 }
 
 type CustomStateCheck struct {
 	// JSONPath specifies the JSON path to the state variable in the Module CR
-	JSONPath string `json:"jsonPath" yaml:"jsonPath"`
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty" yaml:"jsonPath,omitempty"`
 
 	// Value is the value at the JSONPath for which the Module CR state should map with MappedState
-	Value string `json:"value" yaml:"value"`
+	// +optional
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+
+	// Expression is a CEL expression that is evaluated against the Module CR, which is bound to the variable
+	// "object". If it evaluates to true, the Module CR state maps with MappedState. It is used instead of
+	// JSONPath and Value, e.g. to check conditions:
+	// object.status.conditions.exists(c, c.type == "Ready" && c.status == "True")
+	// +optional
+	Expression string `json:"expression,omitempty" yaml:"expression,omitempty"`
 
 	// MappedState is the Kyma CR State
 	MappedState shared.State `json:"mappedState" yaml:"mappedState"`
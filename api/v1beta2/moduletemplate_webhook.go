@@ -17,11 +17,13 @@ limitations under the License.
 package v1beta2
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	machineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -32,9 +34,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-func (m *ModuleTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+func (m *ModuleTemplate) SetupWebhookWithManager(mgr ctrl.Manager, validator *ModuleTemplateValidator) error {
 	err := ctrl.NewWebhookManagedBy(mgr).
 		For(m).
+		WithValidator(validator).
 		Complete()
 	if err != nil {
 		return fmt.Errorf("failed to setup webhook with manager for ModuleTemplate: %w", err)
@@ -42,30 +45,68 @@ func (m *ModuleTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return nil
 }
 
+// ModuleTemplateSchemaValidator validates the data and the customStateChecks of a ModuleTemplate
+// against the schema of the module CRD.
+type ModuleTemplateSchemaValidator interface {
+	ValidateSchema(ctx context.Context, template *ModuleTemplate) field.ErrorList
+}
+
 // +kubebuilder:webhook:path=/validate-operator-kyma-project-io-v1beta2-moduletemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.kyma-project.io,resources=moduletemplates,verbs=create;update,versions=v1beta2,name=v1beta2.vmoduletemplate.kb.io,admissionReviewVersions=v1
 
-var _ webhook.Validator = &ModuleTemplate{}
+// ModuleTemplateValidator rejects ModuleTemplates with an invalid descriptor, a decremented version
+// or customStateChecks that cannot determine the state of the module.
+type ModuleTemplateValidator struct {
+	// Schema validates the data and the customStateChecks against the module CRD. It is skipped if not set.
+	Schema ModuleTemplateSchemaValidator
+	// Expressions validates the expressions of the customStateChecks. It is skipped if not set.
+	Expressions StateCheckExpressionValidator
+}
+
+var _ webhook.CustomValidator = &ModuleTemplateValidator{}
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (m *ModuleTemplate) ValidateCreate() (admission.Warnings, error) {
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (v *ModuleTemplateValidator) ValidateCreate(ctx context.Context, obj machineryruntime.Object) (
+	admission.Warnings, error,
+) {
+	template, ok := obj.(*ModuleTemplate)
+	if !ok {
+		return nil, ErrTypeAssertModuleTemplate
+	}
 	logf.Log.WithName("moduletemplate-resource").
-		Info("validate create", "name", m.Name)
-	newDescriptor, err := m.descriptor()
+		Info("validate create", "name", template.Name)
+	if err := template.validateCustomStateChecks(v.Expressions); err != nil {
+		return nil, err
+	}
+	newDescriptor, err := template.descriptor()
 	if err != nil {
 		return nil, err
 	}
-	return nil, validate(nil, newDescriptor, m.Name)
+	if err := validate(nil, newDescriptor, template.Name); err != nil {
+		return nil, err
+	}
+	return nil, v.validateModule(ctx, template)
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (m *ModuleTemplate) ValidateUpdate(old machineryruntime.Object) (admission.Warnings, error) {
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+// The module is only validated against its CRD if the spec changed, so a ModuleTemplate
+// that became invalid, e.g. because the CRD was changed, can still be labeled or deleted.
+func (v *ModuleTemplateValidator) ValidateUpdate(ctx context.Context, oldObj, newObj machineryruntime.Object) (
+	admission.Warnings, error,
+) {
+	template, ok := newObj.(*ModuleTemplate)
+	if !ok {
+		return nil, ErrTypeAssertModuleTemplate
+	}
 	logf.Log.WithName("moduletemplate-resource").
-		Info("validate update", "name", m.Name)
-	newDescriptor, err := m.descriptor()
+		Info("validate update", "name", template.Name)
+	if err := template.validateCustomStateChecks(v.Expressions); err != nil {
+		return nil, err
+	}
+	newDescriptor, err := template.descriptor()
 	if err != nil {
 		return nil, err
 	}
-	oldTemplate, ok := old.(*ModuleTemplate)
+	oldTemplate, ok := oldObj.(*ModuleTemplate)
 	if !ok {
 		return nil, ErrTypeAssertModuleTemplate
 	}
@@ -73,14 +114,42 @@ func (m *ModuleTemplate) ValidateUpdate(old machineryruntime.Object) (admission.
 	if err != nil {
 		return nil, err
 	}
-	return nil, validate(oldDescriptor, newDescriptor, m.Name)
+	if err := validate(oldDescriptor, newDescriptor, template.Name); err != nil {
+		return nil, err
+	}
+	if equality.Semantic.DeepEqual(oldTemplate.Spec, template.Spec) {
+		return nil, nil
+	}
+	return nil, v.validateModule(ctx, template)
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
-func (m *ModuleTemplate) ValidateDelete() (admission.Warnings, error) {
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (v *ModuleTemplateValidator) ValidateDelete(_ context.Context, _ machineryruntime.Object) (
+	admission.Warnings, error,
+) {
 	return nil, nil
 }
 
+// validateModule rejects customStateChecks without a mapping to the Ready and Error state, as the state
+// of the module could not be determined with them, and modules not matching the schema of their CRD.
+func (v *ModuleTemplateValidator) validateModule(ctx context.Context, template *ModuleTemplate) error {
+	var errs field.ErrorList
+	if len(template.Spec.CustomStateCheck) > 0 {
+		for _, state := range MissingRequiredStates(template.Spec.CustomStateCheck) {
+			errs = append(errs, field.Required(field.NewPath("spec").Child("customStateCheck"),
+				fmt.Sprintf("a customStateCheck mapped to the %s state is required", state)))
+		}
+	}
+	if v.Schema != nil {
+		errs = append(errs, v.Schema.ValidateSchema(ctx, template)...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "ModuleTemplate"},
+		template.Name, errs)
+}
+
 func (m *ModuleTemplate) descriptor() (*Descriptor, error) {
 	obj := m.Spec.Descriptor.Object
 	if obj != nil {
@@ -125,6 +194,27 @@ func validate(oldDescriptor, newDescriptor *Descriptor, newTemplateName string)
 	return nil
 }
 
+// validateCustomStateChecks rejects customStateChecks without a JSONPath or with an Expression that does not compile,
+// as they would only fail once the module is installed.
+func (m *ModuleTemplate) validateCustomStateChecks(expressions StateCheckExpressionValidator) error {
+	var errs field.ErrorList
+	for i, stateCheck := range m.Spec.CustomStateCheck {
+		if stateCheck == nil {
+			continue
+		}
+		if err := stateCheck.Validate(expressions); err != nil {
+			errs = append(errs, field.Invalid(
+				field.NewPath("spec").Child("customStateCheck").Index(i).Child("expression"),
+				stateCheck.Expression, err.Error(),
+			))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "ModuleTemplate"}, m.Name, errs)
+}
+
 func validationErr(newTemplateName string, newVersion string, errMsg string) *apierrors.StatusError {
 	return apierrors.NewInvalid(
 		schema.GroupKind{Group: GroupVersion.Group, Kind: "ModuleTemplate"},
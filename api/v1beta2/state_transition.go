@@ -0,0 +1,78 @@
+package v1beta2
+
+import (
+	"strings"
+
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+)
+
+const (
+	// MaxKymaStateTransitions is the maximum number of transitions in the History of a Kyma.
+	MaxKymaStateTransitions = 20
+	// MaxModuleStateTransitions is the maximum number of transitions in the History of a Module.
+	MaxModuleStateTransitions = 10
+	// MaxStateTransitionReasonLength is the maximum length of the Reason of a StateTransition.
+	MaxStateTransitionReasonLength = 256
+)
+
+// StateTransition records a change of the State of a Kyma or a Module.
+type StateTransition struct {
+	// Time is the time the transition was observed at.
+	Time apimetav1.Time `json:"time"`
+
+	// From is the State before the transition. It is empty for the first State.
+	// +optional
+	From shared.State `json:"from,omitempty"`
+
+	// To is the State after the transition.
+	To shared.State `json:"to"`
+
+	// Reason is a human-readable message indicating details about the transition.
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Version is the version of the Module at the time of the transition.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// RecordStateTransition appends the transition from the previous State to the current State to the History
+// of the Kyma, if the State changed.
+func (status *KymaStatus) RecordStateTransition(previous shared.State, reason string) {
+	status.History = appendStateTransition(status.History, MaxKymaStateTransitions, StateTransition{
+		From:   previous,
+		To:     status.State,
+		Reason: reason,
+	})
+}
+
+// RecordStateTransition appends the transition from the previous State to the current State to the History
+// of the Module, if the State changed.
+func (status *ModuleStatus) RecordStateTransition(previous shared.State, reason string) {
+	status.History = appendStateTransition(status.History, MaxModuleStateTransitions, StateTransition{
+		From:    previous,
+		To:      status.State,
+		Reason:  reason,
+		Version: status.Version,
+	})
+}
+
+// appendStateTransition returns a copy of the history with the transition, limited to the last transitions.
+func appendStateTransition(history []StateTransition, limit int, transition StateTransition) []StateTransition {
+	if transition.From == transition.To {
+		return history
+	}
+	transition.Time = apimetav1.Now()
+	if len(transition.Reason) > MaxStateTransitionReasonLength {
+		transition.Reason = strings.ToValidUTF8(transition.Reason[:MaxStateTransitionReasonLength-3], "") + "..."
+	}
+	if len(history) >= limit {
+		history = history[len(history)-limit+1:]
+	}
+	updated := make([]StateTransition, 0, len(history)+1)
+	updated = append(updated, history...)
+	return append(updated, transition)
+}
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -52,22 +53,34 @@ import (
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
 	"github.com/kyma-project/lifecycle-manager/internal"
 	"github.com/kyma-project/lifecycle-manager/internal/controller"
+	declarativev2 "github.com/kyma-project/lifecycle-manager/internal/declarative/v2"
 	"github.com/kyma-project/lifecycle-manager/internal/descriptor/provider"
+	"github.com/kyma-project/lifecycle-manager/internal/manifest/layercache"
 	"github.com/kyma-project/lifecycle-manager/internal/pkg/flags"
 	"github.com/kyma-project/lifecycle-manager/internal/pkg/metrics"
+	"github.com/kyma-project/lifecycle-manager/pkg/cloudevents"
+	"github.com/kyma-project/lifecycle-manager/pkg/crdschema"
 	"github.com/kyma-project/lifecycle-manager/pkg/log"
 	"github.com/kyma-project/lifecycle-manager/pkg/matcher"
 	"github.com/kyma-project/lifecycle-manager/pkg/queue"
 	"github.com/kyma-project/lifecycle-manager/pkg/remote"
+	"github.com/kyma-project/lifecycle-manager/pkg/statecheck"
+	"github.com/kyma-project/lifecycle-manager/pkg/templatelookup"
+	"github.com/kyma-project/lifecycle-manager/pkg/tracing"
 	"github.com/kyma-project/lifecycle-manager/pkg/watcher"
 
 	_ "github.com/open-component-model/ocm/pkg/contexts/ocm"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	// embeds the time zone database for maintenance windows, as the container image has none.
+	_ "time/tzdata"
 	//nolint:gci // kubebuilder's scaffold imports must be appended here.
 	// +kubebuilder:scaffold:imports
 )
 
-const metricCleanupTimeout = 5 * time.Minute
+const (
+	metricCleanupTimeout   = 5 * time.Minute
+	tracingShutdownTimeout = 10 * time.Second
+)
 
 var (
 	scheme       = machineryruntime.NewScheme() //nolint:gochecknoglobals // scheme used to add CRDs
@@ -160,22 +173,38 @@ func setupManager(flagVar *flags.FlagVar, cacheOptions cache.Options, scheme *ma
 		setupKcpWatcherReconciler(mgr, options, flagVar)
 	}
 
-	remoteClientCache := remote.NewClientCache()
+	remoteClientCache := remote.NewClientCache(
+		remote.WithTTL(flagVar.RemoteClientCacheTTL),
+		remote.WithMaxEntries(flagVar.RemoteClientCacheMaxEntries),
+		remote.WithHealthProbe(flagVar.RemoteClientCacheProbeInterval, flagVar.RemoteClientCacheProbeTimeout),
+		remote.WithClientCacheMetrics(metrics.NewClientCacheMetrics()),
+	)
+	if err := mgr.Add(remoteClientCache); err != nil {
+		setupLog.Error(err, "unable to add remote client cache health probe to manager")
+		os.Exit(1)
+	}
+	remoteConfigProviders := remote.NewConfigProviders(
+		remote.NewClientWithConfig(mgr.GetClient(), mgr.GetConfig()), remoteConfigProviderOptions(flagVar))
 	sharedMetrics := metrics.NewSharedMetrics()
 	descriptorProvider := provider.NewCachedDescriptorProvider(nil)
 	kymaMetrics := metrics.NewKymaMetrics(sharedMetrics)
-	setupKymaReconciler(mgr, remoteClientCache, descriptorProvider, flagVar, options, skrWebhookManager, kymaMetrics)
-	setupManifestReconciler(mgr, flagVar, options, sharedMetrics)
+	setupKymaReconciler(mgr, remoteClientCache, remoteConfigProviders, descriptorProvider, flagVar, options,
+		skrWebhookManager, kymaMetrics, setupEventSink(mgr, flagVar))
+	layerCache := newLayerCache(flagVar)
+	setupManifestReconciler(mgr, remoteConfigProviders, layerCache, flagVar, options, sharedMetrics)
 	setupMandatoryModuleReconciler(mgr, descriptorProvider, flagVar, options)
 	setupMandatoryModuleDeletionReconciler(mgr, descriptorProvider, flagVar, options)
+	setupModuleRolloutReconciler(mgr, flagVar, options)
 
 	if flagVar.EnablePurgeFinalizer {
-		setupPurgeReconciler(mgr, remoteClientCache, flagVar, options)
+		setupPurgeReconciler(mgr, remoteClientCache, remoteConfigProviders, flagVar, options)
 	}
 	if flagVar.EnableWebhooks {
-		enableWebhooks(mgr)
+		enableWebhooks(mgr, descriptorProvider, flagVar)
 	}
 
+	setupTracing(mgr, flagVar)
+
 	addHealthChecks(mgr)
 	if flagVar.DropStoredVersion != "" {
 		go func(version string) {
@@ -220,14 +249,27 @@ func runKymaMetricsCleanup(kymaMetrics *metrics.KymaMetrics, kcpClient client.Cl
 	scheduler.StartAsync()
 }
 
-func enableWebhooks(mgr manager.Manager) {
+func enableWebhooks(mgr manager.Manager, descriptorProvider *provider.CachedDescriptorProvider,
+	flagVar *flags.FlagVar,
+) {
+	moduleTemplateValidator := &v1beta2.ModuleTemplateValidator{
+		Schema: crdschema.NewValidator(
+			crdschema.NewClusterLookup(mgr.GetAPIReader(), mgr.GetRESTMapper()),
+			crdschema.NewDescriptorLookup(mgr.GetClient(), descriptorProvider),
+		),
+		Expressions: statecheck.NewCompiler(),
+	}
 	if err := (&v1beta2.ModuleTemplate{}).
-		SetupWebhookWithManager(mgr); err != nil {
+		SetupWebhookWithManager(mgr, moduleTemplateValidator); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "ModuleTemplate")
 		os.Exit(1)
 	}
 
-	if err := (&v1beta2.Kyma{}).SetupWebhookWithManager(mgr); err != nil {
+	kymaValidator := &v1beta2.KymaValidator{
+		SyncEnabled: flagVar.InKCPMode,
+		Modules:     templatelookup.NewTemplateLookup(mgr.GetClient(), descriptorProvider, flagVar.InKCPMode),
+	}
+	if err := (&v1beta2.Kyma{}).SetupWebhookWithManager(mgr, kymaValidator); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Kyma")
 		os.Exit(1)
 	}
@@ -255,31 +297,50 @@ func controllerOptionsFromFlagVar(flagVar *flags.FlagVar) ctrlruntime.Options {
 	}
 }
 
+func remoteConfigProviderOptions(flagVar *flags.FlagVar) remote.ConfigProviderOptions {
+	var audiences []string
+	if flagVar.SkrTokenAudiences != "" {
+		audiences = strings.Split(flagVar.SkrTokenAudiences, ",")
+	}
+	return remote.ConfigProviderOptions{
+		KubeconfigDir:   flagVar.SkrKubeconfigDir,
+		TokenDir:        flagVar.SkrTokenDir,
+		TokenAudiences:  audiences,
+		TokenExpiration: flagVar.SkrTokenExpiration,
+	}
+}
+
 func setupKymaReconciler(mgr ctrl.Manager, remoteClientCache *remote.ClientCache,
+	remoteConfigProviders remote.ConfigProviders,
 	descriptorProvider *provider.CachedDescriptorProvider,
 	flagVar *flags.FlagVar, options ctrlruntime.Options, skrWebhookManager *watcher.SKRWebhookManifestManager,
-	kymaMetrics *metrics.KymaMetrics,
+	kymaMetrics *metrics.KymaMetrics, eventSink cloudevents.Sink,
 ) {
 	options.MaxConcurrentReconciles = flagVar.MaxConcurrentKymaReconciles
 	kcpRestConfig := mgr.GetConfig()
 
 	if err := (&controller.KymaReconciler{
-		Client:             mgr.GetClient(),
-		EventRecorder:      mgr.GetEventRecorderFor(shared.OperatorName),
-		KcpRestConfig:      kcpRestConfig,
-		RemoteClientCache:  remoteClientCache,
-		DescriptorProvider: descriptorProvider,
-		SKRWebhookManager:  skrWebhookManager,
+		Client:                mgr.GetClient(),
+		EventRecorder:         mgr.GetEventRecorderFor(shared.OperatorName),
+		KcpRestConfig:         kcpRestConfig,
+		RemoteClientCache:     remoteClientCache,
+		RemoteConfigProviders: remoteConfigProviders,
+		DescriptorProvider:    descriptorProvider,
+		SKRWebhookManager:     skrWebhookManager,
 		RequeueIntervals: queue.RequeueIntervals{
 			Success: flagVar.KymaRequeueSuccessInterval,
 			Busy:    flagVar.KymaRequeueBusyInterval,
 			Error:   flagVar.KymaRequeueErrInterval,
 			Warning: flagVar.KymaRequeueWarningInterval,
 		},
-		InKCPMode:           flagVar.InKCPMode,
-		RemoteSyncNamespace: flagVar.RemoteSyncNamespace,
-		IsManagedKyma:       flagVar.IsKymaManaged,
-		Metrics:             kymaMetrics,
+		InKCPMode:              flagVar.InKCPMode,
+		RemoteSyncNamespace:    flagVar.RemoteSyncNamespace,
+		IsManagedKyma:          flagVar.IsKymaManaged,
+		Metrics:                kymaMetrics,
+		SignatureName:          flagVar.VerificationSignatureName,
+		UpgradeRollbackTimeout: flagVar.ModuleUpgradeRollbackTimeout,
+		UpgradeRetryInterval:   flagVar.ModuleUpgradeRetryInterval,
+		EventSink:              eventSink,
 	}).SetupWithManager(
 		mgr, options, controller.SetupUpSetting{
 			ListenerAddr:                 flagVar.KymaListenerAddr,
@@ -292,6 +353,50 @@ func setupKymaReconciler(mgr ctrl.Manager, remoteClientCache *remote.ClientCache
 	}
 }
 
+// setupTracing registers the TracerProvider exporting the traces of the reconciliations, if an endpoint
+// is configured. The provider is shut down with the manager, so the remaining spans are flushed.
+func setupTracing(mgr ctrl.Manager, flagVar *flags.FlagVar) {
+	if flagVar.TracingEndpoint == "" {
+		return
+	}
+	provider, err := tracing.NewTracerProvider(context.Background(), tracing.Options{
+		Endpoint:      flagVar.TracingEndpoint,
+		Insecure:      flagVar.TracingInsecure,
+		SamplingRatio: flagVar.TracingSamplingRatio,
+		Version:       buildVersion,
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to flush traces: %w", err)
+		}
+		return nil
+	})); err != nil {
+		setupLog.Error(err, "unable to add tracer provider to manager")
+		os.Exit(1)
+	}
+}
+
+// setupEventSink creates the sink that publishes the lifecycle events as CloudEvents, if an endpoint is configured.
+func setupEventSink(mgr ctrl.Manager, flagVar *flags.FlagVar) cloudevents.Sink {
+	if flagVar.CloudEventsSinkURL == "" {
+		return cloudevents.NopSink{}
+	}
+	sink := cloudevents.NewHTTPSink(flagVar.CloudEventsSinkURL, flagVar.CloudEventsBufferSize,
+		flagVar.CloudEventsMaxRetries, flagVar.CloudEventsRetryInterval)
+	if err := mgr.Add(sink); err != nil {
+		setupLog.Error(err, "unable to add cloud events sink to manager")
+		os.Exit(1)
+	}
+	return sink
+}
+
 func createSkrWebhookManager(mgr ctrl.Manager, flagVar *flags.FlagVar) (*watcher.SKRWebhookManifestManager, error) {
 	caCertificateCache := watcher.NewCACertificateCache(flagVar.CaCertCacheTTL)
 	config := watcher.SkrWebhookManagerConfig{
@@ -337,12 +442,18 @@ func getWatcherImg(flagVar *flags.FlagVar) string {
 
 func setupPurgeReconciler(mgr ctrl.Manager,
 	remoteClientCache *remote.ClientCache,
+	remoteConfigProviders remote.ConfigProviders,
 	flagVar *flags.FlagVar,
 	options ctrlruntime.Options,
 ) {
 	resolveRemoteClientFunc := func(ctx context.Context, key client.ObjectKey) (client.Client, error) {
 		kcpClient := remote.NewClientWithConfig(mgr.GetClient(), mgr.GetConfig())
-		return remote.NewClientLookup(kcpClient, remoteClientCache, v1beta2.SyncStrategyLocalSecret).Lookup(ctx, key)
+		kyma := &v1beta2.Kyma{}
+		if err := kcpClient.Get(ctx, key, kyma); err != nil {
+			return nil, fmt.Errorf("failed to get kyma %s: %w", key, err)
+		}
+		return remote.NewClientLookup(kcpClient, remoteClientCache, remote.SyncStrategyFor(kyma),
+			remoteConfigProviders).Lookup(ctx, key)
 	}
 
 	if err := (&controller.PurgeReconciler{
@@ -361,7 +472,22 @@ func setupPurgeReconciler(mgr ctrl.Manager,
 	}
 }
 
-func setupManifestReconciler(mgr ctrl.Manager, flagVar *flags.FlagVar, options ctrlruntime.Options,
+// newLayerCache creates the layer cache of the Manifest reconciler.
+func newLayerCache(flagVar *flags.FlagVar) *layercache.Cache {
+	layerCacheDir := flagVar.LayerCacheDir
+	if layerCacheDir == "" {
+		layerCacheDir = filepath.Join(os.TempDir(), "layer-cache")
+	}
+	layerCache, err := layercache.New(layerCacheDir, flagVar.LayerCacheMaxSize, metrics.NewLayerCacheMetrics())
+	if err != nil {
+		setupLog.Error(err, "unable to create layer cache")
+		os.Exit(1)
+	}
+	return layerCache
+}
+
+func setupManifestReconciler(mgr ctrl.Manager, remoteConfigProviders remote.ConfigProviders,
+	layerCache *layercache.Cache, flagVar *flags.FlagVar, options ctrlruntime.Options,
 	sharedMetrics *metrics.SharedMetrics,
 ) {
 	options.MaxConcurrentReconciles = flagVar.MaxConcurrentManifestReconciles
@@ -375,6 +501,10 @@ func setupManifestReconciler(mgr ctrl.Manager, flagVar *flags.FlagVar, options c
 		}, controller.SetupUpSetting{
 			ListenerAddr:                 flagVar.ManifestListenerAddr,
 			EnableDomainNameVerification: flagVar.EnableDomainNameVerification,
+			EnableCosignVerification:     flagVar.EnableCosignVerification,
+			LayerCache:                   layerCache,
+			RemoteConfigProviders:        remoteConfigProviders,
+			DriftDetection:               declarativev2.DriftDetectionMode(flagVar.ManifestDriftDetection),
 		}, metrics.NewManifestMetrics(sharedMetrics),
 	); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Manifest")
@@ -403,6 +533,22 @@ func setupKcpWatcherReconciler(mgr ctrl.Manager, options ctrlruntime.Options, fl
 	}
 }
 
+func setupModuleRolloutReconciler(mgr ctrl.Manager, flagVar *flags.FlagVar, options ctrlruntime.Options) {
+	if err := (&controller.ModuleRolloutReconciler{
+		Client:        mgr.GetClient(),
+		EventRecorder: mgr.GetEventRecorderFor(shared.OperatorName),
+		RequeueIntervals: queue.RequeueIntervals{
+			Success: flagVar.ModuleRolloutRequeueSuccessInterval,
+			Busy:    flagVar.KymaRequeueBusyInterval,
+			Error:   flagVar.KymaRequeueErrInterval,
+			Warning: flagVar.KymaRequeueWarningInterval,
+		},
+	}).SetupWithManager(mgr, options); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", controller.ModuleRolloutControllerName)
+		os.Exit(1)
+	}
+}
+
 func setupMandatoryModuleReconciler(mgr ctrl.Manager, descriptorProvider *provider.CachedDescriptorProvider,
 	flagVar *flags.FlagVar, options ctrlruntime.Options,
 ) {
@@ -420,6 +566,7 @@ func setupMandatoryModuleReconciler(mgr ctrl.Manager, descriptorProvider *provid
 		RemoteSyncNamespace: flagVar.RemoteSyncNamespace,
 		InKCPMode:           flagVar.InKCPMode,
 		DescriptorProvider:  descriptorProvider,
+		SignatureName:       flagVar.VerificationSignatureName,
 	}).SetupWithManager(mgr, options); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MandatoryModule")
 		os.Exit(1)
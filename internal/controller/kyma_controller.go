@@ -20,6 +20,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -37,14 +39,18 @@ import (
 	"github.com/kyma-project/lifecycle-manager/internal/descriptor/provider"
 	"github.com/kyma-project/lifecycle-manager/internal/pkg/metrics"
 	"github.com/kyma-project/lifecycle-manager/pkg/adapter"
+	"github.com/kyma-project/lifecycle-manager/pkg/cloudevents"
 	"github.com/kyma-project/lifecycle-manager/pkg/log"
+	"github.com/kyma-project/lifecycle-manager/pkg/maintenancewindows"
 	"github.com/kyma-project/lifecycle-manager/pkg/module/common"
 	"github.com/kyma-project/lifecycle-manager/pkg/module/parse"
 	"github.com/kyma-project/lifecycle-manager/pkg/module/sync"
 	"github.com/kyma-project/lifecycle-manager/pkg/queue"
 	"github.com/kyma-project/lifecycle-manager/pkg/remote"
+	"github.com/kyma-project/lifecycle-manager/pkg/rollout"
 	"github.com/kyma-project/lifecycle-manager/pkg/status"
 	"github.com/kyma-project/lifecycle-manager/pkg/templatelookup"
+	"github.com/kyma-project/lifecycle-manager/pkg/tracing"
 	"github.com/kyma-project/lifecycle-manager/pkg/util"
 	"github.com/kyma-project/lifecycle-manager/pkg/watcher"
 )
@@ -69,14 +75,26 @@ type KymaReconciler struct {
 	client.Client
 	record.EventRecorder
 	queue.RequeueIntervals
-	DescriptorProvider  *provider.CachedDescriptorProvider
-	SKRWebhookManager   *watcher.SKRWebhookManifestManager
-	KcpRestConfig       *rest.Config
-	RemoteClientCache   *remote.ClientCache
-	InKCPMode           bool
-	RemoteSyncNamespace string
-	IsManagedKyma       bool
-	Metrics             *metrics.KymaMetrics
+	DescriptorProvider *provider.CachedDescriptorProvider
+	SKRWebhookManager  *watcher.SKRWebhookManifestManager
+	KcpRestConfig      *rest.Config
+	RemoteClientCache  *remote.ClientCache
+	// RemoteConfigProviders resolve the access to the runtime cluster according to the sync strategy of a Kyma.
+	RemoteConfigProviders remote.ConfigProviders
+	InKCPMode             bool
+	RemoteSyncNamespace   string
+	IsManagedKyma         bool
+	Metrics               *metrics.KymaMetrics
+	// SignatureName is the name of the signature all module descriptors are verified against.
+	// Verification is disabled if it is empty and the ModuleTemplate is not labeled with a signature.
+	SignatureName string
+	// UpgradeRollbackTimeout is the time after which a module upgrade that is not Ready is rolled back.
+	// Automatic rollbacks are disabled if it is 0.
+	UpgradeRollbackTimeout time.Duration
+	// UpgradeRetryInterval is the time after which a rolled back module upgrade is retried.
+	UpgradeRetryInterval time.Duration
+	// EventSink publishes the lifecycle events of the Kyma and its modules. They are discarded if it is nil.
+	EventSink cloudevents.Sink
 }
 
 // +kubebuilder:rbac:groups=operator.kyma-project.io,resources=kymas,verbs=get;list;watch;create;update;patch;delete
@@ -85,19 +103,29 @@ type KymaReconciler struct {
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch;get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=operator.kyma-project.io,resources=moduletemplates,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=operator.kyma-project.io,resources=moduletemplates/finalizers,verbs=update
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=modulerollouts,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cert-manager.io,resources=issuers,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;create;update;delete;patch;watch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=update
 
 func (r *KymaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.Start(ctx, "Kyma.Reconcile", tracing.KymaAttributes(req.NamespacedName))
+	result, err := r.reconcileRequest(ctx, req)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *KymaReconciler) reconcileRequest(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := logf.FromContext(ctx)
 	logger.V(log.DebugLevel).Info("Kyma reconciliation started")
 
 	ctx = adapter.ContextWithRecorder(ctx, r.EventRecorder)
+	ctx = adapter.ContextWithPendingEvents(adapter.ContextWithEventSink(ctx, r.EventSink))
 
 	kyma := &v1beta2.Kyma{}
 	if err := r.Get(ctx, req.NamespacedName, kyma); err != nil {
@@ -165,7 +193,7 @@ func (r *KymaReconciler) getSyncedContext(ctx context.Context, kyma *v1beta2.Kym
 
 	remoteClient := remote.NewClientWithConfig(r.Client, r.KcpRestConfig)
 	ctxWithSync, err := remote.InitializeSyncContext(ctx, kyma,
-		r.RemoteSyncNamespace, remoteClient, r.RemoteClientCache)
+		r.RemoteSyncNamespace, remoteClient, r.RemoteClientCache, r.RemoteConfigProviders)
 	if err != nil {
 		return ctx, err
 	}
@@ -360,6 +388,12 @@ func (r *KymaReconciler) handleInitialState(ctx context.Context, kyma *v1beta2.K
 }
 
 func (r *KymaReconciler) handleProcessingState(ctx context.Context, kyma *v1beta2.Kyma) (ctrl.Result, error) {
+	if kyma.IsPlanRequested() {
+		return r.handlePlan(ctx, kyma)
+	}
+	// the plan is only kept while it is requested, as it is outdated as soon as it is applied
+	kyma.Status.Plan = nil
+
 	logger := logf.FromContext(ctx)
 	var errGroup errgroup.Group
 	errGroup.Go(func() error {
@@ -375,6 +409,29 @@ func (r *KymaReconciler) handleProcessingState(ctx context.Context, kyma *v1beta
 		}
 		return nil
 	})
+	r.syncRemote(ctx, kyma, &errGroup)
+
+	if err := errGroup.Wait(); err != nil {
+		return r.requeueWithError(ctx, kyma, err)
+	}
+
+	state := kyma.DetermineState()
+	requeueInterval := queue.DetermineRequeueInterval(state, r.RequeueIntervals)
+	if state == shared.StateReady {
+		const msg = "kyma is ready"
+		if kyma.Status.State != shared.StateReady {
+			logger.Info(msg)
+		}
+		return ctrl.Result{RequeueAfter: requeueInterval}, r.updateStatus(ctx, kyma, state, msg)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueInterval},
+		r.updateStatus(ctx, kyma, state, "waiting for all modules to become ready")
+}
+
+// syncRemote adds the synchronization of the module catalog and the installation of the SKR webhook to the group.
+// Both also run while the module reconciliation is suspended by the plan annotation.
+func (r *KymaReconciler) syncRemote(ctx context.Context, kyma *v1beta2.Kyma, errGroup *errgroup.Group) {
 	if r.SyncKymaEnabled(kyma) {
 		errGroup.Go(func() error {
 			if err := r.syncModuleCatalog(ctx, kyma); err != nil {
@@ -401,23 +458,41 @@ func (r *KymaReconciler) handleProcessingState(ctx context.Context, kyma *v1beta
 			return nil
 		})
 	}
+}
 
+// handlePlan resolves and parses the ModuleTemplates of the Kyma like handleProcessingState, but instead of
+// applying the resulting Manifests, it records the planned changes in the Kyma status.
+// The module catalog and the SKR webhook are still synchronized, only the Manifests are not created, patched or deleted.
+func (r *KymaReconciler) handlePlan(ctx context.Context, kyma *v1beta2.Kyma) (ctrl.Result, error) {
+	var errGroup errgroup.Group
+	r.syncRemote(ctx, kyma, &errGroup)
 	if err := errGroup.Wait(); err != nil {
 		return r.requeueWithError(ctx, kyma, err)
 	}
 
-	state := kyma.DetermineState()
-	requeueInterval := queue.DetermineRequeueInterval(state, r.RequeueIntervals)
-	if state == shared.StateReady {
-		const msg = "kyma is ready"
-		if kyma.Status.State != shared.StateReady {
-			logger.Info(msg)
-		}
-		return ctrl.Result{RequeueAfter: requeueInterval}, r.updateStatus(ctx, kyma, state, msg)
+	modules, err := r.GenerateModulesFromTemplate(ctx, kyma)
+	if err != nil {
+		r.Metrics.RecordRequeueReason(metrics.KymaPlan, queue.UnexpectedRequeue)
+		return r.requeueWithError(ctx, kyma, fmt.Errorf("error while fetching modules during planning: %w", err))
 	}
 
-	return ctrl.Result{RequeueAfter: requeueInterval},
-		r.updateStatus(ctx, kyma, state, "waiting for all modules to become ready")
+	plans, err := sync.New(r).PlanManifests(ctx, kyma, modules)
+	if err != nil {
+		r.Metrics.RecordRequeueReason(metrics.KymaPlan, queue.UnexpectedRequeue)
+		return r.requeueWithError(ctx, kyma, fmt.Errorf("could not plan manifests: %w", err))
+	}
+
+	kyma.Status.Plan = &v1beta2.KymaPlan{
+		ObservedGeneration: kyma.GetGeneration(),
+		LastPlanTime:       apimetav1.NewTime(time.Now()),
+		Modules:            plans,
+	}
+	const msg = "module reconciliation suspended by plan annotation"
+	if err := r.updateStatus(ctx, kyma, kyma.Status.State, msg); err != nil {
+		r.Metrics.RecordRequeueReason(metrics.KymaPlan, queue.UnexpectedRequeue)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: r.RequeueIntervals.Success}, nil
 }
 
 func (r *KymaReconciler) handleDeletingState(ctx context.Context, kyma *v1beta2.Kyma) (ctrl.Result, error) {
@@ -480,7 +555,8 @@ func (r *KymaReconciler) cleanupManifestCRs(ctx context.Context, kyma *v1beta2.K
 		return nil
 	}
 
-	if err = r.deleteManifests(ctx, relatedManifests); err != nil {
+	// modules are deleted before the modules they depend on, the remaining ones in a later reconciliation
+	if err = r.deleteManifests(ctx, sync.DeletableManifests(relatedManifests)); err != nil {
 		return fmt.Errorf("error while trying to delete manifests: %w", err)
 	}
 	return ErrManifestsStillExist
@@ -531,8 +607,12 @@ func (r *KymaReconciler) reconcileManifests(ctx context.Context, kyma *v1beta2.K
 	if err != nil {
 		return fmt.Errorf("error while fetching modules during processing: %w", err)
 	}
+	if err := r.deferUpgrades(ctx, kyma, modules); err != nil {
+		return fmt.Errorf("error while deferring module upgrades: %w", err)
+	}
 
 	runner := sync.New(r)
+	runner.RollbackTimeout = r.UpgradeRollbackTimeout
 
 	if err := runner.ReconcileManifests(ctx, kyma, modules); err != nil {
 		return fmt.Errorf("sync failed: %w", err)
@@ -546,6 +626,115 @@ func (r *KymaReconciler) reconcileManifests(ctx context.Context, kyma *v1beta2.K
 	return nil
 }
 
+// deferUpgrades defers the version-changing upgrades of installed modules until a maintenance window of the Kyma
+// is open, until the ModuleRollout of the new version releases it to the Kyma, and until the retry of a rolled back
+// upgrade.
+func (r *KymaReconciler) deferUpgrades(ctx context.Context, kyma *v1beta2.Kyma, modules common.Modules) error {
+	upgrades := upgradingModules(kyma, modules)
+	if len(upgrades) == 0 {
+		return nil
+	}
+	deferFailedUpgrades(kyma, upgrades, time.Now(), r.UpgradeRetryInterval)
+	if err := deferToMaintenanceWindows(kyma, upgrades, time.Now()); err != nil {
+		return err
+	}
+	moduleRollouts := &v1beta2.ModuleRolloutList{}
+	if err := r.List(ctx, moduleRollouts); err != nil {
+		return fmt.Errorf("failed to list module rollouts: %w", err)
+	}
+	return deferToModuleRollouts(kyma, upgrades, moduleRollouts.Items)
+}
+
+// upgradingModules returns the enabled modules whose installed version differs from the resolved version.
+func upgradingModules(kyma *v1beta2.Kyma, modules common.Modules) common.Modules {
+	moduleStatusMap := kyma.GetModuleStatusMap()
+	var upgrades common.Modules
+	for _, module := range modules {
+		if !module.Enabled || module.Template.Err != nil {
+			continue
+		}
+		moduleStatus, found := moduleStatusMap[module.ModuleName]
+		if !found || moduleStatus.Manifest == nil || moduleStatus.Version == "" ||
+			moduleStatus.Version == module.Manifest.Spec.Version {
+			continue
+		}
+		upgrades = append(upgrades, module)
+	}
+	return upgrades
+}
+
+// deferFailedUpgrades keeps the failed upgrade of a module in the status as long as its version is desired
+// and defers the upgrade until the retry interval has passed since the rollback.
+func deferFailedUpgrades(kyma *v1beta2.Kyma, upgrades common.Modules, now time.Time, retryInterval time.Duration) {
+	moduleStatusMap := kyma.GetModuleStatusMap()
+	for _, module := range upgrades {
+		failedUpgrade := moduleStatusMap[module.ModuleName].FailedUpgrade
+		if failedUpgrade == nil || failedUpgrade.Version != module.Manifest.Spec.Version {
+			continue
+		}
+		module.FailedUpgrade = failedUpgrade
+		if retryAfter := failedUpgrade.Time.Add(retryInterval); now.Before(retryAfter) {
+			deferredUpgrade(module).RetryAfter = &apimetav1.Time{Time: retryAfter}
+		}
+	}
+}
+
+// deferToMaintenanceWindows defers the upgrades if the Kyma has maintenance windows and none of them is open.
+func deferToMaintenanceWindows(kyma *v1beta2.Kyma, upgrades common.Modules, now time.Time) error {
+	if len(kyma.Spec.MaintenanceWindows) == 0 {
+		return nil
+	}
+	open, err := maintenancewindows.IsOpen(kyma.Spec.MaintenanceWindows, now)
+	if err != nil {
+		return fmt.Errorf("failed to check maintenance windows: %w", err)
+	}
+	if open {
+		return nil
+	}
+	nextWindow, err := maintenancewindows.NextOpening(kyma.Spec.MaintenanceWindows, now)
+	if err != nil {
+		return fmt.Errorf("failed to determine next maintenance window: %w", err)
+	}
+	for _, module := range upgrades {
+		deferredUpgrade(module).NextWindow = &apimetav1.Time{Time: nextWindow}
+	}
+	return nil
+}
+
+// deferToModuleRollouts defers the upgrades to versions that are rolled out in waves
+// as long as the wave of the Kyma is not released.
+func deferToModuleRollouts(kyma *v1beta2.Kyma, upgrades common.Modules,
+	moduleRollouts []v1beta2.ModuleRollout,
+) error {
+	for _, module := range upgrades {
+		for i := range moduleRollouts {
+			moduleRollout := &moduleRollouts[i]
+			if !rollout.Applies(moduleRollout, module.ModuleName, module.Template.Spec.Channel,
+				module.Manifest.Spec.Version) {
+				continue
+			}
+			released, err := rollout.IsReleased(moduleRollout, kyma)
+			if err != nil {
+				return fmt.Errorf("failed to check module rollout: %w", err)
+			}
+			if !released {
+				deferredUpgrade(module).Rollout = moduleRollout.Name
+			}
+		}
+	}
+	return nil
+}
+
+func deferredUpgrade(module *common.Module) *v1beta2.DeferredUpgrade {
+	if module.DeferredUpgrade == nil {
+		module.DeferredUpgrade = &v1beta2.DeferredUpgrade{
+			Version: module.Manifest.Spec.Version,
+			Channel: module.Template.Spec.Channel,
+		}
+	}
+	return module.DeferredUpgrade
+}
+
 func (r *KymaReconciler) syncModuleCatalog(ctx context.Context, kyma *v1beta2.Kyma) error {
 	moduleTemplateList := &v1beta2.ModuleTemplateList{}
 	if err := r.List(ctx, moduleTemplateList, &client.ListOptions{}); err != nil {
@@ -591,8 +780,10 @@ func (r *KymaReconciler) GenerateModulesFromTemplate(ctx context.Context, kyma *
 			r.enqueueWarningEvent(kyma, moduleReconciliationError, template.Err)
 		}
 	}
-	parser := parse.NewParser(r.Client, r.DescriptorProvider, r.InKCPMode, r.RemoteSyncNamespace)
-	return parser.GenerateModulesFromTemplates(kyma, templates), nil
+	parser := parse.NewParser(r.Client, r.DescriptorProvider, r.InKCPMode, r.RemoteSyncNamespace, r.SignatureName)
+	modules := parser.GenerateModulesFromTemplates(ctx, kyma, templates)
+	sync.ResolveDependencies(modules)
+	return modules, nil
 }
 
 func (r *KymaReconciler) DeleteNoLongerExistingModules(ctx context.Context, kyma *v1beta2.Kyma) error {
@@ -601,11 +792,22 @@ func (r *KymaReconciler) DeleteNoLongerExistingModules(ctx context.Context, kyma
 	if len(moduleStatus) == 0 {
 		return nil
 	}
+	relatedManifests, err := r.getRelatedManifestCRs(ctx, kyma)
+	if err != nil {
+		return fmt.Errorf("error while trying to get manifests: %w", err)
+	}
+	dependents := sync.DependentModules(relatedManifests)
 	for i := range moduleStatus {
 		moduleStatus := moduleStatus[i]
 		if moduleStatus.Manifest == nil {
 			continue
 		}
+		// a module is only deleted once no other module depends on it anymore
+		if moduleDependents := dependents[moduleStatus.Name]; len(moduleDependents) > 0 {
+			moduleStatus.Message = fmt.Sprintf("module is still required by %s",
+				strings.Join(moduleDependents, ", "))
+			continue
+		}
 		err = r.deleteManifest(ctx, moduleStatus.Manifest)
 	}
 
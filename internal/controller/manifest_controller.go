@@ -70,30 +70,32 @@ func SetupWithManager(mgr manager.Manager,
 		).WithOptions(options)
 
 	if err := controllerManagedByManager.Complete(ManifestReconciler(mgr, requeueIntervals,
-		manifestMetrics)); err != nil {
+		manifestMetrics, settings)); err != nil {
 		return fmt.Errorf("failed to initialize manifest controller by manager: %w", err)
 	}
 	return nil
 }
 
 func ManifestReconciler(mgr manager.Manager, requeueIntervals queue.RequeueIntervals,
-	manifestMetrics *metrics.ManifestMetrics,
+	manifestMetrics *metrics.ManifestMetrics, settings SetupUpSetting,
 ) *declarativev2.Reconciler {
 	kcp := &declarativev2.ClusterInfo{
 		Client: mgr.GetClient(),
 		Config: mgr.GetConfig(),
 	}
-	lookup := &manifest.RemoteClusterLookup{KCP: kcp}
+	lookup := &manifest.RemoteClusterLookup{KCP: kcp, ConfigProviders: settings.RemoteConfigProviders}
 	return declarativev2.NewFromManager(
 		mgr, &v1beta2.Manifest{}, requeueIntervals, manifestMetrics,
 		declarativev2.WithSpecResolver(
-			manifest.NewSpecResolver(kcp),
+			manifest.NewSpecResolver(kcp, settings.LayerCache, settings.EnableCosignVerification),
 		),
 		declarativev2.WithCustomReadyCheck(manifest.NewCustomResourceReadyCheck()),
 		declarativev2.WithRemoteTargetCluster(lookup.ConfigResolver),
 		manifest.WithClientCacheKey(),
 		declarativev2.WithPostRun{manifest.PostRunCreateCR},
 		declarativev2.WithPreDelete{manifest.PreDeleteDeleteCR},
+		declarativev2.WithDryRunResources(manifest.DryRunModuleCR),
 		declarativev2.WithModuleCRDeletionCheck(manifest.NewModuleCRDeletionCheck()),
+		declarativev2.WithDriftDetection(settings.DriftDetection),
 	)
 }
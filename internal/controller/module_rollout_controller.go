@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/log"
+	"github.com/kyma-project/lifecycle-manager/pkg/queue"
+	"github.com/kyma-project/lifecycle-manager/pkg/rollout"
+	"github.com/kyma-project/lifecycle-manager/pkg/status"
+	"github.com/kyma-project/lifecycle-manager/pkg/util"
+)
+
+// ModuleRolloutReconciler releases the version of a ModuleRollout wave by wave and pauses the rollout
+// if too many upgraded Kymas report the module in the Error state.
+type ModuleRolloutReconciler struct {
+	client.Client
+	record.EventRecorder
+	queue.RequeueIntervals
+}
+
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=modulerollouts,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=modulerollouts/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=kymas,verbs=get;list;watch
+
+func (r *ModuleRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	logger.V(log.DebugLevel).Info("ModuleRollout reconciliation started")
+
+	moduleRollout := &v1beta2.ModuleRollout{}
+	if err := r.Get(ctx, req.NamespacedName, moduleRollout); err != nil {
+		if util.IsNotFound(err) {
+			return ctrl.Result{Requeue: false}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("moduleRolloutController: %w", err)
+	}
+	if !moduleRollout.DeletionTimestamp.IsZero() {
+		return ctrl.Result{Requeue: false}, nil
+	}
+
+	if len(moduleRollout.Spec.Waves) == 0 {
+		return r.updateRolloutStatus(ctx, moduleRollout, shared.StateError, "rollout has no waves")
+	}
+	if moduleRollout.Status.Version != moduleRollout.Spec.Version {
+		moduleRollout.Status = v1beta2.ModuleRolloutStatus{
+			Version:                moduleRollout.Spec.Version,
+			LastWaveTransitionTime: apimetav1.Now(),
+		}
+	}
+	if moduleRollout.Status.CurrentWave >= len(moduleRollout.Spec.Waves) {
+		moduleRollout.Status.CurrentWave = len(moduleRollout.Spec.Waves) - 1
+	}
+
+	kymas := &v1beta2.KymaList{}
+	if err := r.List(ctx, kymas); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list kymas for rollout: %w", err)
+	}
+	waves, errorRate, err := rollout.Progress(moduleRollout, kymas.Items)
+	if err != nil {
+		return r.updateRolloutStatus(ctx, moduleRollout, shared.StateError, err.Error())
+	}
+	moduleRollout.Status.Waves = waves
+	moduleRollout.Status.ErrorRate = errorRate
+
+	if rollout.IsCompleted(moduleRollout) {
+		return r.updateRolloutStatus(ctx, moduleRollout, shared.StateReady, "rollout is completed")
+	}
+	if !moduleRollout.Spec.Paused && errorRate > moduleRollout.Spec.ErrorThreshold {
+		return r.pause(ctx, moduleRollout)
+	}
+	if moduleRollout.Spec.Paused {
+		return r.updateRolloutStatus(ctx, moduleRollout, shared.StateWarning,
+			fmt.Sprintf("rollout is paused in wave %s", r.currentWaveName(moduleRollout)))
+	}
+	return r.progress(ctx, moduleRollout)
+}
+
+// progress releases the next wave once all Kymas of the current wave run the version, failed the upgrade,
+// or wait for their maintenance window, and the wave interval has passed. It completes the rollout after the last wave.
+func (r *ModuleRolloutReconciler) progress(ctx context.Context,
+	moduleRollout *v1beta2.ModuleRollout,
+) (ctrl.Result, error) {
+	current := moduleRollout.Status.Waves[moduleRollout.Status.CurrentWave]
+	if pending := current.Kymas - current.Upgraded - current.Failed - current.Deferred; pending > 0 {
+		return r.updateRolloutStatus(ctx, moduleRollout, shared.StateProcessing,
+			fmt.Sprintf("waiting for %d of %d kymas in wave %s to be upgraded", pending, current.Kymas, current.Name))
+	}
+	nextRelease := moduleRollout.Status.LastWaveTransitionTime.Add(moduleRollout.Spec.WaveInterval.Duration)
+	if time.Now().Before(nextRelease) {
+		return r.updateRolloutStatus(ctx, moduleRollout, shared.StateProcessing,
+			fmt.Sprintf("wave %s is upgraded, next wave is released at %s", current.Name,
+				nextRelease.UTC().Format(time.RFC3339)))
+	}
+	if moduleRollout.Status.CurrentWave == len(moduleRollout.Spec.Waves)-1 {
+		r.Event(moduleRollout, "Normal", "RolloutCompleted",
+			fmt.Sprintf("version %s is released to all kymas", moduleRollout.Spec.Version))
+		return r.updateRolloutStatus(ctx, moduleRollout, shared.StateReady, "rollout is completed")
+	}
+	moduleRollout.Status.CurrentWave++
+	moduleRollout.Status.LastWaveTransitionTime = apimetav1.Now()
+	r.Event(moduleRollout, "Normal", "WaveReleased",
+		fmt.Sprintf("version %s is released to wave %s", moduleRollout.Spec.Version,
+			r.currentWaveName(moduleRollout)))
+	return r.updateRolloutStatus(ctx, moduleRollout, shared.StateProcessing,
+		fmt.Sprintf("wave %s is released", r.currentWaveName(moduleRollout)))
+}
+
+// pause stops the rollout by setting spec.paused, so it has to be resumed explicitly once the errors are resolved.
+func (r *ModuleRolloutReconciler) pause(ctx context.Context,
+	moduleRollout *v1beta2.ModuleRollout,
+) (ctrl.Result, error) {
+	message := fmt.Sprintf("rollout is paused in wave %s, error rate of %d%% exceeds the threshold of %d%%",
+		r.currentWaveName(moduleRollout), moduleRollout.Status.ErrorRate, moduleRollout.Spec.ErrorThreshold)
+	r.Event(moduleRollout, "Warning", "RolloutPaused", message)
+	rolloutStatus := moduleRollout.Status
+	moduleRollout.Spec.Paused = true
+	if err := r.Update(ctx, moduleRollout); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to pause rollout: %w", err)
+	}
+	moduleRollout.Status = rolloutStatus
+	return r.updateRolloutStatus(ctx, moduleRollout, shared.StateWarning, message)
+}
+
+func (r *ModuleRolloutReconciler) currentWaveName(moduleRollout *v1beta2.ModuleRollout) string {
+	return moduleRollout.Spec.Waves[moduleRollout.Status.CurrentWave].Name
+}
+
+func (r *ModuleRolloutReconciler) updateRolloutStatus(ctx context.Context, moduleRollout *v1beta2.ModuleRollout,
+	state shared.State, message string,
+) (ctrl.Result, error) {
+	moduleRollout.Status.State = state
+	moduleRollout.Status.Message = message
+	moduleRollout.Status.ObservedGeneration = moduleRollout.Generation
+	moduleRollout.ManagedFields = nil
+	if err := r.Status().Patch(ctx, moduleRollout, client.Apply, client.FieldOwner(shared.OperatorName),
+		status.SubResourceOpts(client.ForceOwnership)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update rollout status: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: queue.DetermineRequeueInterval(state, r.RequeueIntervals)}, nil
+}
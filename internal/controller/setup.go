@@ -22,7 +22,10 @@ import (
 
 	"github.com/kyma-project/lifecycle-manager/api/shared"
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	declarativev2 "github.com/kyma-project/lifecycle-manager/internal/declarative/v2"
+	"github.com/kyma-project/lifecycle-manager/internal/manifest/layercache"
 	"github.com/kyma-project/lifecycle-manager/pkg/istio"
+	"github.com/kyma-project/lifecycle-manager/pkg/remote"
 	"github.com/kyma-project/lifecycle-manager/pkg/security"
 	"github.com/kyma-project/lifecycle-manager/pkg/watch"
 )
@@ -31,13 +34,21 @@ type SetupUpSetting struct {
 	ListenerAddr                 string
 	EnableDomainNameVerification bool
 	IstioNamespace               string
+	EnableCosignVerification     bool
+	// LayerCache stores the module layers pulled by the Manifest controller.
+	LayerCache *layercache.Cache
+	// RemoteConfigProviders resolve the access to the runtime cluster according to the sync strategy of a Kyma.
+	RemoteConfigProviders remote.ConfigProviders
+	// DriftDetection configures how the Manifest controller handles synced resources changed in the runtime cluster.
+	DriftDetection declarativev2.DriftDetectionMode
 }
 
 const (
-	WatcherControllerName  = "watcher"
-	PurgeControllerName    = "purge"
-	KymaControllerName     = "kyma"
-	ManifestControllerName = "manifest"
+	WatcherControllerName       = "watcher"
+	PurgeControllerName         = "purge"
+	KymaControllerName          = "kyma"
+	ManifestControllerName      = "manifest"
+	ModuleRolloutControllerName = "module-rollout"
 )
 
 var (
@@ -52,20 +63,26 @@ func (r *KymaReconciler) SetupWithManager(mgr ctrl.Manager,
 ) error {
 	predicates := predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})
 
-	controllerBuilder := ctrl.NewControllerManagedBy(mgr).For(&v1beta2.Kyma{}).
+	// the predicates are set per watch instead of globally, as ModuleRollouts release waves with status changes
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).For(&v1beta2.Kyma{}, builder.WithPredicates(predicates)).
 		Named(KymaControllerName).
 		WithOptions(options).
-		WithEventFilter(predicates).
 		Watches(
 			&v1beta2.ModuleTemplate{},
 			handler.EnqueueRequestsFromMapFunc(watch.NewTemplateChangeHandler(r).Watch()),
 			builder.WithPredicates(predicates),
 		).
+		Watches(
+			&v1beta2.ModuleRollout{},
+			handler.EnqueueRequestsFromMapFunc(watch.NewRolloutChangeHandler(r).Watch()),
+			builder.WithPredicates(watch.RolloutReleasePredicate()),
+		).
 		// here we define a watch on secrets for the lifecycle-manager so that the cache is picking up changes
 		Watches(&apicorev1.Secret{}, handler.Funcs{})
 
 	controllerBuilder = controllerBuilder.Watches(&v1beta2.Manifest{},
-		&watch.RestrictedEnqueueRequestForOwner{Log: ctrl.Log, OwnerType: &v1beta2.Kyma{}, IsController: true})
+		&watch.RestrictedEnqueueRequestForOwner{Log: ctrl.Log, OwnerType: &v1beta2.Kyma{}, IsController: true},
+		builder.WithPredicates(predicates))
 
 	var runnableListener *watcherevent.SKREventListener
 	var eventChannel *source.Channel
@@ -161,6 +178,23 @@ func (r *WatcherReconciler) SetupWithManager(mgr ctrl.Manager, options ctrlrunti
 	return nil
 }
 
+// SetupWithManager sets up the ModuleRollout controller with the Manager.
+func (r *ModuleRolloutReconciler) SetupWithManager(mgr ctrl.Manager,
+	options ctrlruntime.Options,
+) error {
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta2.ModuleRollout{}).
+		Named(ModuleRolloutControllerName).
+		WithOptions(options).
+		WithEventFilter(predicate.GenerationChangedPredicate{})
+
+	if err := controllerBuilder.Complete(r); err != nil {
+		return fmt.Errorf("error occurred while building controller: %w", err)
+	}
+
+	return nil
+}
+
 // SetupWithManager sets up the Purge controller with the Manager.
 func (r *PurgeReconciler) SetupWithManager(mgr ctrl.Manager,
 	options ctrlruntime.Options,
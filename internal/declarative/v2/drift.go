@@ -0,0 +1,246 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/internal"
+	"github.com/kyma-project/lifecycle-manager/pkg/util"
+)
+
+var ErrDriftDetectionFailed = errors.New("drift detection failed")
+
+// DriftDetectionMode configures how synced resources that were changed in the target cluster are handled.
+type DriftDetectionMode string
+
+const (
+	// DriftDetectionDisabled re-applies all resources without checking them for drift.
+	DriftDetectionDisabled DriftDetectionMode = "disabled"
+	// DriftDetectionReport reports drifted resources in the Drift condition and leaves them unchanged.
+	DriftDetectionReport DriftDetectionMode = "report"
+	// DriftDetectionCorrect reports drifted resources in the Drift condition and re-applies them.
+	DriftDetectionCorrect DriftDetectionMode = "correct"
+)
+
+const (
+	maxDriftsInMessage = 10
+	maxFieldsInMessage = 5
+)
+
+// ResourceDrift describes a synced resource whose state in the cluster differs from the target.
+type ResourceDrift struct {
+	Resource shared.Resource
+	// Fields are the paths of the fields that are changed by the next apply. It is empty if the resource was deleted.
+	Fields []string
+}
+
+func (d ResourceDrift) String() string {
+	name := d.Resource.NamespacedName()
+	if len(d.Fields) == 0 {
+		return fmt.Sprintf("%s %s (deleted)", d.Resource.Kind, name)
+	}
+	fields := d.Fields
+	if len(fields) > maxFieldsInMessage {
+		fields = append(fields[:maxFieldsInMessage:maxFieldsInMessage], "...")
+	}
+	return fmt.Sprintf("%s %s (%s)", d.Resource.Kind, name, strings.Join(fields, ", "))
+}
+
+type driftResult struct {
+	drift *ResourceDrift
+	err   error
+}
+
+// DetectDrift applies the resources with server-side dry-run and returns the resources whose state in the cluster
+// differs from the result, together with the fields that would be corrected by the next apply.
+// Resources that no longer exist in the cluster are reported without fields.
+func (c *ConcurrentDefaultSSA) DetectDrift(ctx context.Context, resources []*resource.Info) (
+	[]ResourceDrift, error,
+) {
+	detectionStart := time.Now()
+	logger := logf.FromContext(ctx, "owner", c.owner)
+	logger.V(internal.TraceLogLevel).Info("drift detection", "resources", len(resources))
+
+	converted := NewInfoToResourceConverter().InfosToResources(resources)
+	results := make(chan driftResult, len(resources))
+	for i := range resources {
+		i := i
+		go func() {
+			drift, err := c.detectResourceDrift(ctx, resources[i], converted[i])
+			results <- driftResult{drift: drift, err: err}
+		}()
+	}
+
+	var drifts []ResourceDrift
+	var errs []error
+	for i := 0; i < len(resources); i++ {
+		result := <-results
+		switch {
+		case result.err != nil:
+			errs = append(errs, result.err)
+		case result.drift != nil:
+			drifts = append(drifts, *result.drift)
+		}
+	}
+
+	detectionFinish := time.Since(detectionStart)
+	if errs != nil {
+		errs = append(errs, fmt.Errorf("%w (after %s)", ErrDriftDetectionFailed, detectionFinish))
+		return nil, errors.Join(errs...)
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].Resource.ID() < drifts[j].Resource.ID()
+	})
+	logger.V(internal.DebugLogLevel).Info("drift detection finished", "time", detectionFinish,
+		"drifted", len(drifts))
+	return drifts, nil
+}
+
+func (c *ConcurrentDefaultSSA) detectResourceDrift(ctx context.Context, info *resource.Info,
+	res shared.Resource,
+) (*ResourceDrift, error) {
+	current, desired, err := c.dryRunApply(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return &ResourceDrift{Resource: res}, nil
+	}
+	fields, err := changedFields(current, desired)
+	if err != nil {
+		return nil, fmt.Errorf("comparing dry-run result for %s failed: %w", info.ObjectName(), err)
+	}
+	if len(fields) == 0 {
+		return nil, nil //nolint:nilnil // a resource without drift is no error
+	}
+	return &ResourceDrift{Resource: res, Fields: fields}, nil
+}
+
+// detectDrift checks the already synced resources of the target for drift if drift detection is enabled and
+// records the result in the Drift condition and the metrics. It returns the resources that have to be applied,
+// which exclude the drifted resources in DriftDetectionReport mode, and whether the Drift condition was changed.
+// Drift is only detected if the resources of the synced OCI ref are rendered, as an upgrade changes them anyway.
+func (r *Reconciler) detectDrift(ctx context.Context, clnt Client, obj Object, spec *Spec,
+	target []*resource.Info,
+) ([]*resource.Info, bool, error) {
+	if (r.DriftDetection != DriftDetectionReport && r.DriftDetection != DriftDetectionCorrect) ||
+		!obj.GetDeletionTimestamp().IsZero() || !ociRefNotChanged(obj, spec.OCIRef) {
+		return target, false, nil
+	}
+	status := obj.GetStatus()
+
+	synced := make(map[string]bool, len(status.Synced))
+	for _, res := range status.Synced {
+		synced[res.ID()] = true
+	}
+	resources := NewInfoToResourceConverter().InfosToResources(target)
+	syncedTarget := make([]*resource.Info, 0, len(target))
+	for i := range target {
+		if synced[resources[i].ID()] {
+			syncedTarget = append(syncedTarget, target[i])
+		}
+	}
+
+	drifts, err := ConcurrentSSA(clnt, r.FieldOwner).DetectDrift(ctx, syncedTarget)
+	if err != nil {
+		r.Event(obj, "Warning", "DriftDetection", err.Error())
+		obj.SetStatus(status.WithState(shared.StateError).WithErr(err))
+		return nil, false, err
+	}
+
+	kymaName, moduleName := obj.GetLabels()[shared.KymaName], obj.GetLabels()[shared.ModuleName]
+	r.Metrics.SetDriftedResources(kymaName, moduleName, len(drifts))
+	changed := setDriftCondition(obj, r.DriftDetection, drifts)
+	if changed && len(drifts) > 0 {
+		condition := meta.FindStatusCondition(obj.GetStatus().Conditions, string(ConditionTypeDrift))
+		r.Event(obj, "Warning", condition.Reason, condition.Message)
+	}
+
+	if r.DriftDetection == DriftDetectionCorrect {
+		r.Metrics.RecordDriftCorrections(kymaName, moduleName, len(drifts))
+		return target, changed, nil
+	}
+	drifted := make(map[string]bool, len(drifts))
+	for _, drift := range drifts {
+		drifted[drift.Resource.ID()] = true
+	}
+	toApply := make([]*resource.Info, 0, len(target))
+	for i := range target {
+		if !drifted[resources[i].ID()] {
+			toApply = append(toApply, target[i])
+			continue
+		}
+		// drifted resources are not applied, so their readiness is checked with their state in the cluster
+		if err := refreshFromCluster(ctx, clnt, target[i]); err != nil {
+			obj.SetStatus(obj.GetStatus().WithState(shared.StateError).WithErr(err))
+			return nil, false, err
+		}
+	}
+	return toApply, changed, nil
+}
+
+// refreshFromCluster replaces the rendered object of the resource with its state in the cluster, if it exists.
+func refreshFromCluster(ctx context.Context, clnt Client, info *resource.Info) error {
+	obj, ok := info.Object.(client.Object)
+	if !ok {
+		return fmt.Errorf("%s is not a valid client-go object: %w", info.ObjectName(), ErrClientObjectConversionFailed)
+	}
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	if err := clnt.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		if util.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("get for %s failed: %w", info.ObjectName(), err)
+	}
+	info.Object = current
+	return nil
+}
+
+// setDriftCondition records the drifted resources in the Drift condition and reports whether it was changed.
+// A correction stays visible in the condition until the next drift is detected.
+func setDriftCondition(obj Object, mode DriftDetectionMode, drifts []ResourceDrift) bool {
+	status := obj.GetStatus()
+	current := meta.FindStatusCondition(status.Conditions, string(ConditionTypeDrift))
+	condition := apimetav1.Condition{
+		Type:               string(ConditionTypeDrift),
+		ObservedGeneration: obj.GetGeneration(),
+	}
+	switch {
+	case len(drifts) == 0 && current != nil && current.Reason == string(ConditionReasonDriftCorrected):
+		return false
+	case len(drifts) == 0:
+		condition.Status = apimetav1.ConditionFalse
+		condition.Reason = string(ConditionReasonNoDrift)
+		condition.Message = "synced resources match the target"
+	case mode == DriftDetectionCorrect:
+		condition.Status = apimetav1.ConditionFalse
+		condition.Reason = string(ConditionReasonDriftCorrected)
+		condition.Message = "drift was corrected: " + describeDrifts(drifts)
+		// every correction gets its own transition time
+		meta.RemoveStatusCondition(&status.Conditions, condition.Type)
+	default:
+		condition.Status = apimetav1.ConditionTrue
+		condition.Reason = string(ConditionReasonDriftDetected)
+		condition.Message = "drift was detected: " + describeDrifts(drifts)
+	}
+	changed := meta.SetStatusCondition(&status.Conditions, condition)
+	obj.SetStatus(status)
+	return changed
+}
+
+func describeDrifts(drifts []ResourceDrift) string {
+	return util.DescribeFirst(drifts, maxDriftsInMessage, len(drifts))
+}
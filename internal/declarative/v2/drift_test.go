@@ -0,0 +1,90 @@
+package v2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	declarativev2 "github.com/kyma-project/lifecycle-manager/internal/declarative/v2"
+)
+
+func configMap(name string, data map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"kind":       "ConfigMap",
+		"apiVersion": "v1",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": "some-namespace",
+			"labels":    map[string]any{"app": name},
+		},
+		"data": data,
+	}}
+}
+
+func configMapInfo(obj *unstructured.Unstructured) *resource.Info {
+	return &resource.Info{Name: obj.GetName(), Namespace: obj.GetNamespace(), Object: obj}
+}
+
+func TestConcurrentSSA_DetectDrift(t *testing.T) {
+	t.Parallel()
+
+	unchanged := configMap("unchanged", map[string]any{"key": "value"})
+	drifted := configMap("drifted", map[string]any{"key": "value", "other": "value"})
+	clnt := fake.NewClientBuilder().WithObjects(unchanged.DeepCopy(), drifted.DeepCopy()).Build()
+
+	target := configMap("drifted", map[string]any{"key": "changed"})
+	target.SetLabels(map[string]string{"app": "drifted", "added": "true"})
+	deleted := configMap("deleted", nil)
+
+	drifts, err := declarativev2.ConcurrentSSA(clnt, client.FieldOwner("test")).DetectDrift(
+		context.Background(), []*resource.Info{configMapInfo(unchanged), configMapInfo(target), configMapInfo(deleted)},
+	)
+
+	require.NoError(t, err)
+	require.Len(t, drifts, 2)
+	assert.Equal(t, "deleted", drifts[0].Resource.Name)
+	assert.Empty(t, drifts[0].Fields)
+	assert.Equal(t, "drifted", drifts[1].Resource.Name)
+	assert.Equal(t, []string{"data.key", "data.other", "metadata.labels.added"}, drifts[1].Fields)
+	inCluster := drifted.DeepCopy()
+	require.NoError(t, clnt.Get(context.Background(), client.ObjectKeyFromObject(drifted), inCluster))
+	assert.Equal(t, drifted.Object["data"], inCluster.Object["data"])
+}
+
+func TestResourceDrift_String(t *testing.T) {
+	t.Parallel()
+	res := shared.Resource{
+		Name:             "test",
+		Namespace:        "some-namespace",
+		GroupVersionKind: apimetav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+	}
+	tests := []struct {
+		name     string
+		fields   []string
+		expected string
+	}{
+		{"deleted resource", nil, "Deployment some-namespace/test (deleted)"},
+		{"changed fields", []string{"spec.replicas"}, "Deployment some-namespace/test (spec.replicas)"},
+		{
+			"too many fields",
+			[]string{"a", "b", "c", "d", "e", "f"},
+			"Deployment some-namespace/test (a, b, c, d, e, ...)",
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			drift := declarativev2.ResourceDrift{Resource: res, Fields: testCase.fields}
+			assert.Equal(t, testCase.expected, drift.String())
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	machineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kyma-project/lifecycle-manager/internal"
+	"github.com/kyma-project/lifecycle-manager/pkg/util"
+)
+
+var ErrDryRunFailed = errors.New("ServerSideApply dry-run failed")
+
+type dryRunChange int
+
+const (
+	dryRunUnchanged dryRunChange = iota
+	dryRunCreated
+	dryRunUpdated
+)
+
+type dryRunResult struct {
+	info   *resource.Info
+	change dryRunChange
+	err    error
+}
+
+// DryRun applies the resources with server-side dry-run and returns the resources that would be created and
+// the resources that would be updated, without persisting any change in the cluster.
+func (c *ConcurrentDefaultSSA) DryRun(ctx context.Context, resources []*resource.Info) (
+	[]*resource.Info, []*resource.Info, error,
+) {
+	dryRunStart := time.Now()
+	logger := logf.FromContext(ctx, "owner", c.owner)
+	logger.V(internal.TraceLogLevel).Info("ServerSideApply dry-run", "resources", len(resources))
+
+	results := make(chan dryRunResult, len(resources))
+	for i := range resources {
+		i := i
+		go func() {
+			change, err := c.dryRunResourceInfo(ctx, resources[i])
+			results <- dryRunResult{info: resources[i], change: change, err: err}
+		}()
+	}
+
+	var created, updated []*resource.Info
+	var errs []error
+	for i := 0; i < len(resources); i++ {
+		result := <-results
+		switch {
+		case result.err != nil:
+			errs = append(errs, result.err)
+		case result.change == dryRunCreated:
+			created = append(created, result.info)
+		case result.change == dryRunUpdated:
+			updated = append(updated, result.info)
+		}
+	}
+
+	dryRunFinish := time.Since(dryRunStart)
+	if errs != nil {
+		errs = append(errs, fmt.Errorf("%w (after %s)", ErrDryRunFailed, dryRunFinish))
+		return nil, nil, errors.Join(errs...)
+	}
+	logger.V(internal.DebugLogLevel).Info("ServerSideApply dry-run finished", "time", dryRunFinish)
+	return created, updated, nil
+}
+
+func (c *ConcurrentDefaultSSA) dryRunResourceInfo(ctx context.Context, info *resource.Info) (dryRunChange, error) {
+	current, desired, err := c.dryRunApply(ctx, info)
+	if err != nil {
+		return dryRunUnchanged, err
+	}
+	if current == nil {
+		return dryRunCreated, nil
+	}
+
+	changed, err := hasChangedContent(current, desired)
+	if err != nil {
+		return dryRunUnchanged, fmt.Errorf("comparing dry-run result for %s failed: %w", info.ObjectName(), err)
+	}
+	if changed {
+		return dryRunUpdated, nil
+	}
+	return dryRunUnchanged, nil
+}
+
+// dryRunApply returns the object in the cluster and the result of applying the resource with server-side dry-run.
+// If the resource does not exist in the cluster yet, both objects are nil.
+func (c *ConcurrentDefaultSSA) dryRunApply(ctx context.Context, info *resource.Info) (
+	client.Object, client.Object, error,
+) {
+	obj, isTyped := info.Object.(client.Object)
+	if !isTyped {
+		return nil, nil, fmt.Errorf(
+			"%s is not a valid client-go object: %w", info.ObjectName(), ErrClientObjectConversionFailed,
+		)
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	err := c.clnt.Get(ctx, client.ObjectKeyFromObject(obj), current)
+	// resources of kinds that are not yet known to the cluster are usually introduced by CRDs
+	// of the same rendering, which are not created in a dry-run.
+	if util.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("get for %s failed: %w", info.ObjectName(), err)
+	}
+
+	desired, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil, nil, fmt.Errorf(
+			"%s is not a valid client-go object: %w", info.ObjectName(), ErrClientObjectConversionFailed,
+		)
+	}
+	desired.SetManagedFields(nil)
+	if err := c.clnt.Patch(ctx, desired, client.Apply, client.ForceOwnership, c.owner, client.DryRunAll); err != nil {
+		return nil, nil, fmt.Errorf(
+			"dry-run patch for %s failed: %w", info.ObjectName(), c.suppressUnauthorized(err),
+		)
+	}
+	return current, desired, nil
+}
+
+// hasChangedContent compares the object in the cluster with the result of the dry-run,
+// ignoring metadata that is always changed by an apply.
+func hasChangedContent(current, dryRun client.Object) (bool, error) {
+	fields, err := changedFields(current, dryRun)
+	if err != nil {
+		return false, err
+	}
+	return len(fields) > 0, nil
+}
+
+// changedFields returns the paths of all fields that differ between the object in the cluster and the result
+// of the dry-run, ignoring metadata that is always changed by an apply.
+func changedFields(current, dryRun client.Object) ([]string, error) {
+	currentContent, err := machineryruntime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert current object: %w", err)
+	}
+	dryRunContent, err := machineryruntime.DefaultUnstructuredConverter.ToUnstructured(dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert dry-run object: %w", err)
+	}
+	for _, content := range []map[string]any{currentContent, dryRunContent} {
+		unstructured.RemoveNestedField(content, "metadata", "managedFields")
+		unstructured.RemoveNestedField(content, "metadata", "resourceVersion")
+		unstructured.RemoveNestedField(content, "metadata", "generation")
+	}
+	return appendChangedFields(nil, "", currentContent, dryRunContent), nil
+}
+
+// appendChangedFields appends the paths of the differing fields in sorted order. Maps are compared field by field,
+// all other values as a whole.
+func appendChangedFields(fields []string, path string, current, dryRun map[string]any) []string {
+	keys := make([]string, 0, len(current)+len(dryRun))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	for key := range dryRun {
+		if _, found := current[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+		currentMap, currentIsMap := current[key].(map[string]any)
+		dryRunMap, dryRunIsMap := dryRun[key].(map[string]any)
+		if currentIsMap && dryRunIsMap {
+			fields = appendChangedFields(fields, fieldPath, currentMap, dryRunMap)
+			continue
+		}
+		if !equality.Semantic.DeepEqual(current[key], dryRun[key]) {
+			fields = append(fields, fieldPath)
+		}
+	}
+	return fields
+}
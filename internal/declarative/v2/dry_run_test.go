@@ -0,0 +1,55 @@
+package v2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	declarativev2 "github.com/kyma-project/lifecycle-manager/internal/declarative/v2"
+)
+
+func TestConcurrentSSA_DryRun(t *testing.T) {
+	t.Parallel()
+
+	existing := &unstructured.Unstructured{Object: map[string]any{
+		"kind":       "ConfigMap",
+		"apiVersion": "v1",
+		"metadata": map[string]any{
+			"name":      "existing",
+			"namespace": "some-namespace",
+		},
+		"data": map[string]any{"key": "value"},
+	}}
+	clnt := fake.NewClientBuilder().WithObjects(existing.DeepCopy()).Build()
+
+	missing := &unstructured.Unstructured{Object: map[string]any{
+		"kind":       "ConfigMap",
+		"apiVersion": "v1",
+		"metadata": map[string]any{
+			"name":      "missing",
+			"namespace": "some-namespace",
+		},
+	}}
+
+	changed := existing.DeepCopy()
+	changed.Object["data"] = map[string]any{"key": "changed"}
+
+	created, updated, err := declarativev2.ConcurrentSSA(clnt, client.FieldOwner("test")).
+		DryRun(context.Background(), []*resource.Info{{Object: missing}, {Object: changed}})
+
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, "missing", created[0].Object.(client.Object).GetName())
+	require.Len(t, updated, 1)
+	assert.Equal(t, "existing", updated[0].Object.(client.Object).GetName())
+	assert.Error(t, clnt.Get(context.Background(), client.ObjectKeyFromObject(missing), missing.DeepCopy()))
+	inCluster := existing.DeepCopy()
+	require.NoError(t, clnt.Get(context.Background(), client.ObjectKeyFromObject(existing), inCluster))
+	assert.Equal(t, existing.Object["data"], inCluster.Object["data"])
+}
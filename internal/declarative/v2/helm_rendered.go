@@ -0,0 +1,107 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/lifecycle-manager/internal"
+)
+
+const helmNotesFile = "NOTES.txt"
+
+var ErrHelmRenderFailed = errors.New("helm chart rendering failed")
+
+// renderHelmChart loads the packaged chart from the spec path and renders it client-side with the spec values.
+// CRDs shipped in the crds directory of the chart are put in front of the rendered templates,
+// so that they are handled by the same apply, prune and readiness logic as all other resources.
+// Helm hooks are dropped, as no Helm release is installed that could run them.
+func renderHelmChart(spec *Spec) (internal.ManifestResources, error) {
+	chrt, err := loader.Load(spec.Path)
+	if err != nil {
+		return internal.ManifestResources{}, fmt.Errorf("%w: failed to load chart %s: %w",
+			ErrHelmRenderFailed, spec.Path, err)
+	}
+	if err := chartutil.ProcessDependencies(chrt, spec.Values); err != nil {
+		return internal.ManifestResources{}, fmt.Errorf("%w: failed to process dependencies: %w",
+			ErrHelmRenderFailed, err)
+	}
+
+	namespace := spec.Namespace
+	if namespace == "" {
+		namespace = apimetav1.NamespaceDefault
+	}
+	values, err := chartutil.ToRenderValues(chrt, spec.Values, chartutil.ReleaseOptions{
+		Name:      spec.ManifestName,
+		Namespace: namespace,
+		IsInstall: true,
+	}, chartutil.DefaultCapabilities)
+	if err != nil {
+		return internal.ManifestResources{}, fmt.Errorf("%w: failed to build values: %w", ErrHelmRenderFailed, err)
+	}
+
+	rendered, err := engine.Render(chrt, values)
+	if err != nil {
+		return internal.ManifestResources{}, fmt.Errorf("%w: %w", ErrHelmRenderFailed, err)
+	}
+
+	resources, err := internal.ParseManifestStringToObjects(spec.Path, joinHelmManifests(chrt, rendered))
+	if err != nil {
+		return internal.ManifestResources{}, fmt.Errorf("%w: %w", ErrHelmRenderFailed, err)
+	}
+	return resources, nil
+}
+
+func joinHelmManifests(chrt *chart.Chart, rendered map[string]string) string {
+	documents := make([]string, 0, len(rendered))
+	for _, crd := range chrt.CRDObjects() {
+		documents = append(documents, string(crd.File.Data))
+	}
+
+	names := make([]string, 0, len(rendered))
+	for name := range rendered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if path.Base(name) == helmNotesFile {
+			continue
+		}
+		entries := releaseutil.SplitManifests(rendered[name])
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+		for _, key := range keys {
+			content := strings.TrimSpace(entries[key])
+			if content == "" || isHelmHook(content) {
+				continue
+			}
+			documents = append(documents, content)
+		}
+	}
+	return strings.Join(documents, "\n---\n")
+}
+
+// isHelmHook reports whether the document is annotated as a Helm hook, such as a test Pod or a pre-install Job.
+// Documents that cannot be parsed are kept, so the parse error is reported for them.
+func isHelmHook(document string) bool {
+	var head releaseutil.SimpleHead
+	if err := yaml.Unmarshal([]byte(document), &head); err != nil || head.Metadata == nil {
+		return false
+	}
+	_, isHook := head.Metadata.Annotations[release.HookAnnotation]
+	return isHook
+}
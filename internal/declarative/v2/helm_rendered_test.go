@@ -0,0 +1,169 @@
+package v2_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	declarativev2 "github.com/kyma-project/lifecycle-manager/internal/declarative/v2"
+)
+
+const (
+	testChartYAML = `apiVersion: v2
+name: sample
+version: 0.1.0
+`
+	testChartValues = `replicas: 1
+message: default
+`
+	testChartConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+  namespace: {{ .Release.Namespace }}
+data:
+  message: {{ .Values.message | quote }}
+  replicas: {{ .Values.replicas | quote }}
+`
+	testChartCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: samples.operator.kyma-project.io
+`
+	testChartNotes    = `Thank you for installing {{ .Chart.Name }}.`
+	testChartTestHook = `apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ .Release.Name }}-test
+  annotations:
+    helm.sh/hook: test
+`
+	testChartInstallHooks = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: {{ .Release.Name }}-migrate
+  annotations:
+    helm.sh/hook: pre-install,pre-upgrade
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ .Release.Name }}-migrate
+`
+)
+
+func writeTestChart(t *testing.T) string {
+	t.Helper()
+	return writeChart(t, map[string]string{
+		"Chart.yaml":               testChartYAML,
+		"values.yaml":              testChartValues,
+		"templates/configmap.yaml": testChartConfigMap,
+		"templates/NOTES.txt":      testChartNotes,
+		"crds/crd.yaml":            testChartCRD,
+	})
+}
+
+func writeChart(t *testing.T, files map[string]string) string {
+	t.Helper()
+	chartDir := filepath.Join(t.TempDir(), "sample")
+	for name, content := range files {
+		file := filepath.Join(chartDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	}
+	return chartDir
+}
+
+func TestInMemoryManifestCache_ParseHelmChart(t *testing.T) {
+	t.Parallel()
+	chartPath := writeTestChart(t)
+
+	tests := []struct {
+		name            string
+		values          map[string]any
+		namespace       string
+		expectedMessage string
+		expectedNs      string
+	}{
+		{
+			name:            "chart defaults are used without values",
+			values:          map[string]any{},
+			expectedMessage: "default",
+			expectedNs:      "default",
+		},
+		{
+			name:            "values and namespace override chart defaults",
+			values:          map[string]any{"message": "from-module-cr"},
+			namespace:       "kyma-system",
+			expectedMessage: "from-module-cr",
+			expectedNs:      "kyma-system",
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			parser := declarativev2.NewInMemoryCachedManifestParser(time.Minute)
+			defer parser.Stop()
+
+			resources, err := parser.Parse(&declarativev2.Spec{
+				ManifestName: "sample",
+				Path:         chartPath,
+				Mode:         declarativev2.RenderModeHelm,
+				Values:       testCase.values,
+				Namespace:    testCase.namespace,
+			})
+			require.NoError(t, err)
+			require.Len(t, resources.Items, 2)
+
+			assert.Equal(t, "CustomResourceDefinition", resources.Items[0].GetKind())
+			configMap := resources.Items[1]
+			assert.Equal(t, "sample-config", configMap.GetName())
+			assert.Equal(t, testCase.expectedNs, configMap.GetNamespace())
+			assert.Equal(t, testCase.expectedMessage, configMap.Object["data"].(map[string]any)["message"])
+		})
+	}
+}
+
+func TestInMemoryManifestCache_ParseHelmChart_DropsHooks(t *testing.T) {
+	t.Parallel()
+	chartPath := writeChart(t, map[string]string{
+		"Chart.yaml":               testChartYAML,
+		"values.yaml":              testChartValues,
+		"templates/configmap.yaml": testChartConfigMap,
+		"templates/tests/pod.yaml": testChartTestHook,
+		"templates/migrate.yaml":   testChartInstallHooks,
+	})
+	parser := declarativev2.NewInMemoryCachedManifestParser(time.Minute)
+	defer parser.Stop()
+
+	resources, err := parser.Parse(&declarativev2.Spec{
+		ManifestName: "sample",
+		Path:         chartPath,
+		Mode:         declarativev2.RenderModeHelm,
+	})
+	require.NoError(t, err)
+
+	kinds := make([]string, 0, len(resources.Items))
+	for _, item := range resources.Items {
+		kinds = append(kinds, item.GetKind())
+	}
+	assert.Equal(t, []string{"ConfigMap", "ServiceAccount"}, kinds)
+}
+
+func TestInMemoryManifestCache_ParseHelmChart_InvalidChart(t *testing.T) {
+	t.Parallel()
+	parser := declarativev2.NewInMemoryCachedManifestParser(time.Minute)
+	defer parser.Stop()
+
+	_, err := parser.Parse(&declarativev2.Spec{
+		ManifestName: "sample",
+		Path:         filepath.Join(t.TempDir(), "missing"),
+		Mode:         declarativev2.RenderModeHelm,
+	})
+	require.ErrorIs(t, err, declarativev2.ErrHelmRenderFailed)
+}
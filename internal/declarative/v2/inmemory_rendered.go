@@ -1,6 +1,9 @@
 package v2
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"time"
@@ -46,7 +49,7 @@ func (c *InMemoryManifestCache) Parse(spec *Spec,
 	if item != nil {
 		resources = item.Value()
 	} else {
-		resources, err = internal.ParseManifestToObjects(spec.Path)
+		resources, err = parseManifest(spec)
 		if err != nil {
 			return internal.ManifestResources{}, fmt.Errorf("failed to parse manifest objects: %w", err)
 		}
@@ -61,7 +64,24 @@ func (c *InMemoryManifestCache) Parse(spec *Spec,
 	return *copied, nil
 }
 
+func parseManifest(spec *Spec) (internal.ManifestResources, error) {
+	switch spec.Mode {
+	case RenderModeHelm:
+		return renderHelmChart(spec)
+	case RenderModeKustomize:
+		return renderKustomization(spec)
+	default:
+		return internal.ParseManifestToObjects(spec.Path)
+	}
+}
+
 func generateCacheKey(spec *Spec) string {
 	file := filepath.Join(ManifestFilePrefix, spec.Path, spec.ManifestName)
-	return fmt.Sprintf("%s-%s", file, spec.Mode)
+	if spec.Mode != RenderModeHelm {
+		return fmt.Sprintf("%s-%s", file, spec.Mode)
+	}
+	// the rendering of a chart depends on its values, so every set of values is cached separately
+	values, _ := json.Marshal(spec.Values) //nolint:errchkjson // values are decoded from JSON and always marshal
+	hash := sha256.Sum256(values)
+	return fmt.Sprintf("%s-%s-%s-%s", file, spec.Mode, spec.Namespace, hex.EncodeToString(hash[:]))
 }
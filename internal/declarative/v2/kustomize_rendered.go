@@ -0,0 +1,34 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/kyma-project/lifecycle-manager/internal"
+)
+
+var ErrKustomizeRenderFailed = errors.New("kustomization rendering failed")
+
+// renderKustomization builds the kustomization directory of the spec path in-process.
+// Bases outside of the kustomization directory are rejected by the default load restrictions,
+// so all bases and overlays are expected to be shipped within the layer.
+func renderKustomization(spec *Spec) (internal.ManifestResources, error) {
+	kustomizer := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resMap, err := kustomizer.Run(filesys.MakeFsOnDisk(), spec.Path)
+	if err != nil {
+		return internal.ManifestResources{}, fmt.Errorf("%w: %w", ErrKustomizeRenderFailed, err)
+	}
+	rendered, err := resMap.AsYaml()
+	if err != nil {
+		return internal.ManifestResources{}, fmt.Errorf("%w: failed to convert resources to yaml: %w",
+			ErrKustomizeRenderFailed, err)
+	}
+	resources, err := internal.ParseManifestStringToObjects(spec.Path, string(rendered))
+	if err != nil {
+		return internal.ManifestResources{}, fmt.Errorf("%w: %w", ErrKustomizeRenderFailed, err)
+	}
+	return resources, nil
+}
@@ -0,0 +1,115 @@
+package v2_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	declarativev2 "github.com/kyma-project/lifecycle-manager/internal/declarative/v2"
+)
+
+const (
+	testBaseKustomization = `resources:
+- configmap.yaml
+`
+	testBaseConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: sample-config
+data:
+  environment: base
+`
+	testOverlayKustomization = `namespace: kyma-system
+namePrefix: prod-
+resources:
+- ../../base
+patches:
+- patch: |-
+    - op: replace
+      path: /data/environment
+      value: prod
+  target:
+    kind: ConfigMap
+`
+)
+
+func writeTestKustomization(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"base/kustomization.yaml":          testBaseKustomization,
+		"base/configmap.yaml":              testBaseConfigMap,
+		"overlays/prod/kustomization.yaml": testOverlayKustomization,
+	}
+	for name, content := range files {
+		file := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	}
+	return root
+}
+
+func TestInMemoryManifestCache_ParseKustomization(t *testing.T) {
+	t.Parallel()
+	root := writeTestKustomization(t)
+
+	tests := []struct {
+		name                string
+		path                string
+		expectedName        string
+		expectedNamespace   string
+		expectedEnvironment string
+	}{
+		{
+			name:                "base is rendered as is",
+			path:                filepath.Join(root, "base"),
+			expectedName:        "sample-config",
+			expectedEnvironment: "base",
+		},
+		{
+			name:                "overlay is applied on top of the base",
+			path:                filepath.Join(root, "overlays", "prod"),
+			expectedName:        "prod-sample-config",
+			expectedNamespace:   "kyma-system",
+			expectedEnvironment: "prod",
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			parser := declarativev2.NewInMemoryCachedManifestParser(time.Minute)
+			defer parser.Stop()
+
+			resources, err := parser.Parse(&declarativev2.Spec{
+				ManifestName: "sample",
+				Path:         testCase.path,
+				Mode:         declarativev2.RenderModeKustomize,
+			})
+			require.NoError(t, err)
+			require.Len(t, resources.Items, 1)
+
+			configMap := resources.Items[0]
+			assert.Equal(t, testCase.expectedName, configMap.GetName())
+			assert.Equal(t, testCase.expectedNamespace, configMap.GetNamespace())
+			assert.Equal(t, testCase.expectedEnvironment, configMap.Object["data"].(map[string]any)["environment"])
+		})
+	}
+}
+
+func TestInMemoryManifestCache_ParseKustomization_MissingKustomization(t *testing.T) {
+	t.Parallel()
+	parser := declarativev2.NewInMemoryCachedManifestParser(time.Minute)
+	defer parser.Stop()
+
+	_, err := parser.Parse(&declarativev2.Spec{
+		ManifestName: "sample",
+		Path:         t.TempDir(),
+		Mode:         declarativev2.RenderModeKustomize,
+	})
+	require.ErrorIs(t, err, declarativev2.ErrKustomizeRenderFailed)
+}
@@ -41,6 +41,7 @@ func DefaultOptions() *Options {
 		WithSkipReconcileOn(SkipReconcileOnDefaultLabelPresentAndTrue),
 		WithManifestParser(NewInMemoryCachedManifestParser(DefaultInMemoryParseTTL)),
 		WithModuleCRDeletionCheck(NewDefaultDeletionCheck()),
+		WithDriftDetection(DriftDetectionDisabled),
 	)
 }
 
@@ -70,11 +71,15 @@ type Options struct {
 	PostRuns   []PostRun
 	PreDeletes []PreDelete
 
+	DryRunResources DryRunResourcesFn
+
 	DeletionCheck ModuleCRDeletionCheck
 
 	DeletePrerequisites bool
 
 	ShouldSkip SkipReconcile
+
+	DriftDetection DriftDetectionMode
 }
 
 type Option interface {
@@ -117,6 +122,13 @@ func (o WithFinalizer) Apply(options *Options) {
 	options.Finalizer = string(o)
 }
 
+// WithDriftDetection configures whether drift of the synced resources is detected, and whether it is corrected.
+type WithDriftDetection DriftDetectionMode
+
+func (o WithDriftDetection) Apply(options *Options) {
+	options.DriftDetection = DriftDetectionMode(o)
+}
+
 type WithManagerOption struct {
 	manager.Manager
 }
@@ -206,6 +218,22 @@ func (o WithPreDelete) Apply(options *Options) {
 	options.PreDeletes = append(options.PreDeletes, o...)
 }
 
+// DryRunResourcesFn returns the resources that are applied by hooks instead of being rendered,
+// so they are also applied with server-side dry-run and recorded in the dry-run of the object.
+type DryRunResourcesFn func(obj Object) []*unstructured.Unstructured
+
+func WithDryRunResources(dryRunResources DryRunResourcesFn) WithDryRunResourcesOption {
+	return WithDryRunResourcesOption{DryRunResourcesFn: dryRunResources}
+}
+
+type WithDryRunResourcesOption struct {
+	DryRunResourcesFn
+}
+
+func (o WithDryRunResourcesOption) Apply(options *Options) {
+	options.DryRunResources = o.DryRunResourcesFn
+}
+
 func WithModuleCRDeletionCheck(deletionCheckFn ModuleCRDeletionCheck) WithModuleCRDeletionCheckOption {
 	return WithModuleCRDeletionCheckOption{ModuleCRDeletionCheck: deletionCheckFn}
 }
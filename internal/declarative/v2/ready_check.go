@@ -16,6 +16,8 @@ var ErrNotValidClientObject = errors.New("object in resource info is not a valid
 type StateInfo struct {
 	shared.State
 	Info string
+	// UnhealthyResources are the resources that are not ready, if the State is determined by them.
+	UnhealthyResources []shared.UnhealthyResource
 }
 
 type ReadyCheck interface {
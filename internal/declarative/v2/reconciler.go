@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	apicorev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -19,6 +23,8 @@ import (
 	"github.com/kyma-project/lifecycle-manager/internal/pkg/metrics"
 	"github.com/kyma-project/lifecycle-manager/pkg/common"
 	"github.com/kyma-project/lifecycle-manager/pkg/queue"
+	"github.com/kyma-project/lifecycle-manager/pkg/signature"
+	"github.com/kyma-project/lifecycle-manager/pkg/tracing"
 	"github.com/kyma-project/lifecycle-manager/pkg/util"
 )
 
@@ -59,8 +65,11 @@ type Reconciler struct {
 type ConditionType string
 
 const (
-	ConditionTypeResources    ConditionType = "Resources"
-	ConditionTypeInstallation ConditionType = "Installation"
+	ConditionTypeResources             ConditionType = "Resources"
+	ConditionTypeInstallation          ConditionType = "Installation"
+	ConditionTypeSignatureVerification ConditionType = "SignatureVerification"
+	ConditionTypeDrift                 ConditionType = "Drift"
+	ConditionTypeDeletion              ConditionType = "Deletion"
 )
 
 type ConditionReason string
@@ -68,6 +77,13 @@ type ConditionReason string
 const (
 	ConditionReasonResourcesAreAvailable ConditionReason = "ResourcesAvailable"
 	ConditionReasonReady                 ConditionReason = "Ready"
+	ConditionReasonSignatureVerified     ConditionReason = "SignatureVerified"
+	ConditionReasonSignatureInvalid      ConditionReason = "SignatureInvalid"
+	ConditionReasonNoDrift               ConditionReason = "NoDrift"
+	ConditionReasonDriftDetected         ConditionReason = "DriftDetected"
+	ConditionReasonDriftCorrected        ConditionReason = "DriftCorrected"
+	ConditionReasonInstallationTimeout   ConditionReason = "InstallationTimeout"
+	ConditionReasonDeletionTimeout       ConditionReason = "DeletionTimeout"
 )
 
 func newInstallationCondition(obj Object) apimetav1.Condition {
@@ -90,7 +106,22 @@ func newResourcesCondition(obj Object) apimetav1.Condition {
 	}
 }
 
-//nolint:funlen,cyclop,gocognit // Declarative pkg will be removed soon
+func newSignatureVerificationCondition(obj Object, err error) apimetav1.Condition {
+	condition := apimetav1.Condition{
+		Type:               string(ConditionTypeSignatureVerification),
+		Reason:             string(ConditionReasonSignatureVerified),
+		Status:             apimetav1.ConditionTrue,
+		Message:            "signatures of the module layers are verified",
+		ObservedGeneration: obj.GetGeneration(),
+	}
+	if err != nil {
+		condition.Reason = string(ConditionReasonSignatureInvalid)
+		condition.Status = apimetav1.ConditionFalse
+		condition.Message = err.Error()
+	}
+	return condition
+}
+
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	obj, ok := r.prototype.DeepCopyObject().(Object)
 	if !ok {
@@ -106,6 +137,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{Requeue: false}, nil
 	}
 
+	// the reconciliation is linked to the Kyma reconciliation that last changed the object
+	ctx, span := tracing.Start(ctx, "Manifest.Reconcile",
+		trace.WithAttributes(tracing.ManifestNameKey.String(obj.GetName()),
+			tracing.KymaNameKey.String(obj.GetLabels()[shared.KymaName]),
+			tracing.ModuleNameKey.String(obj.GetLabels()[shared.ModuleName])),
+		tracing.LinkTo(obj.GetAnnotations()[shared.TraceParentAnnotation]))
+	result, err := r.reconcile(ctx, obj)
+	tracing.End(span, err)
+	return result, err
+}
+
+//nolint:funlen,cyclop,gocognit // Declarative pkg will be removed soon
+func (r *Reconciler) reconcile(ctx context.Context, obj Object) (ctrl.Result, error) {
 	if r.ShouldSkip(ctx, obj) {
 		return ctrl.Result{RequeueAfter: r.Success}, nil
 	}
@@ -114,6 +158,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return r.ssaStatus(ctx, obj, metrics.ManifestInit)
 	}
 
+	if !isDryRun(obj) && obj.GetStatus().DryRun != nil {
+		status := obj.GetStatus()
+		status.DryRun = nil
+		obj.SetStatus(status)
+		return r.ssaStatus(ctx, obj, metrics.ManifestDryRunCleanup)
+	}
+
+	r.checkDeletionTimeout(obj)
+
 	if obj.GetDeletionTimestamp().IsZero() {
 		objMeta := r.partialObjectMetadata(obj)
 		if controllerutil.AddFinalizer(objMeta, r.Finalizer) {
@@ -128,6 +181,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 		return r.ssaStatus(ctx, obj, metrics.ManifestParseSpec)
 	}
+	defer spec.ReleasePath()
 
 	if notContainsSyncedOCIRefAnnotation(obj) {
 		updateSyncedOCIRefAnnotation(obj, spec.OCIRef)
@@ -156,6 +210,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	diff := ResourceList(current).Difference(target)
+	if isDryRun(obj) && obj.GetDeletionTimestamp().IsZero() {
+		return r.dryRun(ctx, clnt, obj, target, diff)
+	}
 	if err := r.pruneDiff(ctx, clnt, obj, diff, spec); errors.Is(err, ErrDeletionNotFinished) {
 		r.Metrics.RecordRequeueReason(metrics.ManifestPruneDiffNotFinished, queue.IntendedRequeue)
 		return ctrl.Result{Requeue: true}, nil
@@ -171,7 +228,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return r.ssaStatus(ctx, obj, metrics.ManifestPreDelete)
 	}
 
-	if err = r.syncResources(ctx, clnt, obj, target); err != nil {
+	toApply, driftConditionChanged, err := r.detectDrift(ctx, clnt, obj, spec, target)
+	if err != nil {
+		if errors.Is(err, ErrClientUnauthorized) {
+			r.invalidateClientCache(ctx, obj)
+		}
+		return r.ssaStatus(ctx, obj, metrics.ManifestDriftDetection)
+	}
+
+	if err = r.syncResources(ctx, clnt, obj, target, toApply); err != nil {
 		if errors.Is(err, ErrRequeueRequired) {
 			r.Metrics.RecordRequeueReason(metrics.ManifestSyncResourcesEnqueueRequired, queue.IntendedRequeue)
 			return ctrl.Result{Requeue: true}, nil
@@ -182,6 +247,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return r.ssaStatus(ctx, obj, metrics.ManifestSyncResources)
 	}
 
+	if driftConditionChanged {
+		return r.ssaStatus(ctx, obj, metrics.ManifestDriftDetection)
+	}
+
 	// This situation happens when manifest get new installation layer to update resources,
 	// we need to make sure all updates successfully before we can update synced oci ref
 	if requireUpdateSyncedOCIRefAnnotation(obj, spec.OCIRef) {
@@ -190,6 +259,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	if !obj.GetDeletionTimestamp().IsZero() {
+		r.Metrics.RemoveManifestMetrics(obj.GetLabels()[shared.KymaName], obj.GetLabels()[shared.ModuleName])
 		return r.removeFinalizers(ctx, obj, []string{r.Finalizer}, metrics.ManifestRemoveFinalizerInDeleting)
 	}
 	return ctrl.Result{RequeueAfter: r.Success}, nil
@@ -268,7 +338,16 @@ func (r *Reconciler) Spec(ctx context.Context, obj Object) (*Spec, error) {
 	if err != nil {
 		return nil, err
 	}
-	spec, err := r.SpecResolver.Spec(ctx, obj, targetClient)
+	specCtx, span := tracing.Start(ctx, "SpecResolver.Spec")
+	spec, err := r.SpecResolver.Spec(specCtx, obj, targetClient)
+	tracing.End(span, err)
+	status := obj.GetStatus()
+	// the signature condition is only maintained once a verification failed, so it can be observed to recover
+	if errors.Is(err, signature.ErrVerificationFailed) ||
+		(err == nil && meta.FindStatusCondition(status.Conditions, string(ConditionTypeSignatureVerification)) != nil) {
+		meta.SetStatusCondition(&status.Conditions, newSignatureVerificationCondition(obj, err))
+		obj.SetStatus(status)
+	}
 	if err != nil {
 		r.Event(obj, "Warning", "Spec", err.Error())
 		obj.SetStatus(obj.GetStatus().WithState(shared.StateError).WithErr(err))
@@ -311,10 +390,14 @@ func (r *Reconciler) renderResources(
 	return target, current, nil
 }
 
-func (r *Reconciler) syncResources(ctx context.Context, clnt Client, obj Object, target []*resource.Info) error {
+// syncResources applies the resources of toApply, which can exclude drifted resources of the target,
+// and records the target as synced.
+func (r *Reconciler) syncResources(ctx context.Context, clnt Client, obj Object,
+	target, toApply []*resource.Info,
+) error {
 	status := obj.GetStatus()
 
-	if err := ConcurrentSSA(clnt, r.FieldOwner).Run(ctx, target); err != nil {
+	if err := ConcurrentSSA(clnt, r.FieldOwner).Run(ctx, toApply); err != nil {
 		r.Event(obj, "Warning", "ServerSideApply", err.Error())
 		obj.SetStatus(status.WithState(shared.StateError).WithErr(err))
 		return err
@@ -382,16 +465,27 @@ func (r *Reconciler) checkTargetReadiness(
 	if crStateInfo.State == shared.StateProcessing {
 		waitingMsg := fmt.Sprintf("waiting for resources to become ready: %s", crStateInfo.Info)
 		r.Event(manifest, "Normal", "ResourceReadyCheck", waitingMsg)
-		manifest.SetStatus(status.WithState(shared.StateProcessing).WithOperation(waitingMsg))
+		// a ready installation that becomes processing again, e.g. for an upgrade, restarts the install timeout
+		if meta.IsStatusConditionTrue(status.Conditions, string(ConditionTypeInstallation)) {
+			meta.SetStatusCondition(&status.Conditions, newInstallationCondition(manifest))
+		}
+		manifest.SetStatus(status.WithState(shared.StateProcessing).WithOperation(waitingMsg).
+			WithUnhealthyResources(crStateInfo.UnhealthyResources))
+		r.checkInstallationTimeout(manifest, waitingMsg)
 		return ErrInstallationConditionRequiresUpdate
 	}
+	r.Metrics.SetTimeoutExceeded(manifest.GetLabels()[shared.KymaName], manifest.GetLabels()[shared.ModuleName],
+		timeoutOperationInstall, false)
 
 	installationCondition := newInstallationCondition(manifest)
-	if !meta.IsStatusConditionTrue(status.Conditions, installationCondition.Type) || status.State != crStateInfo.State {
+	newStatus := status.WithUnhealthyResources(crStateInfo.UnhealthyResources)
+	if !meta.IsStatusConditionTrue(status.Conditions, installationCondition.Type) ||
+		status.State != crStateInfo.State || !slices.Equal(status.UnhealthyResources, newStatus.UnhealthyResources) ||
+		status.UnhealthyResourcesCount != newStatus.UnhealthyResourcesCount {
 		r.Event(manifest, "Normal", installationCondition.Reason, installationCondition.Message)
 		installationCondition.Status = apimetav1.ConditionTrue
-		meta.SetStatusCondition(&status.Conditions, installationCondition)
-		manifest.SetStatus(status.WithState(crStateInfo.State).
+		meta.SetStatusCondition(&newStatus.Conditions, installationCondition)
+		manifest.SetStatus(newStatus.WithState(crStateInfo.State).
 			WithOperation(generateOperationMessage(installationCondition, crStateInfo)))
 		return ErrInstallationConditionRequiresUpdate
 	}
@@ -423,6 +517,73 @@ func (r *Reconciler) deleteDiffResources(
 	return nil
 }
 
+// dryRun applies the target resources and the resources of the DryRunResources hook with server-side dry-run
+// and records the resources that would be created, updated or pruned in the status, without changing the target cluster.
+func (r *Reconciler) dryRun(ctx context.Context, clnt Client, obj Object, target, diff []*resource.Info,
+) (ctrl.Result, error) {
+	status := obj.GetStatus()
+
+	diff, err := pruneResource(diff, "Namespace", namespaceNotBeRemoved)
+	if err != nil {
+		obj.SetStatus(status.WithState(shared.StateError).WithErr(err))
+		return r.ssaStatus(ctx, obj, metrics.ManifestDryRun)
+	}
+
+	resources, err := r.dryRunResources(clnt, obj, target)
+	if err != nil {
+		r.Event(obj, "Warning", "DryRun", err.Error())
+		obj.SetStatus(status.WithState(shared.StateError).WithErr(err))
+		return r.ssaStatus(ctx, obj, metrics.ManifestDryRun)
+	}
+
+	created, updated, err := ConcurrentSSA(clnt, r.FieldOwner).DryRun(ctx, resources)
+	if err != nil {
+		if errors.Is(err, ErrClientUnauthorized) {
+			r.invalidateClientCache(ctx, obj)
+		}
+		r.Event(obj, "Warning", "DryRun", err.Error())
+		obj.SetStatus(status.WithState(shared.StateError).WithErr(err))
+		return r.ssaStatus(ctx, obj, metrics.ManifestDryRun)
+	}
+
+	converter := NewInfoToResourceConverter()
+	status.DryRun = &shared.DryRun{
+		LastDryRunTime: apimetav1.NewTime(time.Now()),
+		Created:        converter.InfosToResources(created),
+		Updated:        converter.InfosToResources(updated),
+		Pruned:         converter.InfosToResources(diff),
+	}
+	obj.SetStatus(status.WithOperation(fmt.Sprintf(
+		"dry-run: %d resources would be created, %d updated and %d pruned",
+		len(created), len(updated), len(diff))))
+
+	resetNonPatchableField(obj)
+	if err := r.Status().Patch(ctx, obj, client.Apply, client.ForceOwnership, r.FieldOwner); err != nil {
+		r.Event(obj, "Warning", "PatchStatus", err.Error())
+		r.Metrics.RecordRequeueReason(metrics.ManifestDryRun, queue.UnexpectedRequeue)
+		return ctrl.Result{}, fmt.Errorf("failed to patch status: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: r.Success}, nil
+}
+
+// dryRunResources returns the target resources together with the resources that are applied by hooks.
+func (r *Reconciler) dryRunResources(clnt Client, obj Object, target []*resource.Info) ([]*resource.Info, error) {
+	if r.DryRunResources == nil {
+		return target, nil
+	}
+	hookResources, err := NewResourceToInfoConverter(ResourceInfoConverter(clnt), r.Namespace).
+		UnstructuredToInfos(r.DryRunResources(obj))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert resources for dry-run: %w", err)
+	}
+	return append(slices.Clip(target), hookResources...), nil
+}
+
+func isDryRun(obj Object) bool {
+	dryRun, found := obj.GetLabels()[shared.DryRunLabel]
+	return found && strings.ToLower(dryRun) == shared.EnableLabelValue
+}
+
 func (r *Reconciler) doPreDelete(ctx context.Context, clnt Client, obj Object) error {
 	if !obj.GetDeletionTimestamp().IsZero() {
 		for _, preDelete := range r.PreDeletes {
@@ -13,7 +13,9 @@ type SpecResolver interface {
 type RenderMode string
 
 const (
-	RenderModeRaw RenderMode = "raw"
+	RenderModeRaw       RenderMode = "raw"
+	RenderModeHelm      RenderMode = "helm"
+	RenderModeKustomize RenderMode = "kustomize"
 )
 
 type Spec struct {
@@ -21,6 +23,19 @@ type Spec struct {
 	Path         string
 	OCIRef       string
 	Mode         RenderMode
+	// Values are passed to the chart when rendering with RenderModeHelm.
+	Values map[string]any
+	// Namespace is used as the release namespace when rendering with RenderModeHelm.
+	Namespace string
+	// Release is called once the content at Path is no longer read, it is optional.
+	Release func()
+}
+
+// ReleasePath releases the content at Path, so it can be evicted from the layer cache.
+func (s *Spec) ReleasePath() {
+	if s.Release != nil {
+		s.Release()
+	}
 }
 
 func DefaultSpec(path, ociref string, mode RenderMode) *CustomSpecFns {
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	machineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/resource"
@@ -14,6 +15,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/kyma-project/lifecycle-manager/internal"
+	"github.com/kyma-project/lifecycle-manager/pkg/tracing"
 )
 
 var (
@@ -45,6 +47,8 @@ func (c *ConcurrentDefaultSSA) Run(ctx context.Context, resources []*resource.In
 	ssaStart := time.Now()
 	logger := logf.FromContext(ctx, "owner", c.owner)
 	logger.V(internal.TraceLogLevel).Info("ServerSideApply", "resources", len(resources))
+	ctx, span := tracing.Start(ctx, "ConcurrentSSA.Run",
+		trace.WithAttributes(tracing.ResourceCountKey.Int(len(resources))))
 
 	// The Runtime Complexity of this Branch is N as only ServerSideApplier Patch is required
 	results := make(chan error, len(resources))
@@ -65,12 +69,16 @@ func (c *ConcurrentDefaultSSA) Run(ctx context.Context, resources []*resource.In
 
 	if errs != nil {
 		summaryErr := fmt.Errorf("%w (after %s)", ErrServerSideApplyFailed, ssaFinish)
+		var err error
 		if c.allUnauthorized(errs) {
-			return errors.Join(ErrClientUnauthorized, summaryErr)
+			err = errors.Join(ErrClientUnauthorized, summaryErr)
+		} else {
+			err = errors.Join(append(errs, summaryErr)...)
 		}
-		errs = append(errs, summaryErr)
-		return errors.Join(errs...)
+		tracing.End(span, err)
+		return err
 	}
+	tracing.End(span, nil)
 	logger.V(internal.DebugLogLevel).Info("ServerSideApply finished", "time", ssaFinish)
 	return nil
 }
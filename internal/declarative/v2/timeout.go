@@ -0,0 +1,114 @@
+package v2
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+)
+
+const (
+	timeoutOperationInstall = "install"
+	timeoutOperationDelete  = "delete"
+)
+
+// checkInstallationTimeout sets the processing object to the Error state with the InstallationTimeout reason
+// in the Installation condition if it did not become ready within the duration of its InstallTimeoutAnnotation.
+func (r *Reconciler) checkInstallationTimeout(obj Object, waitingMsg string) {
+	installTimeout, exceeded := installationTimeoutExceeded(obj, time.Now())
+	if installTimeout == 0 {
+		return
+	}
+	r.Metrics.SetTimeoutExceeded(obj.GetLabels()[shared.KymaName], obj.GetLabels()[shared.ModuleName],
+		timeoutOperationInstall, exceeded)
+	if !exceeded {
+		return
+	}
+	status := obj.GetStatus()
+	condition := newInstallationCondition(obj)
+	condition.Reason = string(ConditionReasonInstallationTimeout)
+	condition.Message = fmt.Sprintf("installation did not become ready within %s", installTimeout)
+	if current := meta.FindStatusCondition(status.Conditions, condition.Type); current.Reason != condition.Reason {
+		r.Event(obj, "Warning", condition.Reason, condition.Message)
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+	obj.SetStatus(status.WithState(shared.StateError).WithOperation(condition.Message + ": " + waitingMsg))
+}
+
+// checkDeletionTimeout sets the DeletionTimeout reason in the Deletion condition if the object was not deleted
+// within the duration of its DeleteTimeoutAnnotation. The deletion continues regardless of the timeout,
+// and the status is written together with the result of the deletion steps.
+func (r *Reconciler) checkDeletionTimeout(obj Object) {
+	deleteTimeout, exceeded := deletionTimeoutExceeded(obj, time.Now())
+	if !exceeded {
+		return
+	}
+	r.Metrics.SetTimeoutExceeded(obj.GetLabels()[shared.KymaName], obj.GetLabels()[shared.ModuleName],
+		timeoutOperationDelete, true)
+	status, condition, conditionChanged := withDeletionTimeout(obj.GetStatus(), deleteTimeout, obj.GetGeneration())
+	if conditionChanged {
+		r.Event(obj, "Warning", condition.Reason, condition.Message)
+	}
+	obj.SetStatus(status)
+}
+
+// withDeletionTimeout sets the DeletionTimeout reason in the Deletion condition of the status and reports whether
+// the condition changed. The status is set to the Warning state, which is kept by the deletion steps,
+// unless it is in the Error state, so errors of the deletion are not hidden.
+func withDeletionTimeout(status shared.Status, deleteTimeout time.Duration,
+	generation int64,
+) (shared.Status, apimetav1.Condition, bool) {
+	condition := apimetav1.Condition{
+		Type:               string(ConditionTypeDeletion),
+		Reason:             string(ConditionReasonDeletionTimeout),
+		Status:             apimetav1.ConditionFalse,
+		Message:            fmt.Sprintf("deletion did not finish within %s", deleteTimeout),
+		ObservedGeneration: generation,
+	}
+	conditionChanged := meta.SetStatusCondition(&status.Conditions, condition)
+	if status.State != shared.StateError && status.State != shared.StateWarning {
+		status = status.WithState(shared.StateWarning).WithOperation(condition.Message)
+	}
+	return status, condition, conditionChanged
+}
+
+// installationTimeoutExceeded returns the install timeout of the object and whether it is exceeded.
+// The installation starts when the Installation condition transitions to False, which happens on creation
+// and whenever a ready object becomes processing again.
+func installationTimeoutExceeded(obj Object, now time.Time) (time.Duration, bool) {
+	installTimeout := timeoutFromAnnotation(obj, shared.InstallTimeoutAnnotation)
+	if installTimeout == 0 {
+		return 0, false
+	}
+	condition := meta.FindStatusCondition(obj.GetStatus().Conditions, string(ConditionTypeInstallation))
+	if condition == nil || condition.Status == apimetav1.ConditionTrue {
+		return installTimeout, false
+	}
+	return installTimeout, now.Sub(condition.LastTransitionTime.Time) > installTimeout
+}
+
+// deletionTimeoutExceeded returns the delete timeout of the object and whether it is exceeded.
+func deletionTimeoutExceeded(obj Object, now time.Time) (time.Duration, bool) {
+	deleteTimeout := timeoutFromAnnotation(obj, shared.DeleteTimeoutAnnotation)
+	if deleteTimeout == 0 || obj.GetDeletionTimestamp().IsZero() {
+		return deleteTimeout, false
+	}
+	return deleteTimeout, now.Sub(obj.GetDeletionTimestamp().Time) > deleteTimeout
+}
+
+// timeoutFromAnnotation parses the duration of the timeout annotation. It returns zero if the annotation
+// is not set or invalid, which disables the timeout.
+func timeoutFromAnnotation(obj Object, annotation string) time.Duration {
+	value, found := obj.GetAnnotations()[annotation]
+	if !found {
+		return 0
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
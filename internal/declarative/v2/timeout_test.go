@@ -0,0 +1,146 @@
+//nolint:testpackage // test private functions
+package v2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+)
+
+func TestInstallationTimeoutExceeded(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tests := []struct {
+		name            string
+		timeout         string
+		conditionStatus apimetav1.ConditionStatus
+		processingSince time.Duration
+		expectedTimeout time.Duration
+		expectExceeded  bool
+	}{
+		{"no timeout", "", apimetav1.ConditionFalse, time.Hour, 0, false},
+		{"invalid timeout", "ten minutes", apimetav1.ConditionFalse, time.Hour, 0, false},
+		{"processing within timeout", "10m", apimetav1.ConditionFalse, 5 * time.Minute, 10 * time.Minute, false},
+		{"processing after timeout", "10m", apimetav1.ConditionFalse, 15 * time.Minute, 10 * time.Minute, true},
+		{"installed", "10m", apimetav1.ConditionTrue, 15 * time.Minute, 10 * time.Minute, false},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			manifest := &v1beta2.Manifest{}
+			if testCase.timeout != "" {
+				manifest.SetAnnotations(map[string]string{shared.InstallTimeoutAnnotation: testCase.timeout})
+			}
+			manifest.Status.Conditions = []apimetav1.Condition{{
+				Type:               string(ConditionTypeInstallation),
+				Status:             testCase.conditionStatus,
+				LastTransitionTime: apimetav1.NewTime(now.Add(-testCase.processingSince)),
+			}}
+
+			timeout, exceeded := installationTimeoutExceeded(manifest, now)
+
+			assert.Equal(t, testCase.expectedTimeout, timeout)
+			assert.Equal(t, testCase.expectExceeded, exceeded)
+		})
+	}
+}
+
+func TestDeletionTimeoutExceeded(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	deletedBefore := func(duration time.Duration) *apimetav1.Time {
+		deletionTimestamp := apimetav1.NewTime(now.Add(-duration))
+		return &deletionTimestamp
+	}
+	tests := []struct {
+		name              string
+		timeout           string
+		deletionTimestamp *apimetav1.Time
+		expectExceeded    bool
+	}{
+		{"no timeout", "", deletedBefore(time.Hour), false},
+		{"not deleted", "5m", nil, false},
+		{"deleting within timeout", "5m", deletedBefore(time.Minute), false},
+		{"deleting after timeout", "5m", deletedBefore(10 * time.Minute), true},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			manifest := &v1beta2.Manifest{}
+			manifest.SetDeletionTimestamp(testCase.deletionTimestamp)
+			if testCase.timeout != "" {
+				manifest.SetAnnotations(map[string]string{shared.DeleteTimeoutAnnotation: testCase.timeout})
+			}
+
+			_, exceeded := deletionTimeoutExceeded(manifest, now)
+
+			assert.Equal(t, testCase.expectExceeded, exceeded)
+		})
+	}
+}
+
+func TestWithDeletionTimeout(t *testing.T) {
+	t.Parallel()
+	timedOut := apimetav1.Condition{
+		Type:    string(ConditionTypeDeletion),
+		Reason:  string(ConditionReasonDeletionTimeout),
+		Status:  apimetav1.ConditionFalse,
+		Message: "deletion did not finish within 5m0s",
+	}
+	tests := []struct {
+		name                   string
+		status                 shared.Status
+		expectedState          shared.State
+		expectedOperation      string
+		expectConditionChanged bool
+	}{
+		{
+			"deleting",
+			shared.Status{State: shared.StateDeleting, LastOperation: shared.LastOperation{Operation: "deleting"}},
+			shared.StateWarning,
+			timedOut.Message,
+			true,
+		},
+		{
+			"deletion failed",
+			shared.Status{State: shared.StateError, LastOperation: shared.LastOperation{Operation: "apply failed"}},
+			shared.StateError,
+			"apply failed",
+			true,
+		},
+		{
+			"timeout already reported",
+			shared.Status{
+				State:         shared.StateWarning,
+				LastOperation: shared.LastOperation{Operation: "waiting as other finalizers are present"},
+				Conditions:    []apimetav1.Condition{timedOut},
+			},
+			shared.StateWarning,
+			"waiting as other finalizers are present",
+			false,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			status, condition, conditionChanged := withDeletionTimeout(testCase.status, 5*time.Minute, 0)
+
+			assert.Equal(t, testCase.expectedState, status.State)
+			assert.Equal(t, testCase.expectedOperation, status.Operation)
+			assert.Equal(t, testCase.expectConditionChanged, conditionChanged)
+			assert.Equal(t, timedOut.Reason, condition.Reason)
+			assert.Equal(t, timedOut.Reason,
+				meta.FindStatusCondition(status.Conditions, string(ConditionTypeDeletion)).Reason)
+		})
+	}
+}
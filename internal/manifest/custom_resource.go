@@ -1,15 +1,22 @@
 package manifest
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 
+	"github.com/kyma-project/lifecycle-manager/api/shared"
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
 	declarativev2 "github.com/kyma-project/lifecycle-manager/internal/declarative/v2"
 	"github.com/kyma-project/lifecycle-manager/pkg/util"
@@ -22,8 +29,19 @@ var (
 		"deletion of custom resource definition was triggered and is now waiting to be completed")
 )
 
+const (
+	// CustomResourceFieldOwner is the field manager that applies the default custom resource of Manifests
+	// with the Reconcile CustomResourcePolicy.
+	CustomResourceFieldOwner client.FieldOwner = "lifecycle-manager-module-cr"
+	// ModuleConfigFieldOwner is the field manager that only applies the module config to the custom resource.
+	// Its managed fields record the keys of the last applied module config.
+	ModuleConfigFieldOwner client.FieldOwner = "lifecycle-manager-module-config"
+)
+
 // PostRunCreateCR is a hook for creating the manifest default custom resource if not available in the cluster
 // It is used to provide the controller with default data in the Runtime.
+// If the custom resource exists, only the fields of the module config are applied to it,
+// unless the Manifest has the Reconcile CustomResourcePolicy, in which case the complete resource is applied.
 func PostRunCreateCR(
 	ctx context.Context, skr declarativev2.Client, kcp client.Client, obj declarativev2.Object,
 ) error {
@@ -38,10 +56,8 @@ func PostRunCreateCR(
 		return nil
 	}
 
-	resource := manifest.Spec.Resource.DeepCopy()
-	err := skr.Create(ctx, resource, client.FieldOwner(declarativev2.CustomResourceManager))
-	if err != nil && !apierrors.IsAlreadyExists(err) {
-		return fmt.Errorf("failed to create resource: %w", err)
+	if err := createOrApplyCR(ctx, skr, kcp, manifest); err != nil {
+		return err
 	}
 
 	oMeta := &apimetav1.PartialObjectMetadata{}
@@ -60,6 +76,157 @@ func PostRunCreateCR(
 	return nil
 }
 
+// DryRunModuleCR returns the module CR of the Manifest, so it is part of the dry-run of the Manifest,
+// although it is created by PostRunCreateCR instead of being rendered.
+func DryRunModuleCR(obj declarativev2.Object) []*unstructured.Unstructured {
+	manifest, ok := obj.(*v1beta2.Manifest)
+	if !ok || manifest.Spec.Resource == nil {
+		return nil
+	}
+	return []*unstructured.Unstructured{manifest.Spec.Resource.DeepCopy()}
+}
+
+func createOrApplyCR(ctx context.Context, skr declarativev2.Client, kcp client.Client,
+	manifest *v1beta2.Manifest,
+) error {
+	resource := manifest.Spec.Resource.DeepCopy()
+	if manifest.GetAnnotations()[shared.CustomResourcePolicyAnnotation] == v1beta2.CustomResourcePolicyReconcile {
+		if err := skr.Patch(ctx, resource, client.Apply, client.ForceOwnership, CustomResourceFieldOwner); err != nil {
+			return fmt.Errorf("failed to apply resource: %w", err)
+		}
+		return nil
+	}
+	err := skr.Create(ctx, resource, client.FieldOwner(declarativev2.CustomResourceManager))
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create resource: %w", err)
+	}
+	// the module CR is only read if a module config is set, or was applied before and has to be reset
+	moduleConfig, configured := manifest.GetAnnotations()[shared.ModuleConfigAnnotation]
+	applied := manifest.GetAnnotations()[shared.ModuleConfigAppliedAnnotation] == "true"
+	if !configured && !applied {
+		return nil
+	}
+	if err := applyModuleConfig(ctx, skr, manifest, moduleConfig); err != nil {
+		return err
+	}
+	if configured != applied {
+		return markModuleConfigApplied(ctx, kcp, manifest, configured)
+	}
+	return nil
+}
+
+// applyModuleConfig applies the fields of the module config to the module CR, so changes of the config
+// reach the module CR, while all other fields can still be changed in the runtime.
+// Fields of a previously applied config that are no longer part of the config are reset to the default
+// of the module CR, as the create of the module CR still co-owns them and they would otherwise keep their value.
+func applyModuleConfig(ctx context.Context, skr declarativev2.Client, manifest *v1beta2.Manifest,
+	moduleConfig string,
+) error {
+	config := &unstructured.Unstructured{Object: map[string]any{}}
+	if moduleConfig != "" {
+		if err := json.Unmarshal([]byte(moduleConfig), &config.Object); err != nil {
+			return fmt.Errorf("failed to parse module config: %w", err)
+		}
+	}
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(manifest.Spec.Resource.GroupVersionKind())
+	if err := skr.Get(ctx, client.ObjectKeyFromObject(manifest.Spec.Resource), existing); err != nil {
+		return fmt.Errorf("failed to get resource: %w", err)
+	}
+	previous, err := appliedConfigFields(existing)
+	if err != nil {
+		return err
+	}
+	if len(previous) == 0 && len(config.Object) == 0 {
+		return nil
+	}
+
+	config.SetGroupVersionKind(manifest.Spec.Resource.GroupVersionKind())
+	config.SetName(manifest.Spec.Resource.GetName())
+	config.SetNamespace(manifest.Spec.Resource.GetNamespace())
+	if err := skr.Patch(ctx, config, client.Apply, client.ForceOwnership, ModuleConfigFieldOwner); err != nil {
+		return fmt.Errorf("failed to apply module config: %w", err)
+	}
+	return resetRemovedConfigFields(ctx, skr, manifest.Spec.Resource, config, previous)
+}
+
+// markModuleConfigApplied sets or removes the ModuleConfigAppliedAnnotation of the Manifest.
+func markModuleConfigApplied(ctx context.Context, kcp client.Client, manifest *v1beta2.Manifest, applied bool) error {
+	oMeta := &apimetav1.PartialObjectMetadata{}
+	oMeta.SetName(manifest.GetName())
+	oMeta.SetGroupVersionKind(v1beta2.GroupVersion.WithKind(string(shared.ManifestKind)))
+	oMeta.SetNamespace(manifest.GetNamespace())
+	if applied {
+		oMeta.SetAnnotations(map[string]string{shared.ModuleConfigAppliedAnnotation: "true"})
+	}
+	if err := kcp.Patch(ctx, oMeta, client.Apply, client.ForceOwnership, ModuleConfigFieldOwner); err != nil {
+		return fmt.Errorf("failed to mark module config of manifest as applied: %w", err)
+	}
+	return nil
+}
+
+// appliedConfigFields returns the paths of the fields of the module CR that are owned by the ModuleConfigFieldOwner.
+// Fields inside of lists are returned as the path of the list, as lists are reset as a whole.
+func appliedConfigFields(resource *unstructured.Unstructured) ([][]string, error) {
+	var fields [][]string
+	seen := map[string]bool{}
+	for _, managedFields := range resource.GetManagedFields() {
+		if managedFields.Manager != string(ModuleConfigFieldOwner) || managedFields.FieldsV1 == nil {
+			continue
+		}
+		owned := &fieldpath.Set{}
+		if err := owned.FromJSON(bytes.NewReader(managedFields.FieldsV1.Raw)); err != nil {
+			return nil, fmt.Errorf("failed to parse managed fields of module config: %w", err)
+		}
+		owned.Leaves().Iterate(func(path fieldpath.Path) {
+			var names []string
+			for _, element := range path {
+				if element.FieldName == nil {
+					break
+				}
+				names = append(names, *element.FieldName)
+			}
+			if key := strings.Join(names, "."); len(names) > 0 && !seen[key] {
+				seen[key] = true
+				fields = append(fields, names)
+			}
+		})
+	}
+	return fields, nil
+}
+
+// resetRemovedConfigFields sets the previously applied fields that are not part of the config anymore to their value
+// in the default module CR, or removes them if the default does not have them.
+func resetRemovedConfigFields(ctx context.Context, skr declarativev2.Client, defaults *unstructured.Unstructured,
+	config *unstructured.Unstructured, previous [][]string,
+) error {
+	reset := map[string]any{}
+	for _, path := range previous {
+		if path[0] == "apiVersion" || path[0] == "kind" || path[0] == "metadata" {
+			continue
+		}
+		if _, configured, _ := unstructured.NestedFieldNoCopy(config.Object, path...); configured {
+			continue
+		}
+		value, _, _ := unstructured.NestedFieldCopy(defaults.Object, path...)
+		if err := unstructured.SetNestedField(reset, value, path...); err != nil {
+			return fmt.Errorf("failed to reset module config field %s: %w", strings.Join(path, "."), err)
+		}
+	}
+	if len(reset) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(reset)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reset of module config: %w", err)
+	}
+	if err := skr.Patch(ctx, config, client.RawPatch(types.MergePatchType, patch),
+		client.FieldOwner(declarativev2.CustomResourceManager)); err != nil {
+		return fmt.Errorf("failed to reset removed module config fields: %w", err)
+	}
+	return nil
+}
+
 // PreDeleteDeleteCR is a hook for deleting the manifest default custom resource if available in the cluster
 // It is used to clean up the controller default data.
 // It uses DeletePropagationBackground as it will return an error if the resource exists, even if deletion is triggered
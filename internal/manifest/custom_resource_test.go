@@ -0,0 +1,228 @@
+package manifest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	machineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	declarativev2 "github.com/kyma-project/lifecycle-manager/internal/declarative/v2"
+	"github.com/kyma-project/lifecycle-manager/internal/manifest"
+)
+
+type skrClient struct {
+	client.Client
+	resource.RESTClientGetter
+	declarativev2.ResourceInfoConverter
+}
+
+type appliedPatch struct {
+	object    *unstructured.Unstructured
+	patchType types.PatchType
+	data      []byte
+	owner     string
+}
+
+func newModuleCR() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "operator.kyma-project.io/v1alpha1",
+		"kind":       "Sample",
+		"metadata":   map[string]any{"name": "sample", "namespace": "kyma-system"},
+		"spec":       map[string]any{"replicas": int64(1), "logLevel": "info"},
+	}}
+}
+
+// postRunResult records the patches applied to the runtime, the number of reads of the module CR,
+// and the annotations applied to the Manifest in the control plane.
+type postRunResult struct {
+	patches             []appliedPatch
+	reads               int
+	manifestAnnotations []map[string]string
+}
+
+// runPostRunCreateCR runs the hook against a runtime with the existing objects.
+func runPostRunCreateCR(t *testing.T, manifestObj *v1beta2.Manifest, existing ...client.Object) postRunResult {
+	t.Helper()
+	var result postRunResult
+	skr := fake.NewClientBuilder().WithScheme(machineryruntime.NewScheme()).WithObjects(existing...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, clnt client.WithWatch, key client.ObjectKey, obj client.Object,
+				opts ...client.GetOption,
+			) error {
+				result.reads++
+				return clnt.Get(ctx, key, obj, opts...)
+			},
+			Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, patch client.Patch,
+				opts ...client.PatchOption,
+			) error {
+				patchOptions := &client.PatchOptions{}
+				patchOptions.ApplyOptions(opts)
+				unstructuredObj, ok := obj.(*unstructured.Unstructured)
+				require.True(t, ok)
+				data, err := patch.Data(obj)
+				require.NoError(t, err)
+				result.patches = append(result.patches, appliedPatch{
+					object: unstructuredObj.DeepCopy(), patchType: patch.Type(), data: data, owner: patchOptions.FieldManager,
+				})
+				return nil
+			},
+		}).Build()
+	kcpScheme := machineryruntime.NewScheme()
+	require.NoError(t, v1beta2.AddToScheme(kcpScheme))
+	manifestObj.SetFinalizers([]string{declarativev2.CustomResourceManager})
+	kcp := fake.NewClientBuilder().WithScheme(kcpScheme).WithObjects(manifestObj).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch,
+				_ ...client.PatchOption,
+			) error {
+				result.manifestAnnotations = append(result.manifestAnnotations, obj.GetAnnotations())
+				return nil
+			},
+		}).Build()
+
+	require.NoError(t, manifest.PostRunCreateCR(context.Background(), skrClient{Client: skr}, kcp, manifestObj))
+	return result
+}
+
+func TestPostRunCreateCR_CreateAndDeleteKeepsExistingCR(t *testing.T) {
+	t.Parallel()
+	existing := newModuleCR()
+	existing.Object["spec"] = map[string]any{"replicas": int64(5)}
+	manifestObj := &v1beta2.Manifest{Spec: v1beta2.ManifestSpec{Resource: newModuleCR()}}
+	manifestObj.SetName("manifest")
+
+	result := runPostRunCreateCR(t, manifestObj, existing)
+
+	assert.Empty(t, result.patches)
+	assert.Zero(t, result.reads)
+	assert.Empty(t, result.manifestAnnotations)
+}
+
+func TestPostRunCreateCR_CreateAndDeleteAppliesModuleConfig(t *testing.T) {
+	t.Parallel()
+	manifestObj := &v1beta2.Manifest{Spec: v1beta2.ManifestSpec{Resource: newModuleCR()}}
+	manifestObj.SetName("manifest")
+	manifestObj.SetAnnotations(map[string]string{shared.ModuleConfigAnnotation: `{"spec":{"replicas":3}}`})
+
+	result := runPostRunCreateCR(t, manifestObj, newModuleCR())
+	patches := result.patches
+
+	assert.Equal(t, []map[string]string{{shared.ModuleConfigAppliedAnnotation: "true"}}, result.manifestAnnotations)
+	require.Len(t, patches, 1)
+	assert.Equal(t, types.ApplyPatchType, patches[0].patchType)
+	assert.Equal(t, string(manifest.ModuleConfigFieldOwner), patches[0].owner)
+	assert.Equal(t, map[string]any{"replicas": int64(3)}, patches[0].object.Object["spec"])
+	assert.Equal(t, "sample", patches[0].object.GetName())
+	assert.Equal(t, "Sample", patches[0].object.GetKind())
+}
+
+func TestPostRunCreateCR_CreateAndDeleteResetsRemovedModuleConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name                        string
+		moduleConfig                string
+		expectedApply               map[string]any
+		expectedReset               string
+		expectedManifestAnnotations []map[string]string
+	}{
+		{
+			"removed key with default",
+			`{"spec":{"replicas":3}}`,
+			map[string]any{"replicas": int64(3)},
+			`{"spec":{"logLevel":"info","debug":null}}`,
+			nil,
+		},
+		{
+			"removed key without default",
+			`{"spec":{"replicas":3,"logLevel":"debug"}}`,
+			map[string]any{"replicas": int64(3), "logLevel": "debug"},
+			`{"spec":{"debug":null}}`,
+			nil,
+		},
+		{
+			"removed config",
+			"",
+			nil,
+			`{"spec":{"debug":null,"logLevel":"info","replicas":1}}`,
+			[]map[string]string{nil},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			existing := newModuleCR()
+			existing.SetManagedFields([]apimetav1.ManagedFieldsEntry{
+				{
+					Manager:    string(manifest.ModuleConfigFieldOwner),
+					Operation:  apimetav1.ManagedFieldsOperationApply,
+					FieldsType: "FieldsV1",
+					FieldsV1: &apimetav1.FieldsV1{
+						Raw: []byte(`{"f:spec":{"f:replicas":{},"f:logLevel":{},"f:debug":{}}}`),
+					},
+				},
+			})
+			manifestObj := &v1beta2.Manifest{Spec: v1beta2.ManifestSpec{Resource: newModuleCR()}}
+			manifestObj.SetName("manifest")
+			annotations := map[string]string{shared.ModuleConfigAppliedAnnotation: "true"}
+			if testCase.moduleConfig != "" {
+				annotations[shared.ModuleConfigAnnotation] = testCase.moduleConfig
+			}
+			manifestObj.SetAnnotations(annotations)
+
+			result := runPostRunCreateCR(t, manifestObj, existing)
+			patches := result.patches
+
+			assert.Equal(t, testCase.expectedManifestAnnotations, result.manifestAnnotations)
+			require.Len(t, patches, 2)
+			assert.Equal(t, types.ApplyPatchType, patches[0].patchType)
+			assert.Equal(t, string(manifest.ModuleConfigFieldOwner), patches[0].owner)
+			if testCase.expectedApply == nil {
+				assert.NotContains(t, patches[0].object.Object, "spec")
+			} else {
+				assert.Equal(t, testCase.expectedApply, patches[0].object.Object["spec"])
+			}
+			assert.Equal(t, types.MergePatchType, patches[1].patchType)
+			assert.Equal(t, declarativev2.CustomResourceManager, patches[1].owner)
+			assert.JSONEq(t, testCase.expectedReset, string(patches[1].data))
+		})
+	}
+}
+
+func TestPostRunCreateCR_ReconcileAppliesResource(t *testing.T) {
+	t.Parallel()
+	manifestObj := &v1beta2.Manifest{Spec: v1beta2.ManifestSpec{Resource: newModuleCR()}}
+	manifestObj.SetName("manifest")
+	manifestObj.SetAnnotations(map[string]string{
+		shared.CustomResourcePolicyAnnotation: v1beta2.CustomResourcePolicyReconcile,
+	})
+
+	patches := runPostRunCreateCR(t, manifestObj, newModuleCR()).patches
+
+	require.Len(t, patches, 1)
+	assert.Equal(t, string(manifest.CustomResourceFieldOwner), patches[0].owner)
+	assert.Equal(t, newModuleCR(), patches[0].object)
+}
+
+func TestDryRunModuleCR(t *testing.T) {
+	t.Parallel()
+	manifestObj := &v1beta2.Manifest{Spec: v1beta2.ManifestSpec{Resource: newModuleCR()}}
+
+	resources := manifest.DryRunModuleCR(manifestObj)
+
+	require.Len(t, resources, 1)
+	assert.Equal(t, newModuleCR(), resources[0])
+	assert.NotSame(t, manifestObj.Spec.Resource, resources[0])
+	assert.Empty(t, manifest.DryRunModuleCR(&v1beta2.Manifest{}))
+}
@@ -0,0 +1,108 @@
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	containerregistryv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/kyma-project/lifecycle-manager/internal/manifest/layercache"
+)
+
+var (
+	gzipMagicHeader = []byte{0x1f, 0x8b}
+	zstdMagicHeader = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// layerBlob reads the blob of a layer and verifies it against the digest the layer is referenced by.
+// The digest covers the blob as it is stored in the registry, so it is calculated before decompression.
+type layerBlob struct {
+	io.Reader
+	compressed io.ReadCloser
+	hashed     io.Reader
+	hasher     hash.Hash
+	expected   containerregistryv1.Hash
+	closeFn    func()
+}
+
+// openLayerBlob opens the blob of the layer. If uncompressed is set, gzip and zstd compressed blobs
+// are decompressed, blobs without a known compression are read as they are.
+func openLayerBlob(layer containerregistryv1.Layer, digest string, uncompressed bool) (*layerBlob, error) {
+	expected, err := containerregistryv1.NewHash(digest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid layer digest %s: %w", digest, err)
+	}
+	hasher, err := containerregistryv1.Hasher(expected.Algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported layer digest %s: %w", digest, err)
+	}
+	compressed, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	blob := &layerBlob{
+		compressed: compressed,
+		hashed:     io.TeeReader(compressed, hasher),
+		hasher:     hasher,
+		expected:   expected,
+	}
+	blob.Reader = blob.hashed
+	if uncompressed {
+		if err := blob.decompress(); err != nil {
+			_ = compressed.Close()
+			return nil, err
+		}
+	}
+	return blob, nil
+}
+
+func (b *layerBlob) decompress() error {
+	buffered := bufio.NewReader(b.hashed)
+	header, err := buffered.Peek(len(zstdMagicHeader))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to read layer header: %w", err)
+	}
+	switch {
+	case bytes.HasPrefix(header, gzipMagicHeader):
+		gzipReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return fmt.Errorf("failed to decompress layer: %w", err)
+		}
+		b.Reader = gzipReader
+	case bytes.HasPrefix(header, zstdMagicHeader):
+		zstdReader, err := zstd.NewReader(buffered)
+		if err != nil {
+			return fmt.Errorf("failed to decompress layer: %w", err)
+		}
+		b.Reader, b.closeFn = zstdReader, zstdReader.Close
+	default:
+		b.Reader = buffered
+	}
+	return nil
+}
+
+// Verify reads the rest of the blob and compares its digest with the digest the layer is referenced by.
+func (b *layerBlob) Verify() error {
+	if _, err := io.Copy(io.Discard, b.hashed); err != nil {
+		return fmt.Errorf("failed to read layer: %w", err)
+	}
+	actual := containerregistryv1.Hash{Algorithm: b.expected.Algorithm, Hex: hex.EncodeToString(b.hasher.Sum(nil))}
+	if actual != b.expected {
+		return fmt.Errorf("%w: expected %s, got %s", layercache.ErrCorruptContent, b.expected, actual)
+	}
+	return nil
+}
+
+func (b *layerBlob) Close() error {
+	if b.closeFn != nil {
+		b.closeFn()
+	}
+	return b.compressed.Close()
+}
@@ -0,0 +1,430 @@
+package layercache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/lifecycle-manager/internal/pkg/metrics"
+)
+
+const (
+	completeFileSuffix = ".complete"
+	tempFilePrefix     = ".tmp-"
+)
+
+var (
+	ErrInvalidMaxSize = errors.New("maximum size of the layer cache must be positive")
+	// ErrCorruptContent is wrapped by a FillFn if the content does not match the digest it is referenced by.
+	ErrCorruptContent = errors.New("layer content does not match its digest")
+)
+
+// FillFn writes the content of a cache entry to the given path, either as a file or as a directory.
+type FillFn func(path string) error
+
+// Cache is a persistent on-disk cache for module layers.
+// Every entry is written to a temporary path first and renamed afterward, followed by a marker file
+// that flags the entry as complete and holds the checksum of its content. The content is verified
+// against the checksum whenever the entry is read, so entries that were changed on disk are pulled again.
+// Entries are evicted in least recently used order as soon as the total size of all entries exceeds
+// the maximum size, except for entries that are still in use.
+type Cache struct {
+	dir     string
+	maxSize int64
+	metrics *metrics.LayerCacheMetrics
+
+	mu      sync.Mutex
+	size    int64
+	entries map[string]*list.Element
+	lru     *list.List
+	locks   map[string]*entryLock
+}
+
+type entry struct {
+	name       string
+	size       int64
+	checksum   string
+	pins       int
+	lastAccess time.Time
+}
+
+// entryLock serializes the calls for the same entry. It is only kept while calls for the entry are running.
+type entryLock struct {
+	sync.Mutex
+	users int
+}
+
+// New creates a Cache in the given directory and restores the entries left by a previous process.
+// Leftovers of interrupted writes are removed. The metrics are optional.
+func New(dir string, maxSize int64, cacheMetrics *metrics.LayerCacheMetrics) (*Cache, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidMaxSize, maxSize)
+	}
+	if err := os.MkdirAll(dir, fs.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create layer cache directory %s: %w", dir, err)
+	}
+	cache := &Cache{
+		dir:     dir,
+		maxSize: maxSize,
+		metrics: cacheMetrics,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		locks:   make(map[string]*entryLock),
+	}
+	if err := cache.restore(); err != nil {
+		return nil, err
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.evict()
+	return cache, nil
+}
+
+// Get returns the path of the entry with the given key. If the entry does not exist yet, or its content
+// does not match its checksum anymore, it is created with fill.
+// The entry is pinned until the returned release function is called, so it is not evicted while the caller
+// still reads it. Concurrent calls for the same key are serialized.
+func (c *Cache) Get(key string, fill FillFn) (string, func(), error) {
+	name := entryName(key)
+	lock := c.lock(name)
+	defer c.unlock(name, lock)
+
+	entryPath := filepath.Join(c.dir, name)
+	if c.pin(name) {
+		if c.verify(name) {
+			c.recordHit()
+			return entryPath, c.releaseFor(name), nil
+		}
+		c.recordCorrupt()
+		c.unpin(name)
+	}
+
+	err := c.fill(name, fill)
+	if errors.Is(err, ErrCorruptContent) {
+		c.recordCorrupt()
+	} else {
+		c.recordMiss()
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return entryPath, c.releaseFor(name), nil
+}
+
+// Size returns the total size of all entries in bytes.
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+func (c *Cache) fill(name string, fill FillFn) error {
+	tempPath, err := os.MkdirTemp(c.dir, tempFilePrefix+name)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary path for layer %s: %w", name, err)
+	}
+	defer os.RemoveAll(tempPath)
+
+	content := filepath.Join(tempPath, name)
+	if err := fill(content); err != nil {
+		return err
+	}
+	size, err := sizeOf(content)
+	if err != nil {
+		return err
+	}
+	checksum, err := checksumOf(content)
+	if err != nil {
+		return err
+	}
+
+	// the content is renamed before the marker file is written, so an interrupted rename never leaves
+	// a marker for a missing or incomplete entry behind.
+	entryPath := filepath.Join(c.dir, name)
+	if err := os.RemoveAll(entryPath); err != nil {
+		return fmt.Errorf("failed to remove stale layer %s: %w", name, err)
+	}
+	if err := os.Rename(content, entryPath); err != nil {
+		return fmt.Errorf("failed to move layer %s into cache: %w", name, err)
+	}
+	if err := os.WriteFile(entryPath+completeFileSuffix, []byte(checksum), fs.ModePerm); err != nil {
+		return fmt.Errorf("failed to mark layer %s as complete: %w", name, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(&entry{name: name, size: size, checksum: checksum, pins: 1, lastAccess: time.Now()})
+	c.evict()
+	return nil
+}
+
+// pin marks the entry as used and moves it to the front of the LRU list. It reports whether the entry exists.
+func (c *Cache) pin(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, found := c.entries[name]
+	if !found {
+		return false
+	}
+	cached, _ := element.Value.(*entry)
+	cached.pins++
+	cached.lastAccess = time.Now()
+	c.lru.MoveToFront(element)
+	return true
+}
+
+// verify reports whether the content of the entry still matches the checksum it was stored with.
+func (c *Cache) verify(name string) bool {
+	c.mu.Lock()
+	element, found := c.entries[name]
+	if !found {
+		c.mu.Unlock()
+		return false
+	}
+	expected := element.Value.(*entry).checksum //nolint:forcetypeassert // the list only contains entries
+	c.mu.Unlock()
+
+	actual, err := checksumOf(filepath.Join(c.dir, name))
+	return err == nil && actual == expected
+}
+
+// releaseFor returns a function that unpins the entry once, and evicts entries that were kept while it was used.
+func (c *Cache) releaseFor(name string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() { c.unpin(name) })
+	}
+}
+
+func (c *Cache) unpin(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, found := c.entries[name]; found {
+		element.Value.(*entry).pins-- //nolint:forcetypeassert // the list only contains entries
+	}
+	c.evict()
+}
+
+// add inserts the entry at the front of the LRU list. An entry with the same name is replaced,
+// keeping the pins of callers that still read it.
+func (c *Cache) add(cached *entry) {
+	if element, found := c.entries[cached.name]; found {
+		replaced := element.Value.(*entry) //nolint:forcetypeassert // the list only contains entries
+		c.size -= replaced.size
+		cached.pins += replaced.pins
+		c.lru.Remove(element)
+	}
+	c.entries[cached.name] = c.lru.PushFront(cached)
+	c.size += cached.size
+	c.recordSize()
+}
+
+func (c *Cache) removeLocked(name string) {
+	if element, found := c.entries[name]; found {
+		c.size -= element.Value.(*entry).size //nolint:forcetypeassert // the list only contains entries
+		c.lru.Remove(element)
+		delete(c.entries, name)
+	}
+	entryPath := filepath.Join(c.dir, name)
+	_ = os.Remove(entryPath + completeFileSuffix)
+	_ = os.RemoveAll(entryPath)
+	c.recordSize()
+}
+
+// evict removes the least recently used entries until the cache fits its maximum size.
+// Pinned entries and entries with running calls are skipped, they are evicted by a later call.
+// The most recently used entry is never evicted, even if it exceeds the maximum size on its own.
+func (c *Cache) evict() {
+	element := c.lru.Back()
+	for element != nil && element != c.lru.Front() && c.size > c.maxSize {
+		previous := element.Prev()
+		cached, _ := element.Value.(*entry)
+		if _, inUse := c.locks[cached.name]; cached.pins == 0 && !inUse {
+			c.removeLocked(cached.name)
+			c.recordEviction()
+		}
+		element = previous
+	}
+}
+
+// restore rebuilds the index from the cache directory, ordered by the last modification of the entries.
+func (c *Cache) restore() error {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read layer cache directory %s: %w", c.dir, err)
+	}
+	names := make(map[string]bool, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		names[dirEntry.Name()] = true
+	}
+
+	restored := make([]*entry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		entryPath := filepath.Join(c.dir, name)
+		switch {
+		case strings.HasPrefix(name, tempFilePrefix):
+			_ = os.RemoveAll(entryPath)
+		case strings.HasSuffix(name, completeFileSuffix):
+			if !names[strings.TrimSuffix(name, completeFileSuffix)] {
+				_ = os.Remove(entryPath)
+			}
+		case !names[name+completeFileSuffix]:
+			_ = os.RemoveAll(entryPath)
+		default:
+			if cached, err := restoreEntry(c.dir, dirEntry); err == nil {
+				restored = append(restored, cached)
+			}
+		}
+	}
+
+	sort.Slice(restored, func(i, j int) bool {
+		return restored[i].lastAccess.Before(restored[j].lastAccess)
+	})
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cached := range restored {
+		c.add(cached)
+	}
+	return nil
+}
+
+func restoreEntry(dir string, dirEntry fs.DirEntry) (*entry, error) {
+	entryPath := filepath.Join(dir, dirEntry.Name())
+	size, err := sizeOf(entryPath)
+	if err != nil {
+		return nil, err
+	}
+	// markers without a checksum never match, so their entries are pulled again on the next read
+	checksum, err := os.ReadFile(entryPath + completeFileSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read marker of layer %s: %w", dirEntry.Name(), err)
+	}
+	info, err := dirEntry.Info()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read layer %s: %w", dirEntry.Name(), err)
+	}
+	return &entry{name: dirEntry.Name(), size: size, checksum: string(checksum), lastAccess: info.ModTime()}, nil
+}
+
+// lock acquires the lock of the entry, which is shared by all concurrent calls for the entry.
+func (c *Cache) lock(name string) *entryLock {
+	c.mu.Lock()
+	lock, found := c.locks[name]
+	if !found {
+		lock = &entryLock{}
+		c.locks[name] = lock
+	}
+	lock.users++
+	c.mu.Unlock()
+
+	lock.Lock()
+	return lock
+}
+
+// unlock releases the lock of the entry and drops it once no other call for the entry is waiting for it.
+func (c *Cache) unlock(name string, lock *entryLock) {
+	lock.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lock.users--
+	if lock.users == 0 {
+		delete(c.locks, name)
+	}
+}
+
+func sizeOf(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if dirEntry.IsDir() {
+			return nil
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to calculate size of %s: %w", path, err)
+	}
+	return size, nil
+}
+
+// checksumOf hashes the paths and contents of all files of the entry, so both files and directories can be verified.
+func checksumOf(path string) (string, error) {
+	hash := sha256.New()
+	err := filepath.WalkDir(path, func(filePath string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if dirEntry.IsDir() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(path, filePath)
+		if err != nil {
+			return err
+		}
+		_, _ = hash.Write([]byte(filepath.ToSlash(relativePath) + "\x00"))
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(hash, file)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate checksum of %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// entryName converts a key into a name that can be used as a single path element.
+func entryName(key string) string {
+	return strings.NewReplacer("/", "_", ":", "_", "@", "_", "\\", "_").Replace(key)
+}
+
+func (c *Cache) recordHit() {
+	if c.metrics != nil {
+		c.metrics.RecordHit()
+	}
+}
+
+func (c *Cache) recordMiss() {
+	if c.metrics != nil {
+		c.metrics.RecordMiss()
+	}
+}
+
+func (c *Cache) recordCorrupt() {
+	if c.metrics != nil {
+		c.metrics.RecordCorrupt()
+	}
+}
+
+func (c *Cache) recordEviction() {
+	if c.metrics != nil {
+		c.metrics.RecordEviction()
+	}
+}
+
+func (c *Cache) recordSize() {
+	if c.metrics != nil {
+		c.metrics.SetSize(c.size)
+	}
+}
@@ -0,0 +1,239 @@
+package layercache_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/lifecycle-manager/internal/manifest/layercache"
+)
+
+var errPullFailed = errors.New("pull failed")
+
+type fileFiller struct {
+	content string
+	calls   int
+}
+
+func (f *fileFiller) fill(path string) error {
+	f.calls++
+	return os.WriteFile(path, []byte(f.content), 0o600)
+}
+
+func TestCache_Get(t *testing.T) {
+	t.Parallel()
+	cache, err := layercache.New(t.TempDir(), 1024, nil)
+	require.NoError(t, err)
+	filler := &fileFiller{content: "raw manifest"}
+
+	path, release, err := cache.Get("template-sha256:abc-raw-manifest.yaml", filler.fill)
+	require.NoError(t, err)
+	release()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "raw manifest", string(content))
+	assert.Equal(t, "template-sha256_abc-raw-manifest.yaml", filepath.Base(path))
+
+	cachedPath, release, err := cache.Get("template-sha256:abc-raw-manifest.yaml", filler.fill)
+	require.NoError(t, err)
+	release()
+	assert.Equal(t, path, cachedPath)
+	assert.Equal(t, 1, filler.calls)
+	assert.Equal(t, int64(len("raw manifest")), cache.Size())
+}
+
+func TestCache_Get_CorruptContentLeavesNoEntry(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	cache, err := layercache.New(dir, 1024, nil)
+	require.NoError(t, err)
+
+	_, _, err = cache.Get("layer", func(path string) error {
+		_ = os.WriteFile(path, []byte("tampered"), 0o600)
+		return fmt.Errorf("verification failed: %w", layercache.ErrCorruptContent)
+	})
+	require.ErrorIs(t, err, layercache.ErrCorruptContent)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestCache_Get_RefillsChangedContent(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"truncated content", "raw"},
+		{"tampered content", "raw manifest, tampered"},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			cache, err := layercache.New(dir, 1024, nil)
+			require.NoError(t, err)
+			filler := &fileFiller{content: "raw manifest"}
+			path, release, err := cache.Get("layer", filler.fill)
+			require.NoError(t, err)
+			release()
+			restored, err := layercache.New(dir, 1024, nil)
+			require.NoError(t, err)
+
+			for _, cached := range []*layercache.Cache{cache, restored} {
+				require.NoError(t, os.WriteFile(path, []byte(testCase.content), 0o600))
+				_, release, err = cached.Get("layer", filler.fill)
+				require.NoError(t, err)
+				release()
+				content, err := os.ReadFile(path)
+				require.NoError(t, err)
+				assert.Equal(t, "raw manifest", string(content))
+				assert.Equal(t, int64(len("raw manifest")), cached.Size())
+			}
+			assert.Equal(t, 3, filler.calls)
+		})
+	}
+}
+
+func TestCache_Get_FailedFillLeavesNoEntry(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	cache, err := layercache.New(dir, 1024, nil)
+	require.NoError(t, err)
+
+	_, _, err = cache.Get("layer", func(path string) error {
+		_ = os.WriteFile(path, []byte("partial"), 0o600)
+		return errPullFailed
+	})
+	require.ErrorIs(t, err, errPullFailed)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.Zero(t, cache.Size())
+}
+
+func TestCache_Get_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	cache, err := layercache.New(dir, 10, nil)
+	require.NoError(t, err)
+	fill := (&fileFiller{content: "12345"}).fill
+
+	for _, key := range []string{
+		"first", "second",
+		// touch the first entry, so the second one is the least recently used
+		"first", "third",
+	} {
+		_, release, err := cache.Get(key, fill)
+		require.NoError(t, err)
+		release()
+	}
+
+	assert.FileExists(t, filepath.Join(dir, "first"))
+	assert.NoFileExists(t, filepath.Join(dir, "second"))
+	assert.NoFileExists(t, filepath.Join(dir, "second.complete"))
+	assert.FileExists(t, filepath.Join(dir, "third"))
+	assert.Equal(t, int64(10), cache.Size())
+}
+
+func TestCache_Get_KeepsPinnedEntries(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	cache, err := layercache.New(dir, 10, nil)
+	require.NoError(t, err)
+	fill := (&fileFiller{content: "12345"}).fill
+
+	firstPath, releaseFirst, err := cache.Get("first", fill)
+	require.NoError(t, err)
+	_, releaseSecond, err := cache.Get("second", fill)
+	require.NoError(t, err)
+	releaseSecond()
+	_, releaseThird, err := cache.Get("third", fill)
+	require.NoError(t, err)
+	releaseThird()
+
+	assert.FileExists(t, firstPath)
+	assert.NoFileExists(t, filepath.Join(dir, "second"))
+	assert.FileExists(t, filepath.Join(dir, "third"))
+	assert.Equal(t, int64(10), cache.Size())
+
+	releaseFirst()
+	// releasing twice must not unpin the entry for other callers
+	releaseFirst()
+	_, releaseFourth, err := cache.Get("fourth", fill)
+	require.NoError(t, err)
+	releaseFourth()
+
+	assert.NoFileExists(t, firstPath)
+	assert.FileExists(t, filepath.Join(dir, "third"))
+	assert.FileExists(t, filepath.Join(dir, "fourth"))
+	assert.Equal(t, int64(10), cache.Size())
+}
+
+func TestCache_Get_KeepsEntryLargerThanMaxSize(t *testing.T) {
+	t.Parallel()
+	cache, err := layercache.New(t.TempDir(), 4, nil)
+	require.NoError(t, err)
+
+	path, release, err := cache.Get("layer", (&fileFiller{content: "larger than the cache"}).fill)
+	require.NoError(t, err)
+	release()
+	assert.FileExists(t, path)
+}
+
+func TestCache_Get_Directory(t *testing.T) {
+	t.Parallel()
+	cache, err := layercache.New(t.TempDir(), 1024, nil)
+	require.NoError(t, err)
+	fill := func(path string) error {
+		if err := os.MkdirAll(filepath.Join(path, "base"), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(path, "base", "kustomization.yaml"), []byte("resources: []"), 0o600)
+	}
+
+	path, release, err := cache.Get("kustomize", fill)
+	require.NoError(t, err)
+	defer release()
+	assert.FileExists(t, filepath.Join(path, "base", "kustomization.yaml"))
+	assert.Equal(t, int64(len("resources: []")), cache.Size())
+}
+
+func TestNew_RestoresEntries(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	cache, err := layercache.New(dir, 1024, nil)
+	require.NoError(t, err)
+	filler := &fileFiller{content: "raw manifest"}
+	_, release, err := cache.Get("layer", filler.fill)
+	require.NoError(t, err)
+	release()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".tmp-interrupted"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "without-marker"), []byte("partial"), 0o600))
+
+	restored, err := layercache.New(dir, 1024, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("raw manifest")), restored.Size())
+	assert.NoDirExists(t, filepath.Join(dir, ".tmp-interrupted"))
+	assert.NoFileExists(t, filepath.Join(dir, "without-marker"))
+
+	_, release, err = restored.Get("layer", filler.fill)
+	require.NoError(t, err)
+	release()
+	assert.Equal(t, 1, filler.calls)
+}
+
+func TestNew_InvalidMaxSize(t *testing.T) {
+	t.Parallel()
+	_, err := layercache.New(t.TempDir(), 0, nil)
+	require.ErrorIs(t, err, layercache.ErrInvalidMaxSize)
+}
@@ -1,90 +1,205 @@
 package manifest
 
 import (
+	"archive/tar"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
-	"path"
 	"path/filepath"
 	"regexp"
-	"sync"
+	"strings"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/crane"
 	containerregistryv1 "github.com/google/go-containerregistry/pkg/v1"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/internal/manifest/layercache"
 	"github.com/kyma-project/lifecycle-manager/pkg/ocmextensions"
+	"github.com/kyma-project/lifecycle-manager/pkg/tracing"
 )
 
-//nolint:gochecknoglobals // in-memory cache used mutex
 var (
-	fileMutexMap       = sync.Map{}
-	ErrImageLayerPull  = errors.New("failed to pull layer")
-	ErrMutexConversion = errors.New("failed to convert cached value to mutex")
+	ErrImageLayerPull = errors.New("failed to pull layer")
+	// ErrInvalidArchiveEntry is returned for archive entries that would be written outside the target directory.
+	ErrInvalidArchiveEntry = errors.New("invalid archive entry")
 )
 
+// GetPathFromRawManifest stores the raw manifest of the layer and returns the path of the file.
+// The returned release function must be called once the file is no longer read.
 func GetPathFromRawManifest(ctx context.Context,
 	imageSpec v1beta2.ImageSpec,
 	keyChain authn.Keychain,
-) (string, error) {
-	imageRef := fmt.Sprintf("%s/%s@%s", imageSpec.Repo, imageSpec.Name, imageSpec.Ref)
-
-	// check existing file
-	// if file exists return existing file path
-	installPath := getFsChartPath(imageSpec)
-	manifestPath := path.Join(installPath, v1beta2.RawManifestLayerName+".yaml")
+	layerCache *layercache.Cache,
+) (string, func(), error) {
+	return getPathFromLayer(ctx, imageSpec, keyChain, layerCache, v1beta2.RawManifestLayerName+".yaml", true)
+}
 
-	fileMutex, err := getLockerForPath(installPath)
+// ReadLayer pulls the layer and passes its uncompressed content to read, without storing it in the layer cache.
+// The layer is verified against its digest once read returns.
+func ReadLayer(ctx context.Context,
+	imageSpec v1beta2.ImageSpec,
+	keyChain authn.Keychain,
+	read func(reader io.Reader) error,
+) error {
+	blob, err := pullLayerBlob(ctx, imageSpec, keyChain, true)
 	if err != nil {
-		return "", fmt.Errorf("failed to load locker from cache: %w", err)
-	}
-	fileMutex.Lock()
-	defer fileMutex.Unlock()
-
-	dir, err := os.Open(manifestPath)
-	if err != nil && !errors.Is(err, fs.ErrNotExist) {
-		return "", fmt.Errorf("opening dir for installs caused an error %s: %w", imageRef, err)
+		return err
 	}
-	if dir != nil {
-		return manifestPath, nil
+	defer blob.Close()
+	if err := read(blob); err != nil {
+		return err
 	}
+	return blob.Verify()
+}
 
-	// pull image layer
-	layer, err := pullLayer(ctx, imageRef, keyChain)
-	if err != nil {
-		return "", err
+// GetPathFromHelmChart stores the packaged chart archive of the layer and returns its path.
+// The archive is stored compressed as it is, so it can be loaded by the helm chart loader.
+// The returned release function must be called once the archive is no longer read.
+func GetPathFromHelmChart(ctx context.Context,
+	imageSpec v1beta2.ImageSpec,
+	keyChain authn.Keychain,
+	layerCache *layercache.Cache,
+) (string, func(), error) {
+	return getPathFromLayer(ctx, imageSpec, keyChain, layerCache, v1beta2.HelmChartLayerName+".tgz", false)
+}
+
+// GetPathFromKustomization extracts the tarball of the layer holding a kustomization directory
+// and returns the path of the extracted directory.
+// The returned release function must be called once the directory is no longer read.
+func GetPathFromKustomization(ctx context.Context,
+	imageSpec v1beta2.ImageSpec,
+	keyChain authn.Keychain,
+	layerCache *layercache.Cache,
+) (string, func(), error) {
+	imageRef := layerImageRef(imageSpec)
+	return layerCache.Get(layerCacheKey(imageSpec, v1beta2.KustomizeLayerName), tracedPull(ctx, imageRef,
+		func(ctx context.Context, target string) error {
+			blob, err := pullLayerBlob(ctx, imageSpec, keyChain, true)
+			if err != nil {
+				return err
+			}
+			defer blob.Close()
+
+			if err := os.MkdirAll(target, fs.ModePerm); err != nil {
+				return fmt.Errorf("failed to create extraction directory for layer %s: %w", imageRef, err)
+			}
+			if err := extractTar(blob, target); err != nil {
+				return fmt.Errorf("failed to extract kustomization of layer %s: %w", imageRef, err)
+			}
+			return blob.Verify()
+		}))
+}
+
+// extractTar writes all directories and regular files of the tar stream into the target directory.
+// Entries that would be written outside the target directory are rejected.
+func extractTar(reader io.Reader, target string) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar entry: %w", err)
+		}
+
+		entryPath := filepath.Join(target, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(entryPath, filepath.Clean(target)+string(os.PathSeparator)) {
+			return fmt.Errorf("%w: %s", ErrInvalidArchiveEntry, header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(entryPath, fs.ModePerm); err != nil {
+				return fmt.Errorf("failed to create directory %s: %w", header.Name, err)
+			}
+		case tar.TypeReg:
+			if err := writeTarFile(tarReader, entryPath); err != nil {
+				return fmt.Errorf("failed to write file %s: %w", header.Name, err)
+			}
+		default:
+			// links and special files are not needed for kustomizations
+			continue
+		}
 	}
+}
 
-	// copy uncompressed manifest to install path
-	blobReadCloser, err := layer.Uncompressed()
+func writeTarFile(reader io.Reader, filePath string) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), fs.ModePerm); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	outFile, err := os.Create(filePath)
 	if err != nil {
-		return "", fmt.Errorf("failed fetching blob for layer %s: %w", imageRef, err)
+		return fmt.Errorf("file create failed: %w", err)
 	}
-	defer blobReadCloser.Close()
-
-	// create dir for uncompressed manifest
-	if err := os.MkdirAll(installPath, fs.ModePerm); err != nil {
-		return "", fmt.Errorf(
-			"failure while creating installPath directory for layer %s: %w",
-			imageRef, err,
-		)
+	//nolint:gosec // the layers only contain kustomizations of trusted module templates
+	if _, err := io.Copy(outFile, reader); err != nil {
+		_ = outFile.Close()
+		return fmt.Errorf("file copy storage failed: %w", err)
 	}
-	outFile, err := os.Create(manifestPath)
-	if err != nil {
-		return "", fmt.Errorf("file create failed for layer %s: %w", imageRef, err)
+	if err := outFile.Close(); err != nil {
+		return fmt.Errorf("failed to close io: %w", err)
 	}
-	if _, err := io.Copy(outFile, blobReadCloser); err != nil {
-		return "", fmt.Errorf("file copy storage failed for layer %s: %w", imageRef, err)
+	return nil
+}
+
+func getPathFromLayer(ctx context.Context,
+	imageSpec v1beta2.ImageSpec,
+	keyChain authn.Keychain,
+	layerCache *layercache.Cache,
+	fileName string,
+	uncompressed bool,
+) (string, func(), error) {
+	imageRef := layerImageRef(imageSpec)
+	return layerCache.Get(layerCacheKey(imageSpec, fileName), tracedPull(ctx, imageRef,
+		func(ctx context.Context, target string) error {
+			blob, err := pullLayerBlob(ctx, imageSpec, keyChain, uncompressed)
+			if err != nil {
+				return err
+			}
+			defer blob.Close()
+
+			if err := writeTarFile(blob, target); err != nil {
+				return fmt.Errorf("failed to store layer %s: %w", imageRef, err)
+			}
+			return blob.Verify()
+		}))
+}
+
+// pullLayerBlob opens the blob of the layer, which is verified against the digest the layer is referenced by.
+func pullLayerBlob(ctx context.Context,
+	imageSpec v1beta2.ImageSpec,
+	keyChain authn.Keychain,
+	uncompressed bool,
+) (*layerBlob, error) {
+	imageRef := layerImageRef(imageSpec)
+	layer, err := pullLayer(ctx, imageRef, keyChain)
+	if err != nil {
+		return nil, err
 	}
-	err = io.Closer(outFile).Close()
+	blob, err := openLayerBlob(layer, imageSpec.Ref, uncompressed)
 	if err != nil {
-		return manifestPath, fmt.Errorf("failed to close io: %w", err)
+		return nil, fmt.Errorf("failed fetching blob for layer %s: %w", imageRef, err)
+	}
+	return blob, nil
+}
+
+// tracedPull records the pull of a layer as a span. The pull is only run if the layer is not in the layer cache.
+func tracedPull(ctx context.Context, imageRef string,
+	pull func(ctx context.Context, target string) error,
+) func(target string) error {
+	return func(target string) error {
+		ctx, span := tracing.Start(ctx, "OCI.PullLayer",
+			trace.WithAttributes(tracing.ImageReferenceKey.String(imageRef)))
+		err := pull(ctx, target)
+		tracing.End(span, err)
+		return err
 	}
-	return manifestPath, nil
 }
 
 func pullLayer(ctx context.Context, imageRef string, keyChain authn.Keychain) (containerregistryv1.Layer, error) {
@@ -95,7 +210,8 @@ func pullLayer(ctx context.Context, imageRef string, keyChain authn.Keychain) (c
 	}
 
 	if isInsecureLayer {
-		imgLayer, err := crane.PullLayer(noSchemeImageRef, crane.Insecure, crane.WithAuthFromKeychain(keyChain))
+		imgLayer, err := crane.PullLayer(noSchemeImageRef, crane.Insecure, crane.WithAuthFromKeychain(keyChain),
+			crane.WithContext(ctx))
 		if err != nil {
 			return nil, fmt.Errorf("%s due to: %w", ErrImageLayerPull.Error(), err)
 		}
@@ -108,20 +224,11 @@ func pullLayer(ctx context.Context, imageRef string, keyChain authn.Keychain) (c
 	return imgLayer, nil
 }
 
-func getFsChartPath(imageSpec v1beta2.ImageSpec) string {
-	return filepath.Join(os.TempDir(), fmt.Sprintf("%s-%s", imageSpec.Name, imageSpec.Ref))
+func layerImageRef(imageSpec v1beta2.ImageSpec) string {
+	return fmt.Sprintf("%s/%s@%s", imageSpec.Repo, imageSpec.Name, imageSpec.Ref)
 }
 
-// getLockerForPath always returns the same sync.Locker instance for given path argument.
-func getLockerForPath(path string) (sync.Locker, error) {
-	val, ok := fileMutexMap.Load(path)
-	if !ok {
-		val, _ = fileMutexMap.LoadOrStore(path, &sync.Mutex{})
-	}
-
-	mutex, ok := val.(*sync.Mutex)
-	if !ok {
-		return nil, ErrMutexConversion
-	}
-	return mutex, nil
+// layerCacheKey identifies a layer in the layer cache by its name, its digest and the file it is stored as.
+func layerCacheKey(imageSpec v1beta2.ImageSpec, fileName string) string {
+	return fmt.Sprintf("%s-%s-%s", imageSpec.Name, imageSpec.Ref, fileName)
 }
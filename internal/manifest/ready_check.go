@@ -8,16 +8,19 @@ import (
 	"strings"
 	"time"
 
-	apiappsv1 "k8s.io/api/apps/v1"
 	apicorev1 "k8s.io/api/core/v1"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	machineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/resource"
-	"k8s.io/kubectl/pkg/util/deployment"
+	kstatus "sigs.k8s.io/cli-utils/pkg/kstatus/status"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	"github.com/kyma-project/lifecycle-manager/api/shared"
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
 	declarativev2 "github.com/kyma-project/lifecycle-manager/internal/declarative/v2"
+	"github.com/kyma-project/lifecycle-manager/pkg/statecheck"
 	"github.com/kyma-project/lifecycle-manager/pkg/util"
 )
 
@@ -26,6 +29,7 @@ const (
 	customResourceStatePath          = "status.state"
 	ModuleCRWithCustomCheckWarning   = "module CR state not found or given customStateCheck.jsonPath is not exists"
 	ModuleCRWithNoCustomCheckWarning = "module CR state not found"
+	maxNotReadyResourcesInInfo       = 5
 )
 
 // NewCustomResourceReadyCheck creates a readiness check that verifies that the Resource in the Manifest
@@ -36,9 +40,16 @@ func NewCustomResourceReadyCheck() *CustomResourceReadyCheck {
 
 type CustomResourceReadyCheck struct{}
 
+// stateCheckExpressions caches the compiled expressions of the customStateChecks of all Manifests.
+//
+//nolint:gochecknoglobals // compiling an expression is expensive and the compiled programs are shared by all Manifests
+var stateCheckExpressions = statecheck.NewCompiler()
+
 var (
-	ErrNotSupportedState    = errors.New("module CR state not support")
-	ErrRequiredStateMissing = errors.New("required Ready and Error state mapping are missing")
+	ErrNotSupportedState           = errors.New("module CR state not support")
+	ErrRequiredStateMissing        = errors.New("required Ready and Error state mapping are missing")
+	ErrStateCheckExpressionNotBool = errors.New("customStateCheck expression did not evaluate to a bool")
+	ErrStateCheckExpressionFailed  = errors.New("customStateCheck expression could not be evaluated")
 )
 
 func (c *CustomResourceReadyCheck) Run(ctx context.Context,
@@ -46,10 +57,22 @@ func (c *CustomResourceReadyCheck) Run(ctx context.Context,
 	obj declarativev2.Object,
 	resources []*resource.Info,
 ) (declarativev2.StateInfo, error) {
-	if !isDeploymentReady(clnt, resources) {
+	unhealthy, failed, err := notReadyResources(clnt, resources)
+	if err != nil {
+		return declarativev2.StateInfo{State: shared.StateError}, err
+	}
+	if failed {
 		return declarativev2.StateInfo{
-			State: shared.StateProcessing,
-			Info:  "module operator deployment is not ready",
+			State:              shared.StateError,
+			Info:               "resources failed: " + describeResources(unhealthy),
+			UnhealthyResources: unhealthy,
+		}, nil
+	}
+	if len(unhealthy) > 0 {
+		return declarativev2.StateInfo{
+			State:              shared.StateProcessing,
+			Info:               "resources are not ready: " + describeResources(unhealthy),
+			UnhealthyResources: unhealthy,
 		}, nil
 	}
 	manifest, ok := obj.(*v1beta2.Manifest)
@@ -61,7 +84,7 @@ func (c *CustomResourceReadyCheck) Run(ctx context.Context,
 	}
 	moduleCR := manifest.Spec.Resource.DeepCopy()
 
-	err := clnt.Get(ctx, client.ObjectKeyFromObject(moduleCR), moduleCR)
+	err = clnt.Get(ctx, client.ObjectKeyFromObject(moduleCR), moduleCR)
 	if err != nil {
 		if util.IsNotFound(err) && !manifest.DeletionTimestamp.IsZero() {
 			return declarativev2.StateInfo{State: shared.StateDeleting}, nil
@@ -115,35 +138,88 @@ func mappingState(stateChecks []*v1beta2.CustomStateCheck,
 	customStateFound bool,
 ) (shared.State, bool, error) {
 	// make sure ready and error state exists, for other missing customized state, can be ignored.
-	if requiredStateMissing(stateChecks) {
+	if len(v1beta2.MissingRequiredStates(stateChecks)) > 0 {
 		return "", false, ErrRequiredStateMissing
 	}
 	stateResult := map[shared.State]bool{}
 	foundStateInCR := false
 	for _, stateCheck := range stateChecks {
-		stateFromCR, stateExists, err := unstructured.NestedString(moduleCR.Object,
-			strings.Split(stateCheck.JSONPath, ".")...)
+		matched, stateExists, err := evaluateStateCheck(stateCheck, moduleCR, customStateFound)
 		if err != nil {
-			return "", false, fmt.Errorf("could not get state from module CR %s at path %s "+
-				"to determine readiness: %w", moduleCR.GetName(), stateCheck.JSONPath, err)
+			return "", false, err
 		}
 		if !stateExists {
 			continue
 		}
-		if !customStateFound && !shared.State(stateFromCR).IsSupportedState() {
-			return "", false, ErrNotSupportedState
-		}
 		foundStateInCR = true
 		_, found := stateResult[stateCheck.MappedState]
 		if found {
-			stateResult[stateCheck.MappedState] = stateResult[stateCheck.MappedState] && stateFromCR == stateCheck.Value
+			stateResult[stateCheck.MappedState] = stateResult[stateCheck.MappedState] && matched
 		} else {
-			stateResult[stateCheck.MappedState] = stateFromCR == stateCheck.Value
+			stateResult[stateCheck.MappedState] = matched
 		}
 	}
 	return calculateFinalState(stateResult), foundStateInCR, nil
 }
 
+// evaluateStateCheck reports whether the module CR matches the state check, and whether the state check could be
+// evaluated at all, because the JSONPath or the fields used by the Expression exist in the module CR.
+func evaluateStateCheck(stateCheck *v1beta2.CustomStateCheck,
+	moduleCR *unstructured.Unstructured,
+	customStateFound bool,
+) (bool, bool, error) {
+	if stateCheck.Expression != "" {
+		return evaluateStateCheckExpression(stateCheck, moduleCR)
+	}
+	stateFromCR, stateExists, err := unstructured.NestedString(moduleCR.Object,
+		strings.Split(stateCheck.JSONPath, ".")...)
+	if err != nil {
+		return false, false, fmt.Errorf("could not get state from module CR %s at path %s "+
+			"to determine readiness: %w", moduleCR.GetName(), stateCheck.JSONPath, err)
+	}
+	if !stateExists {
+		return false, false, nil
+	}
+	if !customStateFound && !shared.State(stateFromCR).IsSupportedState() {
+		return false, false, ErrNotSupportedState
+	}
+	return stateFromCR == stateCheck.Value, true, nil
+}
+
+// evaluateStateCheckExpression evaluates the CEL Expression of the state check against the module CR.
+// An expression that accesses a field the module CR does not have yet is treated like a JSONPath that does not exist.
+// All other evaluation errors, such as exceeding the cost limit or mismatching types, are returned.
+func evaluateStateCheckExpression(stateCheck *v1beta2.CustomStateCheck,
+	moduleCR *unstructured.Unstructured,
+) (bool, bool, error) {
+	program, err := stateCheckExpressions.Compile(stateCheck.Expression)
+	if err != nil {
+		return false, false, fmt.Errorf("could not evaluate state of module CR %s "+
+			"to determine readiness: %w", moduleCR.GetName(), err)
+	}
+	result, _, err := program.Eval(map[string]any{v1beta2.StateCheckObjectVariable: moduleCR.Object})
+	if err != nil {
+		if isMissingField(err) {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("%w: expression %q of module CR %s: %w",
+			ErrStateCheckExpressionFailed, stateCheck.Expression, moduleCR.GetName(), err)
+	}
+	matched, ok := result.Value().(bool)
+	if !ok {
+		return false, false, fmt.Errorf("%w: expression %q of module CR %s evaluated to %v",
+			ErrStateCheckExpressionNotBool, stateCheck.Expression, moduleCR.GetName(), result.Value())
+	}
+	return matched, true, nil
+}
+
+// isMissingField reports whether the evaluation of an expression failed because it accesses a key or attribute
+// that does not exist. CEL does not expose these errors as types, so they are identified by their message.
+func isMissingField(err error) bool {
+	message := err.Error()
+	return strings.HasPrefix(message, "no such key") || strings.HasPrefix(message, "no such attribute")
+}
+
 func calculateFinalState(stateResult map[shared.State]bool) shared.State {
 	if stateResult[shared.StateError] {
 		return shared.StateError
@@ -162,20 +238,6 @@ func calculateFinalState(stateResult map[shared.State]bool) shared.State {
 	return shared.StateProcessing
 }
 
-func requiredStateMissing(stateChecks []*v1beta2.CustomStateCheck) bool {
-	readyMissing := true
-	errorMissing := true
-	for _, stateCheck := range stateChecks {
-		if stateCheck.MappedState == shared.StateReady {
-			readyMissing = false
-		}
-		if stateCheck.MappedState == shared.StateError {
-			errorMissing = false
-		}
-	}
-	return readyMissing || errorMissing
-}
-
 func parseStateChecks(manifest *v1beta2.Manifest) ([]*v1beta2.CustomStateCheck, bool, error) {
 	customStateCheckAnnotation, found := manifest.Annotations[shared.CustomStateCheckAnnotation]
 	if !found {
@@ -209,26 +271,85 @@ func parseStateChecks(manifest *v1beta2.Manifest) ([]*v1beta2.CustomStateCheck,
 	return stateCheck, true, nil
 }
 
-func isDeploymentReady(clt declarativev2.Client, resources []*resource.Info) bool {
-	deploy := &apiappsv1.Deployment{}
-	found := false
+// notReadyResources computes the readiness of every rendered resource with the kstatus rules, which cover
+// workloads such as Deployments, StatefulSets, DaemonSets and Jobs, as well as PVCs, CRDs and resources with
+// standard conditions. It returns the resources that are not ready and reports whether one of them failed,
+// in which case only the failed resources are returned.
+// The resources are expected to contain the state of the cluster, as returned by the server-side apply.
+func notReadyResources(clnt declarativev2.Client, resources []*resource.Info) (
+	[]shared.UnhealthyResource, bool, error,
+) {
+	var failed, inProgress []shared.UnhealthyResource
 	for _, res := range resources {
-		err := clt.Scheme().Convert(res.Object, deploy, nil)
-		if err == nil {
-			found = true
+		obj, err := toUnstructured(clnt, res)
+		if err != nil {
+			return nil, false, err
+		}
+		result, err := kstatus.Compute(obj)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to compute readiness of %s: %w", res.ObjectName(), err)
+		}
+		switch result.Status {
+		case kstatus.FailedStatus:
+			failed = append(failed, unhealthyResource(obj, result))
+		case kstatus.InProgressStatus, kstatus.TerminatingStatus:
+			inProgress = append(inProgress, unhealthyResource(obj, result))
+		case kstatus.CurrentStatus, kstatus.UnknownStatus, kstatus.NotFoundStatus:
+		}
+	}
+	if len(failed) > 0 {
+		return failed, true, nil
+	}
+	return inProgress, false, nil
+}
+
+// unhealthyResource describes the resource with the reason of the first true kstatus condition,
+// which is the Reconciling or Stalled condition, or with the computed status if there is none.
+func unhealthyResource(obj *unstructured.Unstructured, result *kstatus.Result) shared.UnhealthyResource {
+	reason := string(result.Status)
+	for _, condition := range result.Conditions {
+		if condition.Status == apicorev1.ConditionTrue && condition.Reason != "" {
+			reason = condition.Reason
 			break
 		}
 	}
-	// not every module operator use Deployment by default, e.g: StatefulSet also a valid approach
-	if !found {
-		return true
+	message := result.Message
+	if len(message) > shared.MaxUnhealthyResourceMessageLength {
+		message = strings.ToValidUTF8(message[:shared.MaxUnhealthyResourceMessageLength-3], "") + "..."
 	}
-	availableCond := deployment.GetDeploymentCondition(deploy.Status, apiappsv1.DeploymentAvailable)
-	if availableCond != nil && availableCond.Status == apicorev1.ConditionTrue {
-		return true
+	gvk := obj.GroupVersionKind()
+	return shared.UnhealthyResource{
+		Resource: shared.Resource{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			GroupVersionKind: apimetav1.GroupVersionKind{
+				Group:   gvk.Group,
+				Version: gvk.Version,
+				Kind:    gvk.Kind,
+			},
+		},
+		Reason:  reason,
+		Message: message,
 	}
-	if deploy.Spec.Replicas != nil && *deploy.Spec.Replicas == deploy.Status.ReadyReplicas {
-		return true
+}
+
+func describeResources(resources []shared.UnhealthyResource) string {
+	return util.DescribeFirst(resources, maxNotReadyResourcesInInfo, len(resources))
+}
+
+func toUnstructured(clnt declarativev2.Client, res *resource.Info) (*unstructured.Unstructured, error) {
+	if obj, ok := res.Object.(*unstructured.Unstructured); ok {
+		return obj, nil
+	}
+	content, err := machineryruntime.DefaultUnstructuredConverter.ToUnstructured(res.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s: %w", res.ObjectName(), err)
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	gvk, err := apiutil.GVKForObject(res.Object, clnt.Scheme())
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine kind of %s: %w", res.ObjectName(), err)
 	}
-	return false
+	obj.SetGroupVersionKind(gvk)
+	return obj, nil
 }
@@ -1,13 +1,18 @@
 package manifest_test
 
 import (
+	"context"
 	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/kyma-project/lifecycle-manager/api/shared"
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
@@ -443,3 +448,223 @@ func TestHandleStateWithDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleState_CustomStateCheckExpression(t *testing.T) {
+	t.Parallel()
+	readyAndObserved := []*v1beta2.CustomStateCheck{
+		{
+			Expression: `object.status.conditions.exists(c, c.type == "Ready" && c.status == "True") && ` +
+				`object.status.observedGeneration == object.metadata.generation`,
+			MappedState: shared.StateReady,
+		},
+		{
+			Expression:  `object.status.conditions.exists(c, c.type == "Ready" && c.status == "False")`,
+			MappedState: shared.StateError,
+		},
+	}
+	tests := []struct {
+		name        string
+		customState []*v1beta2.CustomStateCheck
+		status      map[string]any
+		want        declarativev2.StateInfo
+		wantErr     bool
+	}{
+		{
+			"ready condition of observed generation, expected mapped to StateReady",
+			readyAndObserved,
+			map[string]any{
+				"observedGeneration": int64(2),
+				"conditions":         []any{map[string]any{"type": "Ready", "status": "True"}},
+			},
+			declarativev2.StateInfo{State: shared.StateReady},
+			false,
+		},
+		{
+			"ready condition of outdated generation, expected mapped to StateProcessing",
+			readyAndObserved,
+			map[string]any{
+				"observedGeneration": int64(1),
+				"conditions":         []any{map[string]any{"type": "Ready", "status": "True"}},
+			},
+			declarativev2.StateInfo{State: shared.StateProcessing},
+			false,
+		},
+		{
+			"failed ready condition, expected mapped to StateError",
+			readyAndObserved,
+			map[string]any{
+				"observedGeneration": int64(2),
+				"conditions":         []any{map[string]any{"type": "Ready", "status": "False"}},
+			},
+			declarativev2.StateInfo{State: shared.StateError},
+			false,
+		},
+		{
+			"no conditions yet, expected mapped to StateProcessing",
+			readyAndObserved,
+			map[string]any{},
+			declarativev2.StateInfo{State: shared.StateProcessing, Info: manifest.ModuleCRWithCustomCheckWarning},
+			false,
+		},
+		{
+			"expression not evaluating to bool, expected error",
+			[]*v1beta2.CustomStateCheck{
+				{Expression: `object.status.conditions`, MappedState: shared.StateReady},
+				{JSONPath: "status.state", Value: string(shared.StateError), MappedState: shared.StateError},
+			},
+			map[string]any{"conditions": []any{}},
+			declarativev2.StateInfo{State: shared.StateError},
+			true,
+		},
+		{
+			"expression failing with a type error, expected error",
+			[]*v1beta2.CustomStateCheck{
+				{Expression: `object.status.observedGeneration > "1"`, MappedState: shared.StateReady},
+				{JSONPath: "status.state", Value: string(shared.StateError), MappedState: shared.StateError},
+			},
+			map[string]any{"observedGeneration": int64(2)},
+			declarativev2.StateInfo{State: shared.StateError},
+			true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			manifestCR := testutils.NewTestManifest("test")
+			marshal, err := json.Marshal(testCase.customState)
+			if err != nil {
+				t.Errorf("HandleState() error = %v", err)
+				return
+			}
+			manifestCR.Annotations[shared.CustomStateCheckAnnotation] = string(marshal)
+			manifestCR.CreationTimestamp = apimetav1.Now()
+			moduleCR := builder.NewModuleCRBuilder().WithName("test").WithNamespace(apimetav1.NamespaceDefault).
+				WithGroupVersionKind(v1beta2.GroupVersion.Group, "v1", "TestCR").Build()
+			moduleCR.SetGeneration(2)
+			moduleCR.Object["status"] = testCase.status
+
+			got, err := manifest.HandleState(manifestCR, moduleCR)
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("HandleState() got = %v, want %v", got, testCase.want)
+			}
+			if (err != nil) != testCase.wantErr {
+				t.Errorf("HandleState() error = %v, wantErr %v", err, testCase.wantErr)
+			}
+		})
+	}
+}
+
+func workload(kind, name string, generation int64, status map[string]any) *resource.Info {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       kind,
+		"metadata": map[string]any{
+			"name":       name,
+			"namespace":  "kyma-system",
+			"generation": generation,
+		},
+		"spec":   map[string]any{"replicas": int64(3)},
+		"status": status,
+	}}
+	return &resource.Info{Name: name, Namespace: "kyma-system", Object: obj}
+}
+
+func unhealthyResource(group, kind, name, reason, message string) shared.UnhealthyResource {
+	return shared.UnhealthyResource{
+		Resource: shared.Resource{
+			Name:             name,
+			Namespace:        "kyma-system",
+			GroupVersionKind: apimetav1.GroupVersionKind{Group: group, Version: "v1", Kind: kind},
+		},
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+func TestCustomResourceReadyCheck_Run(t *testing.T) {
+	t.Parallel()
+	readyStatefulSet := workload("StatefulSet", "ready", 1, map[string]any{
+		"observedGeneration": int64(1),
+		"replicas":           int64(3),
+		"readyReplicas":      int64(3),
+		"currentReplicas":    int64(3),
+		"updatedReplicas":    int64(3),
+		"currentRevision":    "rev-1",
+		"updateRevision":     "rev-1",
+	})
+	tests := []struct {
+		name      string
+		resources []*resource.Info
+		want      declarativev2.StateInfo
+	}{
+		{
+			"all resources are ready, expected StateReady",
+			[]*resource.Info{readyStatefulSet},
+			declarativev2.StateInfo{State: shared.StateReady},
+		},
+		{
+			"statefulset with missing replicas, expected StateProcessing",
+			[]*resource.Info{readyStatefulSet, workload("StatefulSet", "scaling", 1, map[string]any{
+				"observedGeneration": int64(1),
+				"replicas":           int64(1),
+				"readyReplicas":      int64(1),
+			})},
+			declarativev2.StateInfo{
+				State: shared.StateProcessing,
+				Info:  "resources are not ready: StatefulSet kyma-system/scaling (Replicas: 1/3)",
+				UnhealthyResources: []shared.UnhealthyResource{
+					unhealthyResource("apps", "StatefulSet", "scaling", "LessReplicas", "Replicas: 1/3"),
+				},
+			},
+		},
+		{
+			"daemonset of outdated generation, expected StateProcessing",
+			[]*resource.Info{workload("DaemonSet", "outdated", 2, map[string]any{"observedGeneration": int64(1)})},
+			declarativev2.StateInfo{
+				State: shared.StateProcessing,
+				Info: "resources are not ready: DaemonSet kyma-system/outdated " +
+					"(DaemonSet generation is 2, but latest observed generation is 1)",
+				UnhealthyResources: []shared.UnhealthyResource{
+					unhealthyResource("apps", "DaemonSet", "outdated", "LatestGenerationNotObserved",
+						"DaemonSet generation is 2, but latest observed generation is 1"),
+				},
+			},
+		},
+		{
+			"failed job, expected StateError",
+			[]*resource.Info{
+				workload("DaemonSet", "outdated", 2, map[string]any{"observedGeneration": int64(1)}),
+				{Name: "job", Namespace: "kyma-system", Object: &unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "batch/v1",
+					"kind":       "Job",
+					"metadata":   map[string]any{"name": "job", "namespace": "kyma-system"},
+					"status": map[string]any{"conditions": []any{
+						map[string]any{"type": "Failed", "status": "True", "message": "BackoffLimitExceeded"},
+					}},
+				}}},
+			},
+			declarativev2.StateInfo{
+				State: shared.StateError,
+				Info:  "resources failed: Job kyma-system/job (Job Failed. failed: 0/1)",
+				UnhealthyResources: []shared.UnhealthyResource{
+					unhealthyResource("batch", "Job", "job", "JobFailed", "Job Failed. failed: 0/1"),
+				},
+			},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			manifestCR := testutils.NewTestManifest("test")
+			skr := skrClient{Client: fake.NewClientBuilder().Build()}
+
+			got, err := manifest.NewCustomResourceReadyCheck().Run(context.Background(), skr, manifestCR,
+				testCase.resources)
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
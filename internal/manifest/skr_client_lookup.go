@@ -5,11 +5,15 @@ import (
 	"fmt"
 
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kyma-project/lifecycle-manager/api/shared"
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
 	"github.com/kyma-project/lifecycle-manager/internal"
 	declarativev2 "github.com/kyma-project/lifecycle-manager/internal/declarative/v2"
+	"github.com/kyma-project/lifecycle-manager/pkg/remote"
+	"github.com/kyma-project/lifecycle-manager/pkg/tracing"
+	"github.com/kyma-project/lifecycle-manager/pkg/util"
 )
 
 type RESTConfigGetter func() (*rest.Config, error)
@@ -17,6 +21,8 @@ type RESTConfigGetter func() (*rest.Config, error)
 type RemoteClusterLookup struct {
 	KCP          *declarativev2.ClusterInfo
 	ConfigGetter RESTConfigGetter
+	// ConfigProviders resolve the rest config for Kymas with a sync strategy other than the default local-secret.
+	ConfigProviders remote.ConfigProviders
 }
 
 func (r *RemoteClusterLookup) ConfigResolver(
@@ -37,21 +43,12 @@ func (r *RemoteClusterLookup) ConfigResolver(
 		return nil, fmt.Errorf("failed to get kyma owner label: %w", err)
 	}
 
-	// RESTConfig can either be retrieved by a secret with name contained in labels.KymaName Manifest CR label,
+	// RESTConfig can either be retrieved by the ConfigProvider of the sync strategy of the Kyma,
 	// or it can be retrieved as a function return value, passed during controller startup.
-	var restConfigGetter RESTConfigGetter
-	if r.ConfigGetter != nil {
-		restConfigGetter = r.ConfigGetter
-	} else {
-		restConfigGetter = func() (*rest.Config, error) {
-			// evaluate remote rest config from secret
-			config, err := (&ClusterClient{DefaultClient: r.KCP.Client}).GetRESTConfig(
-				ctx, kymaOwnerLabel, shared.KymaName, manifest.GetNamespace(),
-			)
-			if err != nil {
-				return nil, fmt.Errorf("could not resolve remote cluster rest config: %w", err)
-			}
-			return config, nil
+	restConfigGetter := r.ConfigGetter
+	if restConfigGetter == nil {
+		if restConfigGetter, err = r.providerConfigGetter(ctx, kymaOwnerLabel, manifest.GetNamespace()); err != nil {
+			return nil, err
 		}
 	}
 
@@ -63,5 +60,45 @@ func (r *RemoteClusterLookup) ConfigResolver(
 	config.QPS = r.KCP.Config.QPS
 	config.Burst = r.KCP.Config.Burst
 
-	return &declarativev2.ClusterInfo{Config: config}, nil
+	return &declarativev2.ClusterInfo{Config: tracing.InstrumentConfig(config)}, nil
+}
+
+// providerConfigGetter returns a RESTConfigGetter using the ConfigProvider of the sync strategy of the owning Kyma.
+// For the local-secret strategy, for unsupported strategies, or if the Kyma is not found anymore, e.g. during
+// deletion, the rest config is read from the secret labeled with the name of the Kyma.
+func (r *RemoteClusterLookup) providerConfigGetter(ctx context.Context,
+	kymaName, namespace string,
+) (RESTConfigGetter, error) {
+	secretConfigGetter := func() (*rest.Config, error) {
+		config, err := (&ClusterClient{DefaultClient: r.KCP.Client}).GetRESTConfig(
+			ctx, kymaName, shared.KymaName, namespace,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve remote cluster rest config: %w", err)
+		}
+		return config, nil
+	}
+	if r.ConfigProviders == nil {
+		return secretConfigGetter, nil
+	}
+	kyma := &v1beta2.Kyma{}
+	key := client.ObjectKey{Name: kymaName, Namespace: namespace}
+	if err := r.KCP.Client.Get(ctx, key, kyma); err != nil {
+		if util.IsNotFound(err) {
+			return secretConfigGetter, nil
+		}
+		return nil, fmt.Errorf("failed to get kyma %s to resolve its sync strategy: %w", key, err)
+	}
+	strategy := r.ConfigProviders.SupportedSyncStrategy(ctx, remote.SyncStrategyFor(kyma))
+	if strategy == v1beta2.SyncStrategyLocalSecret {
+		return secretConfigGetter, nil
+	}
+	provider := r.ConfigProviders[strategy]
+	return func() (*rest.Config, error) {
+		config, err := provider.RestConfig(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve remote cluster rest config: %w", err)
+		}
+		return config, nil
+	}, nil
 }
@@ -4,37 +4,63 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"reflect"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/jellydator/ttlcache/v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
 	"github.com/kyma-project/lifecycle-manager/api/shared"
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
 	declarativev2 "github.com/kyma-project/lifecycle-manager/internal/declarative/v2"
+	"github.com/kyma-project/lifecycle-manager/internal/manifest/layercache"
 	"github.com/kyma-project/lifecycle-manager/pkg/ocmextensions"
+	"github.com/kyma-project/lifecycle-manager/pkg/signature"
 )
 
 // RawManifestInfo defines raw manifest information.
 type RawManifestInfo struct {
 	Path   string
 	OCIRef string
+	// Release is called once the file at Path is no longer read.
+	Release func()
 }
 
 type SpecResolver struct {
-	KCP          *declarativev2.ClusterInfo
-	ChartCache   string
-	cachedCharts map[string]string
+	KCP *declarativev2.ClusterInfo
+	// LayerCache stores the pulled layers on disk, so they are only pulled once per digest.
+	LayerCache *layercache.Cache
+	// EnableCosignVerification enables the verification of cosign signatures for layers of
+	// Manifests labeled with a signature.
+	EnableCosignVerification bool
+	verifiedLayers           *ttlcache.Cache[string, struct{}]
 }
 
-func NewSpecResolver(kcp *declarativev2.ClusterInfo) *SpecResolver {
+const (
+	// verifiedLayersTTL limits how long a successful verification is remembered, so that a rotated
+	// public key is eventually applied to layers that were verified before.
+	verifiedLayersTTL = 12 * time.Hour
+	// maxVerifiedLayers limits the number of remembered verifications, the least recently used are evicted first.
+	maxVerifiedLayers = 1000
+)
+
+func NewSpecResolver(kcp *declarativev2.ClusterInfo,
+	layerCache *layercache.Cache,
+	enableCosignVerification bool,
+) *SpecResolver {
 	return &SpecResolver{
-		KCP:          kcp,
-		ChartCache:   os.TempDir(),
-		cachedCharts: make(map[string]string),
+		KCP:                      kcp,
+		LayerCache:               layerCache,
+		EnableCosignVerification: enableCosignVerification,
+		verifiedLayers: ttlcache.New[string, struct{}](
+			ttlcache.WithTTL[string, struct{}](verifiedLayersTTL),
+			ttlcache.WithCapacity[string, struct{}](maxVerifiedLayers),
+			ttlcache.WithDisableTouchOnHit[string, struct{}](),
+		),
 	}
 }
 
@@ -62,26 +88,88 @@ func (m *SpecResolver) Spec(ctx context.Context, obj declarativev2.Object,
 	if err := yaml.Unmarshal(manifest.Spec.Install.Source.Raw, &imageSpec); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal data: %w", err)
 	}
+	if err := m.verifyLayerSignature(ctx, manifest, imageSpec, targetClient); err != nil {
+		return nil, err
+	}
 
-	var mode declarativev2.RenderMode
 	switch imageSpec.Type {
 	case v1beta2.OciRefType:
-		mode = declarativev2.RenderModeRaw
+		rawManifestInfo, err := m.getRawManifestForInstall(ctx, imageSpec, targetClient)
+		if err != nil {
+			return nil, err
+		}
+		return &declarativev2.Spec{
+			ManifestName: manifest.Spec.Install.Name,
+			Path:         rawManifestInfo.Path,
+			OCIRef:       rawManifestInfo.OCIRef,
+			Mode:         declarativev2.RenderModeRaw,
+			Release:      rawManifestInfo.Release,
+		}, nil
+	case v1beta2.HelmChartType:
+		return m.getHelmChartSpec(ctx, manifest, imageSpec, targetClient)
+	case v1beta2.KustomizeType:
+		return m.getKustomizationSpec(ctx, manifest, imageSpec, targetClient)
 	default:
 		return nil, fmt.Errorf("could not determine render mode for %s: %w",
 			client.ObjectKeyFromObject(manifest), ErrRenderModeInvalid)
 	}
+}
 
-	rawManifestInfo, err := m.getRawManifestForInstall(ctx, imageSpec, targetClient)
+// getHelmChartSpec pulls the packaged chart and uses the spec of the module CR as values for the rendering.
+// The module CR is taken from the Manifest, which in turn is initialized from the data of the ModuleTemplate.
+func (m *SpecResolver) getHelmChartSpec(ctx context.Context,
+	manifest *v1beta2.Manifest,
+	imageSpec v1beta2.ImageSpec,
+	targetClient client.Client,
+) (*declarativev2.Spec, error) {
+	keyChain, err := m.lookupKeyChain(ctx, imageSpec, targetClient)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to fetch keyChain: %w", err)
+	}
+	chartPath, release, err := GetPathFromHelmChart(ctx, imageSpec, keyChain, m.LayerCache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract helm chart from layer digest: %w", err)
 	}
 
+	spec := &declarativev2.Spec{
+		ManifestName: manifest.Spec.Install.Name,
+		Path:         chartPath,
+		OCIRef:       imageSpec.Ref,
+		Mode:         declarativev2.RenderModeHelm,
+		Values:       map[string]any{},
+		Release:      release,
+	}
+	if manifest.Spec.Resource != nil {
+		spec.Namespace = manifest.Spec.Resource.GetNamespace()
+		if values, found, err := unstructured.NestedMap(manifest.Spec.Resource.Object, "spec"); err != nil {
+			release()
+			return nil, fmt.Errorf("failed to read helm values from module CR spec: %w", err)
+		} else if found {
+			spec.Values = values
+		}
+	}
+	return spec, nil
+}
+
+func (m *SpecResolver) getKustomizationSpec(ctx context.Context,
+	manifest *v1beta2.Manifest,
+	imageSpec v1beta2.ImageSpec,
+	targetClient client.Client,
+) (*declarativev2.Spec, error) {
+	keyChain, err := m.lookupKeyChain(ctx, imageSpec, targetClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch keyChain: %w", err)
+	}
+	kustomizationPath, release, err := GetPathFromKustomization(ctx, imageSpec, keyChain, m.LayerCache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract kustomization from layer digest: %w", err)
+	}
 	return &declarativev2.Spec{
 		ManifestName: manifest.Spec.Install.Name,
-		Path:         rawManifestInfo.Path,
-		OCIRef:       rawManifestInfo.OCIRef,
-		Mode:         mode,
+		Path:         kustomizationPath,
+		OCIRef:       imageSpec.Ref,
+		Mode:         declarativev2.RenderModeKustomize,
+		Release:      release,
 	}, nil
 }
 
@@ -95,16 +183,50 @@ func (m *SpecResolver) getRawManifestForInstall(ctx context.Context,
 	}
 
 	// extract raw manifest from layer digest
-	rawManifestPath, err := GetPathFromRawManifest(ctx, imageSpec, keyChain)
+	rawManifestPath, release, err := GetPathFromRawManifest(ctx, imageSpec, keyChain, m.LayerCache)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract raw manifest from layer digest: %w", err)
 	}
 	return &RawManifestInfo{
-		Path:   rawManifestPath,
-		OCIRef: imageSpec.Ref,
+		Path:    rawManifestPath,
+		OCIRef:  imageSpec.Ref,
+		Release: release,
 	}, nil
 }
 
+// verifyLayerSignature verifies the cosign signature of the OCI artifact the install layer belongs to.
+// The artifact is the component version the Manifest was created from, successful verifications are
+// remembered for verifiedLayersTTL, so the registry is only contacted once per layer in that time.
+func (m *SpecResolver) verifyLayerSignature(ctx context.Context,
+	manifest *v1beta2.Manifest,
+	imageSpec v1beta2.ImageSpec,
+	targetClient client.Client,
+) error {
+	signatureName := manifest.GetLabels()[shared.Signature]
+	if !m.EnableCosignVerification || signatureName == "" {
+		return nil
+	}
+	artifactRef := fmt.Sprintf("%s/%s:%s", imageSpec.Repo, imageSpec.Name, manifest.Spec.Version)
+	verificationKey := fmt.Sprintf("%s/%s@%s", signatureName, artifactRef, imageSpec.Ref)
+	if m.verifiedLayers.Get(verificationKey) != nil {
+		return nil
+	}
+
+	keys, err := signature.PublicKeys(ctx, m.KCP.Client, manifest.GetNamespace(), signatureName)
+	if err != nil {
+		return fmt.Errorf("%w: %w", signature.ErrVerificationFailed, err)
+	}
+	keyChain, err := m.lookupKeyChain(ctx, imageSpec, targetClient)
+	if err != nil {
+		return fmt.Errorf("failed to fetch keyChain: %w", err)
+	}
+	if err := signature.VerifyCosignLayer(ctx, artifactRef, imageSpec.Ref, keyChain, keys); err != nil {
+		return err
+	}
+	m.verifiedLayers.Set(verificationKey, struct{}{}, ttlcache.DefaultTTL)
+	return nil
+}
+
 func (m *SpecResolver) lookupKeyChain(
 	ctx context.Context, imageSpec v1beta2.ImageSpec, targetClient client.Client,
 ) (authn.Keychain, error) {
@@ -3,6 +3,7 @@ package flags
 import (
 	"errors"
 	"flag"
+	"net/url"
 	"os"
 	"time"
 
@@ -18,6 +19,7 @@ const (
 	DefaultMandatoryModuleRequeueSuccessInterval                        = 30 * time.Second
 	DefaultMandatoryModuleDeletionRequeueSuccessInterval                = 30 * time.Second
 	DefaultWatcherRequeueSuccessInterval                                = 30 * time.Second
+	DefaultModuleRolloutRequeueSuccessInterval                          = 30 * time.Second
 	DefaultClientQPS                                                    = 300
 	DefaultClientBurst                                                  = 600
 	DefaultPprofServerTimeout                                           = 90 * time.Second
@@ -52,11 +54,29 @@ const (
 	DefaultWatcherResourceLimitsMemory                                  = "200Mi"
 	DefaultDropStoredVersion                                            = "v1alpha1"
 	DefaultMetricsCleanupIntervalInMinutes                              = 15
+	DefaultLayerCacheMaxSize                              int64         = 1 << 30
+	DefaultSkrKubeconfigDir                                             = "/var/run/secrets/skr/kubeconfigs"
+	DefaultSkrTokenDir                                                  = "/var/run/secrets/skr/tokens"
+	DefaultSkrTokenExpiration                                           = 1 * time.Hour
+	DefaultRemoteClientCacheTTL                           time.Duration = 0
+	DefaultRemoteClientCacheMaxEntries                                  = 0
+	DefaultRemoteClientCacheProbeInterval                 time.Duration = 0
+	DefaultRemoteClientCacheProbeTimeout                                = 10 * time.Second
+	DefaultModuleUpgradeRollbackTimeout                   time.Duration = 0
+	DefaultModuleUpgradeRetryInterval                                   = 24 * time.Hour
+	DefaultManifestDriftDetection                                       = "disabled"
+	DefaultCloudEventsBufferSize                                        = 1000
+	DefaultCloudEventsMaxRetries                                        = 5
+	DefaultCloudEventsRetryInterval                                     = 1 * time.Second
+	DefaultTracingSamplingRatio                                         = 1.0
 )
 
 var (
 	errMissingWatcherImageTag = errors.New("runtime watcher image tag is not provided")
 	errWatcherDirNotExist     = errors.New("failed to locate watcher resource manifest folder")
+	errInvalidDriftDetection  = errors.New("manifest drift detection must be one of disabled, report or correct")
+	errInvalidCloudEventsSink = errors.New("cloud events sink must be an absolute http or https URL")
+	errInvalidSamplingRatio   = errors.New("tracing sampling ratio must be between 0 and 1")
 )
 
 //nolint:funlen // defines all program flags
@@ -115,6 +135,9 @@ func DefineFlagVar() *FlagVar {
 	flag.DurationVar(&flagVar.WatcherRequeueSuccessInterval, "watcher-requeue-success-interval",
 		DefaultWatcherRequeueSuccessInterval,
 		"determines the duration a Watcher in Ready state is enqueued for reconciliation.")
+	flag.DurationVar(&flagVar.ModuleRolloutRequeueSuccessInterval, "module-rollout-requeue-success-interval",
+		DefaultModuleRolloutRequeueSuccessInterval,
+		"determines the duration a ModuleRollout in Ready state is enqueued for reconciliation.")
 
 	flag.Float64Var(&flagVar.ClientQPS, "k8s-client-qps", DefaultClientQPS, "kubernetes client QPS")
 	flag.IntVar(&flagVar.ClientBurst, "k8s-client-burst", DefaultClientBurst, "kubernetes client Burst")
@@ -192,6 +215,64 @@ func DefineFlagVar() *FlagVar {
 	flag.IntVar(&flagVar.MetricsCleanupIntervalInMinutes, "metrics-cleanup-interval",
 		DefaultMetricsCleanupIntervalInMinutes,
 		"The interval at which the cleanup of non-existing kyma CRs metrics runs.")
+	flag.StringVar(&flagVar.VerificationSignatureName, "verification-signature-name", "",
+		"Name of the OCM signature all module descriptors must be signed with, verified with the public keys of "+
+			"Secrets labeled with operator.kyma-project.io/signature=<name>. Disabled if empty.")
+	flag.BoolVar(&flagVar.EnableCosignVerification, "enable-cosign-verification", false,
+		"Enabling verification of cosign signatures of the module layers for Manifests labeled with a signature.")
+	flag.StringVar(&flagVar.LayerCacheDir, "layer-cache-dir", "",
+		"Directory of the persistent cache for pulled module layers. Defaults to a directory in the temp dir.")
+	flag.Int64Var(&flagVar.LayerCacheMaxSize, "layer-cache-max-size", DefaultLayerCacheMaxSize,
+		"Maximum size of the layer cache in bytes, least recently used layers are evicted beyond this size.")
+	flag.StringVar(&flagVar.SkrKubeconfigDir, "skr-kubeconfig-dir", DefaultSkrKubeconfigDir,
+		"Directory with a kubeconfig file per Kyma, used for Kymas with the kubeconfig-file sync strategy.")
+	flag.StringVar(&flagVar.SkrTokenDir, "skr-token-dir", DefaultSkrTokenDir,
+		"Directory with a bearer token file per Kyma, used for Kymas with the token-file sync strategy.")
+	flag.StringVar(&flagVar.SkrTokenAudiences, "skr-token-audiences", "",
+		"Comma-separated audiences of the ServiceAccount tokens minted for Kymas with the service-account-token "+
+			"sync strategy. Defaults to the audience of the API server.")
+	flag.DurationVar(&flagVar.SkrTokenExpiration, "skr-token-expiration", DefaultSkrTokenExpiration,
+		"Lifetime of the ServiceAccount tokens minted for Kymas with the service-account-token sync strategy.")
+	flag.DurationVar(&flagVar.RemoteClientCacheTTL, "remote-client-cache-ttl", DefaultRemoteClientCacheTTL,
+		"Duration after which cached clients of remote clusters are recreated. 0 (default) keeps clients forever.")
+	flag.IntVar(&flagVar.RemoteClientCacheMaxEntries, "remote-client-cache-max-entries",
+		DefaultRemoteClientCacheMaxEntries,
+		"Maximum number of cached clients of remote clusters, least recently used clients are evicted first. "+
+			"0 (default) does not limit the cache.")
+	flag.DurationVar(&flagVar.RemoteClientCacheProbeInterval, "remote-client-cache-probe-interval",
+		DefaultRemoteClientCacheProbeInterval,
+		"Interval in which cached clients of remote clusters are probed, clients of unresponsive clusters are "+
+			"dropped. 0 (default) disables the probe.")
+	flag.DurationVar(&flagVar.RemoteClientCacheProbeTimeout, "remote-client-cache-probe-timeout",
+		DefaultRemoteClientCacheProbeTimeout,
+		"Timeout after which a remote cluster is considered unresponsive by the client cache probe.")
+	flag.DurationVar(&flagVar.ModuleUpgradeRollbackTimeout, "module-upgrade-rollback-timeout",
+		DefaultModuleUpgradeRollbackTimeout,
+		"Duration after which a module upgrade that is still in the Error or Processing state is rolled back "+
+			"to the last known good version. 0 (default) disables automatic rollbacks.")
+	flag.DurationVar(&flagVar.ModuleUpgradeRetryInterval, "module-upgrade-retry-interval",
+		DefaultModuleUpgradeRetryInterval,
+		"Duration after which a rolled back module upgrade is retried.")
+	flag.StringVar(&flagVar.ManifestDriftDetection, "manifest-drift-detection", DefaultManifestDriftDetection,
+		"Detection of synced module resources that were changed in the runtime cluster: "+
+			"disabled, report (report drift and leave the resources unchanged) or correct (report and re-apply).")
+	flag.StringVar(&flagVar.CloudEventsSinkURL, "cloud-events-sink-url", "",
+		"HTTP endpoint the lifecycle events of Kymas and modules are published to as CloudEvents. "+
+			"Empty disables the publishing.")
+	flag.IntVar(&flagVar.CloudEventsBufferSize, "cloud-events-buffer-size", DefaultCloudEventsBufferSize,
+		"Maximum number of CloudEvents waiting for delivery. Further events are dropped.")
+	flag.IntVar(&flagVar.CloudEventsMaxRetries, "cloud-events-max-retries", DefaultCloudEventsMaxRetries,
+		"Number of retries of a CloudEvent delivery that failed with a network error or a server error.")
+	flag.DurationVar(&flagVar.CloudEventsRetryInterval, "cloud-events-retry-interval",
+		DefaultCloudEventsRetryInterval,
+		"Interval before the first retry of a CloudEvent delivery, which is doubled for every further retry.")
+	flag.StringVar(&flagVar.TracingEndpoint, "tracing-otlp-endpoint", "",
+		"Host and port of the OTLP HTTP receiver the traces of the reconciliations are exported to. "+
+			"Empty disables the tracing.")
+	flag.BoolVar(&flagVar.TracingInsecure, "tracing-insecure", false,
+		"Export the traces to the OTLP HTTP receiver without TLS.")
+	flag.Float64Var(&flagVar.TracingSamplingRatio, "tracing-sampling-ratio", DefaultTracingSamplingRatio,
+		"Fraction of the reconciliations that are traced, between 0 and 1.")
 	return flagVar
 }
 
@@ -215,6 +296,7 @@ type FlagVar struct {
 	KymaRequeueWarningInterval                     time.Duration
 	ManifestRequeueSuccessInterval                 time.Duration
 	WatcherRequeueSuccessInterval                  time.Duration
+	ModuleRolloutRequeueSuccessInterval            time.Duration
 	MandatoryModuleRequeueSuccessInterval          time.Duration
 	MandatoryModuleDeletionRequeueSuccessInterval  time.Duration
 	ClientQPS                                      float64
@@ -251,6 +333,28 @@ type FlagVar struct {
 	WatcherResourceLimitsCPU               string
 	WatcherResourcesPath                   string
 	MetricsCleanupIntervalInMinutes        int
+	VerificationSignatureName              string
+	EnableCosignVerification               bool
+	LayerCacheDir                          string
+	LayerCacheMaxSize                      int64
+	SkrKubeconfigDir                       string
+	SkrTokenDir                            string
+	SkrTokenAudiences                      string
+	SkrTokenExpiration                     time.Duration
+	RemoteClientCacheTTL                   time.Duration
+	RemoteClientCacheMaxEntries            int
+	RemoteClientCacheProbeInterval         time.Duration
+	RemoteClientCacheProbeTimeout          time.Duration
+	ModuleUpgradeRollbackTimeout           time.Duration
+	ModuleUpgradeRetryInterval             time.Duration
+	ManifestDriftDetection                 string
+	CloudEventsSinkURL                     string
+	CloudEventsBufferSize                  int
+	CloudEventsMaxRetries                  int
+	CloudEventsRetryInterval               time.Duration
+	TracingEndpoint                        string
+	TracingInsecure                        bool
+	TracingSamplingRatio                   float64
 }
 
 func (f FlagVar) Validate() error {
@@ -263,6 +367,20 @@ func (f FlagVar) Validate() error {
 			return errWatcherDirNotExist
 		}
 	}
+	switch f.ManifestDriftDetection {
+	case "disabled", "report", "correct":
+	default:
+		return errInvalidDriftDetection
+	}
+	if f.CloudEventsSinkURL != "" {
+		sinkURL, err := url.Parse(f.CloudEventsSinkURL)
+		if err != nil || !sinkURL.IsAbs() || (sinkURL.Scheme != "http" && sinkURL.Scheme != "https") {
+			return errInvalidCloudEventsSink
+		}
+	}
+	if f.TracingSamplingRatio < 0 || f.TracingSamplingRatio > 1 {
+		return errInvalidSamplingRatio
+	}
 
 	return nil
 }
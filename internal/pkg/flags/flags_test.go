@@ -58,6 +58,11 @@ func Test_ConstantFlags(t *testing.T) {
 			constValue:    DefaultWatcherRequeueSuccessInterval.String(),
 			expectedValue: (30 * time.Second).String(),
 		},
+		{
+			constName:     "DefaultModuleRolloutRequeueSuccessInterval",
+			constValue:    DefaultModuleRolloutRequeueSuccessInterval.String(),
+			expectedValue: (30 * time.Second).String(),
+		},
 		{
 			constName:     "DefaultClientQPS",
 			constValue:    strconv.Itoa(DefaultClientQPS),
@@ -223,6 +228,81 @@ func Test_ConstantFlags(t *testing.T) {
 			constValue:    DefaultDropStoredVersion,
 			expectedValue: "v1alpha1",
 		},
+		{
+			constName:     "DefaultLayerCacheMaxSize",
+			constValue:    strconv.FormatInt(DefaultLayerCacheMaxSize, 10),
+			expectedValue: "1073741824",
+		},
+		{
+			constName:     "DefaultSkrKubeconfigDir",
+			constValue:    DefaultSkrKubeconfigDir,
+			expectedValue: "/var/run/secrets/skr/kubeconfigs",
+		},
+		{
+			constName:     "DefaultSkrTokenDir",
+			constValue:    DefaultSkrTokenDir,
+			expectedValue: "/var/run/secrets/skr/tokens",
+		},
+		{
+			constName:     "DefaultSkrTokenExpiration",
+			constValue:    DefaultSkrTokenExpiration.String(),
+			expectedValue: "1h0m0s",
+		},
+		{
+			constName:     "DefaultRemoteClientCacheTTL",
+			constValue:    DefaultRemoteClientCacheTTL.String(),
+			expectedValue: "0s",
+		},
+		{
+			constName:     "DefaultRemoteClientCacheMaxEntries",
+			constValue:    strconv.Itoa(DefaultRemoteClientCacheMaxEntries),
+			expectedValue: "0",
+		},
+		{
+			constName:     "DefaultRemoteClientCacheProbeInterval",
+			constValue:    DefaultRemoteClientCacheProbeInterval.String(),
+			expectedValue: "0s",
+		},
+		{
+			constName:     "DefaultRemoteClientCacheProbeTimeout",
+			constValue:    DefaultRemoteClientCacheProbeTimeout.String(),
+			expectedValue: "10s",
+		},
+		{
+			constName:     "DefaultModuleUpgradeRollbackTimeout",
+			constValue:    DefaultModuleUpgradeRollbackTimeout.String(),
+			expectedValue: "0s",
+		},
+		{
+			constName:     "DefaultModuleUpgradeRetryInterval",
+			constValue:    DefaultModuleUpgradeRetryInterval.String(),
+			expectedValue: "24h0m0s",
+		},
+		{
+			constName:     "DefaultManifestDriftDetection",
+			constValue:    DefaultManifestDriftDetection,
+			expectedValue: "disabled",
+		},
+		{
+			constName:     "DefaultCloudEventsBufferSize",
+			constValue:    strconv.Itoa(DefaultCloudEventsBufferSize),
+			expectedValue: "1000",
+		},
+		{
+			constName:     "DefaultCloudEventsMaxRetries",
+			constValue:    strconv.Itoa(DefaultCloudEventsMaxRetries),
+			expectedValue: "5",
+		},
+		{
+			constName:     "DefaultCloudEventsRetryInterval",
+			constValue:    DefaultCloudEventsRetryInterval.String(),
+			expectedValue: "1s",
+		},
+		{
+			constName:     "DefaultTracingSamplingRatio",
+			constValue:    strconv.FormatFloat(DefaultTracingSamplingRatio, 'f', -1, 64),
+			expectedValue: "1",
+		},
 	}
 	for _, testcase := range tests {
 		testcase := testcase
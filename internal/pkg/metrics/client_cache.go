@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	MetricClientCacheSize                                    = "lifecycle_mgr_remote_client_cache_size"
+	MetricClientCacheRequests                                = "lifecycle_mgr_remote_client_cache_requests_total"
+	MetricClientCacheEvictions                               = "lifecycle_mgr_remote_client_cache_evictions_total"
+	clientCacheResultLabel                                   = "result"
+	clientCacheReasonLabel                                   = "reason"
+	clientCacheHit                                           = "hit"
+	clientCacheMiss                                          = "miss"
+	ClientCacheEvictionExpired     ClientCacheEvictionReason = "expired"
+	ClientCacheEvictionCapacity    ClientCacheEvictionReason = "capacity"
+	ClientCacheEvictionUnhealthy   ClientCacheEvictionReason = "unhealthy"
+	ClientCacheEvictionInvalidated ClientCacheEvictionReason = "invalidated"
+)
+
+type ClientCacheEvictionReason string
+
+type ClientCacheMetrics struct {
+	sizeGauge        prometheus.Gauge
+	requestsCounter  *prometheus.CounterVec
+	evictionsCounter *prometheus.CounterVec
+}
+
+func NewClientCacheMetrics() *ClientCacheMetrics {
+	clientCacheMetrics := &ClientCacheMetrics{
+		sizeGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: MetricClientCacheSize,
+			Help: "Indicates the number of cached clients for remote clusters",
+		}),
+		requestsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: MetricClientCacheRequests,
+			Help: "Indicates the number of remote client cache lookups by result (hit or miss)",
+		}, []string{clientCacheResultLabel}),
+		evictionsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: MetricClientCacheEvictions,
+			Help: "Indicates the number of clients dropped from the remote client cache by reason",
+		}, []string{clientCacheReasonLabel}),
+	}
+	ctrlmetrics.Registry.MustRegister(clientCacheMetrics.sizeGauge)
+	ctrlmetrics.Registry.MustRegister(clientCacheMetrics.requestsCounter)
+	ctrlmetrics.Registry.MustRegister(clientCacheMetrics.evictionsCounter)
+	return clientCacheMetrics
+}
+
+func (m *ClientCacheMetrics) SetSize(size int) {
+	m.sizeGauge.Set(float64(size))
+}
+
+func (m *ClientCacheMetrics) RecordHit() {
+	m.requestsCounter.WithLabelValues(clientCacheHit).Inc()
+}
+
+func (m *ClientCacheMetrics) RecordMiss() {
+	m.requestsCounter.WithLabelValues(clientCacheMiss).Inc()
+}
+
+func (m *ClientCacheMetrics) RecordEviction(reason ClientCacheEvictionReason) {
+	m.evictionsCounter.WithLabelValues(string(reason)).Inc()
+}
@@ -53,6 +53,7 @@ const (
 	KymaDeletion                             KymaRequeueReason = "kyma_deletion"
 	KymaRetrieval                            KymaRequeueReason = "kyma_retrieval"
 	KymaUnauthorized                         KymaRequeueReason = "kyma_unauthorized"
+	KymaPlan                                 KymaRequeueReason = "kyma_plan"
 )
 
 func NewKymaMetrics(sharedMetrics *SharedMetrics) *KymaMetrics {
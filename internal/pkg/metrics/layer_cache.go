@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	MetricLayerCacheRequests  = "lifecycle_mgr_layer_cache_requests_total"
+	MetricLayerCacheEvictions = "lifecycle_mgr_layer_cache_evictions_total"
+	MetricLayerCacheSize      = "lifecycle_mgr_layer_cache_size_bytes"
+	layerCacheResultLabel     = "result"
+	layerCacheHit             = "hit"
+	layerCacheMiss            = "miss"
+	layerCacheCorrupt         = "corrupt"
+)
+
+type LayerCacheMetrics struct {
+	requestsCounter  *prometheus.CounterVec
+	evictionsCounter prometheus.Counter
+	sizeGauge        prometheus.Gauge
+}
+
+func NewLayerCacheMetrics() *LayerCacheMetrics {
+	layerCacheMetrics := &LayerCacheMetrics{
+		requestsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: MetricLayerCacheRequests,
+			Help: "Indicates the number of layer cache lookups by result (hit, miss or corrupt)",
+		}, []string{layerCacheResultLabel}),
+		evictionsCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: MetricLayerCacheEvictions,
+			Help: "Indicates the number of layers evicted from the layer cache",
+		}),
+		sizeGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: MetricLayerCacheSize,
+			Help: "Indicates the total size of all layers in the layer cache",
+		}),
+	}
+	ctrlmetrics.Registry.MustRegister(layerCacheMetrics.requestsCounter)
+	ctrlmetrics.Registry.MustRegister(layerCacheMetrics.evictionsCounter)
+	ctrlmetrics.Registry.MustRegister(layerCacheMetrics.sizeGauge)
+	return layerCacheMetrics
+}
+
+func (m *LayerCacheMetrics) RecordHit() {
+	m.requestsCounter.WithLabelValues(layerCacheHit).Inc()
+}
+
+func (m *LayerCacheMetrics) RecordMiss() {
+	m.requestsCounter.WithLabelValues(layerCacheMiss).Inc()
+}
+
+// RecordCorrupt records a lookup of a layer whose pulled content did not match its digest.
+func (m *LayerCacheMetrics) RecordCorrupt() {
+	m.requestsCounter.WithLabelValues(layerCacheCorrupt).Inc()
+}
+
+func (m *LayerCacheMetrics) RecordEviction() {
+	m.evictionsCounter.Inc()
+}
+
+func (m *LayerCacheMetrics) SetSize(size int64) {
+	m.sizeGauge.Set(float64(size))
+}
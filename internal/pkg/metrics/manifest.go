@@ -1,6 +1,18 @@
 package metrics
 
-import "github.com/kyma-project/lifecycle-manager/pkg/queue"
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/kyma-project/lifecycle-manager/pkg/queue"
+)
+
+const (
+	MetricManifestDriftedResources = "lifecycle_mgr_manifest_drifted_resources"
+	MetricManifestDriftCorrections = "lifecycle_mgr_manifest_drift_corrections_total"
+	MetricManifestTimeoutExceeded  = "lifecycle_mgr_manifest_timeout_exceeded"
+	operationLabel                 = "operation"
+)
 
 type ManifestRequeueReason string
 
@@ -24,16 +36,68 @@ const (
 	ManifestSyncResourcesEnqueueRequired  ManifestRequeueReason = "manifest_sync_resources_enqueue_required"
 	ManifestSyncResources                 ManifestRequeueReason = "manifest_sync_resources"
 	ManifestUnauthorized                  ManifestRequeueReason = "manifest_unauthorized"
+	ManifestDryRun                        ManifestRequeueReason = "manifest_dry_run"
+	ManifestDryRunCleanup                 ManifestRequeueReason = "manifest_dry_run_cleanup"
+	ManifestDriftDetection                ManifestRequeueReason = "manifest_drift_detection"
 )
 
 type ManifestMetrics struct {
 	*SharedMetrics
+	driftedResourcesGauge  *prometheus.GaugeVec
+	driftCorrectionCounter *prometheus.CounterVec
+	timeoutExceededGauge   *prometheus.GaugeVec
 }
 
 func NewManifestMetrics(sharedMetrics *SharedMetrics) *ManifestMetrics {
-	return &ManifestMetrics{SharedMetrics: sharedMetrics}
+	manifestMetrics := &ManifestMetrics{
+		SharedMetrics: sharedMetrics,
+		driftedResourcesGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: MetricManifestDriftedResources,
+			Help: "Indicates the number of synced resources of a module that drifted from the target",
+		}, []string{KymaNameLabel, moduleNameLabel}),
+		driftCorrectionCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: MetricManifestDriftCorrections,
+			Help: "Indicates the number of drifted resources of a module that were re-applied",
+		}, []string{KymaNameLabel, moduleNameLabel}),
+		timeoutExceededGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: MetricManifestTimeoutExceeded,
+			Help: "Indicates that the install or delete operation of a module exceeded the timeout of its ModuleTemplate",
+		}, []string{KymaNameLabel, moduleNameLabel, operationLabel}),
+	}
+	ctrlmetrics.Registry.MustRegister(manifestMetrics.driftedResourcesGauge)
+	ctrlmetrics.Registry.MustRegister(manifestMetrics.driftCorrectionCounter)
+	ctrlmetrics.Registry.MustRegister(manifestMetrics.timeoutExceededGauge)
+	return manifestMetrics
 }
 
 func (k *ManifestMetrics) RecordRequeueReason(requeueReason ManifestRequeueReason, requeueType queue.RequeueType) {
 	k.requeueReasonCounter.WithLabelValues(string(requeueReason), string(requeueType)).Inc()
 }
+
+func (k *ManifestMetrics) SetDriftedResources(kymaName, moduleName string, count int) {
+	k.driftedResourcesGauge.WithLabelValues(kymaName, moduleName).Set(float64(count))
+}
+
+func (k *ManifestMetrics) RecordDriftCorrections(kymaName, moduleName string, count int) {
+	if count > 0 {
+		k.driftCorrectionCounter.WithLabelValues(kymaName, moduleName).Add(float64(count))
+	}
+}
+
+// SetTimeoutExceeded indicates whether the operation of the module exceeded its timeout.
+// Operations within their timeout are not reported.
+func (k *ManifestMetrics) SetTimeoutExceeded(kymaName, moduleName, operation string, exceeded bool) {
+	if exceeded {
+		k.timeoutExceededGauge.WithLabelValues(kymaName, moduleName, operation).Set(1)
+		return
+	}
+	k.timeoutExceededGauge.DeleteLabelValues(kymaName, moduleName, operation)
+}
+
+// RemoveManifestMetrics deletes the drift and timeout metrics of the module once its Manifest is deleted.
+func (k *ManifestMetrics) RemoveManifestMetrics(kymaName, moduleName string) {
+	labels := prometheus.Labels{KymaNameLabel: kymaName, moduleNameLabel: moduleName}
+	k.driftedResourcesGauge.Delete(labels)
+	k.driftCorrectionCounter.Delete(labels)
+	k.timeoutExceededGauge.DeletePartialMatch(labels)
+}
@@ -39,6 +39,51 @@ func Test_ConstantMetricNames(t *testing.T) {
 			constValue:    MetricPurgeError,
 			expectedValue: "lifecycle_mgr_purgectrl_error",
 		},
+		{
+			constName:     "MetricLayerCacheRequests",
+			constValue:    MetricLayerCacheRequests,
+			expectedValue: "lifecycle_mgr_layer_cache_requests_total",
+		},
+		{
+			constName:     "MetricLayerCacheEvictions",
+			constValue:    MetricLayerCacheEvictions,
+			expectedValue: "lifecycle_mgr_layer_cache_evictions_total",
+		},
+		{
+			constName:     "MetricLayerCacheSize",
+			constValue:    MetricLayerCacheSize,
+			expectedValue: "lifecycle_mgr_layer_cache_size_bytes",
+		},
+		{
+			constName:     "MetricClientCacheSize",
+			constValue:    MetricClientCacheSize,
+			expectedValue: "lifecycle_mgr_remote_client_cache_size",
+		},
+		{
+			constName:     "MetricClientCacheRequests",
+			constValue:    MetricClientCacheRequests,
+			expectedValue: "lifecycle_mgr_remote_client_cache_requests_total",
+		},
+		{
+			constName:     "MetricClientCacheEvictions",
+			constValue:    MetricClientCacheEvictions,
+			expectedValue: "lifecycle_mgr_remote_client_cache_evictions_total",
+		},
+		{
+			constName:     "MetricManifestDriftedResources",
+			constValue:    MetricManifestDriftedResources,
+			expectedValue: "lifecycle_mgr_manifest_drifted_resources",
+		},
+		{
+			constName:     "MetricManifestDriftCorrections",
+			constValue:    MetricManifestDriftCorrections,
+			expectedValue: "lifecycle_mgr_manifest_drift_corrections_total",
+		},
+		{
+			constName:     "MetricManifestTimeoutExceeded",
+			constValue:    MetricManifestTimeoutExceeded,
+			expectedValue: "lifecycle_mgr_manifest_timeout_exceeded",
+		},
 		{
 			constName:     "SelfSignedCertNotRenewMetrics",
 			constValue:    SelfSignedCertNotRenewMetrics,
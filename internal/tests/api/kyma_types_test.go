@@ -1,8 +1,11 @@
 package api_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kyma-project/lifecycle-manager/api/shared"
@@ -70,3 +73,38 @@ func TestKyma_DetermineState(t *testing.T) {
 		})
 	}
 }
+
+func TestModuleStatus_RecordStateTransition(t *testing.T) {
+	t.Parallel()
+	status := v1beta2.ModuleStatus{Name: "test", Version: "1.0.0", State: shared.StateProcessing}
+	status.RecordStateTransition("", "installing")
+	status.State = shared.StateReady
+	status.RecordStateTransition(shared.StateProcessing, "installation is ready")
+	status.RecordStateTransition(shared.StateReady, "still ready")
+
+	require.Len(t, status.History, 2)
+	assert.Equal(t, shared.State(""), status.History[0].From)
+	assert.Equal(t, shared.StateProcessing, status.History[0].To)
+	assert.Equal(t, "installing", status.History[0].Reason)
+	assert.Equal(t, shared.StateProcessing, status.History[1].From)
+	assert.Equal(t, shared.StateReady, status.History[1].To)
+	assert.Equal(t, "1.0.0", status.History[1].Version)
+	assert.False(t, status.History[1].Time.IsZero())
+}
+
+func TestKymaStatus_RecordStateTransition_IsBounded(t *testing.T) {
+	t.Parallel()
+	status := v1beta2.KymaStatus{}
+	states := []shared.State{shared.StateProcessing, shared.StateError}
+	for i := 0; i < v1beta2.MaxKymaStateTransitions+4; i++ {
+		previous := status.State
+		status.State = states[i%2]
+		status.RecordStateTransition(previous, strings.Repeat("x", v1beta2.MaxStateTransitionReasonLength+1))
+	}
+
+	require.Len(t, status.History, v1beta2.MaxKymaStateTransitions)
+	assert.Equal(t, shared.StateError, status.History[0].From)
+	assert.Equal(t, shared.StateError, status.History[len(status.History)-1].To)
+	assert.Len(t, status.History[0].Reason, v1beta2.MaxStateTransitionReasonLength)
+	assert.True(t, strings.HasSuffix(status.History[0].Reason, "..."))
+}
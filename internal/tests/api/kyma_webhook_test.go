@@ -0,0 +1,141 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/testutils/builder"
+)
+
+// rejectingModuleValidator rejects every module with the given name.
+type rejectingModuleValidator struct {
+	name string
+}
+
+func (v rejectingModuleValidator) ValidateModules(_ context.Context, kyma *v1beta2.Kyma) field.ErrorList {
+	var errs field.ErrorList
+	for i, module := range kyma.Spec.Modules {
+		if module.Name == v.name {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "modules").Index(i), "rejected"))
+		}
+	}
+	return errs
+}
+
+func TestKymaValidator_ValidateCreate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		syncEnabled    bool
+		kyma           *v1beta2.Kyma
+		expectedFields []string
+	}{
+		{
+			"valid modules",
+			true,
+			builder.NewKymaBuilder().WithModule(v1beta2.Module{Name: "a"}).
+				WithModule(v1beta2.Module{Name: "b", RemoteModuleTemplateRef: "b-template"}).Build(),
+			nil,
+		},
+		{
+			"duplicate module",
+			true,
+			builder.NewKymaBuilder().WithModule(v1beta2.Module{Name: "a"}).
+				WithModule(v1beta2.Module{Name: "a", Channel: "fast"}).Build(),
+			[]string{"spec.modules[1].name"},
+		},
+		{
+			"remote module template without sync",
+			false,
+			builder.NewKymaBuilder().WithModule(v1beta2.Module{Name: "a", RemoteModuleTemplateRef: "a"}).Build(),
+			[]string{"spec.modules[0].remoteModuleTemplateRef"},
+		},
+		{
+			"remote module template with disabled sync label",
+			true,
+			builder.NewKymaBuilder().WithLabel(shared.SyncLabel, shared.DisableLabelValue).
+				WithModule(v1beta2.Module{Name: "a", RemoteModuleTemplateRef: "a"}).Build(),
+			[]string{"spec.modules[0].remoteModuleTemplateRef"},
+		},
+		{
+			"module rejected by module validator",
+			true,
+			builder.NewKymaBuilder().WithModule(v1beta2.Module{Name: "a"}).
+				WithModule(v1beta2.Module{Name: "rejected"}).Build(),
+			[]string{"spec.modules[1]"},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			validator := &v1beta2.KymaValidator{
+				SyncEnabled: testCase.syncEnabled,
+				Modules:     rejectingModuleValidator{name: "rejected"},
+			}
+
+			_, err := validator.ValidateCreate(context.Background(), testCase.kyma)
+
+			if testCase.expectedFields == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, apierrors.IsInvalid(err))
+			var statusErr *apierrors.StatusError
+			require.ErrorAs(t, err, &statusErr)
+			var fields []string
+			for _, cause := range statusErr.ErrStatus.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			assert.Equal(t, testCase.expectedFields, fields)
+		})
+	}
+}
+
+func TestKymaValidator_ValidateUpdate(t *testing.T) {
+	t.Parallel()
+	invalidKyma := builder.NewKymaBuilder().WithModule(v1beta2.Module{Name: "rejected"}).Build()
+	tests := []struct {
+		name        string
+		update      func(kyma *v1beta2.Kyma)
+		expectError bool
+	}{
+		{"unchanged spec", func(kyma *v1beta2.Kyma) { kyma.Finalizers = append(kyma.Finalizers, "test") }, false},
+		{"changed spec", func(kyma *v1beta2.Kyma) { kyma.Spec.Channel = "fast" }, true},
+		{"changed beta label", func(kyma *v1beta2.Kyma) { kyma.SetLabels(map[string]string{shared.BetaLabel: "true"}) }, true},
+		{
+			"deletion",
+			func(kyma *v1beta2.Kyma) {
+				now := apimetav1.Now()
+				kyma.DeletionTimestamp = &now
+				kyma.Spec.Channel = "fast"
+			},
+			false,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			validator := &v1beta2.KymaValidator{Modules: rejectingModuleValidator{name: "rejected"}}
+			updated := invalidKyma.DeepCopy()
+			testCase.update(updated)
+
+			_, err := validator.ValidateUpdate(context.Background(), invalidKyma, updated)
+
+			if testCase.expectError {
+				require.True(t, apierrors.IsInvalid(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
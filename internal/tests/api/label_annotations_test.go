@@ -14,12 +14,22 @@ func Test_AnnotationHasExternalDependencies(t *testing.T) {
 	assert.Equal(t, "operator.kyma-project.io/is-cluster-scoped", shared.IsClusterScopedAnnotation)
 	assert.Equal(t, "operator.kyma-project.io/custom-state-check", shared.CustomStateCheckAnnotation)
 	assert.Equal(t, "skr-domain", shared.SKRDomainAnnotation)
+	assert.Equal(t, "operator.kyma-project.io/plan", shared.PlanAnnotation)
+	assert.Equal(t, "operator.kyma-project.io/dependencies", shared.DependenciesAnnotation)
+	assert.Equal(t, "operator.kyma-project.io/last-known-good", shared.LastKnownGoodAnnotation)
+	assert.Equal(t, "operator.kyma-project.io/rollback-to", shared.RollbackToAnnotation)
+	assert.Equal(t, "operator.kyma-project.io/module-config", shared.ModuleConfigAnnotation)
+	assert.Equal(t, "operator.kyma-project.io/custom-resource-policy", shared.CustomResourcePolicyAnnotation)
+	assert.Equal(t, "operator.kyma-project.io/install-timeout", shared.InstallTimeoutAnnotation)
+	assert.Equal(t, "operator.kyma-project.io/delete-timeout", shared.DeleteTimeoutAnnotation)
+	assert.Equal(t, "operator.kyma-project.io/traceparent", shared.TraceParentAnnotation)
 }
 
 func Test_LabelHasExternalDependencies(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, "kyma-project.io/instance-id", shared.InstanceIDLabel)
 	assert.Equal(t, "operator.kyma-project.io/skip-reconciliation", shared.SkipReconcileLabel)
+	assert.Equal(t, "operator.kyma-project.io/dry-run", shared.DryRunLabel)
 	assert.Equal(t, "operator.kyma-project.io/internal", shared.InternalLabel)
 	assert.Equal(t, "operator.kyma-project.io/beta", shared.BetaLabel)
 	assert.Equal(t, "operator.kyma-project.io/sync", shared.SyncLabel)
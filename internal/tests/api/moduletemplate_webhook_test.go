@@ -1,11 +1,19 @@
 package api_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/Masterminds/semver/v3"
+	compdescv2 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/versions/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/kyma-project/lifecycle-manager/api/shared"
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/testutils/builder"
 )
 
 func Test_ValidateVersion(t *testing.T) {
@@ -59,3 +67,107 @@ func Test_ValidateVersion(t *testing.T) {
 		})
 	}
 }
+
+// rejectingSchemaValidator rejects the data of every ModuleTemplate.
+type rejectingSchemaValidator struct{}
+
+func (rejectingSchemaValidator) ValidateSchema(_ context.Context, _ *v1beta2.ModuleTemplate) field.ErrorList {
+	return field.ErrorList{field.Invalid(field.NewPath("spec", "data"), nil, "rejected")}
+}
+
+func TestModuleTemplateValidator_ValidateCreate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		stateChecks    []*v1beta2.CustomStateCheck
+		schema         v1beta2.ModuleTemplateSchemaValidator
+		expectedFields []string
+	}{
+		{"without customStateChecks", nil, nil, nil},
+		{
+			"with Ready and Error mapping",
+			[]*v1beta2.CustomStateCheck{
+				{JSONPath: "status.health", Value: "green", MappedState: shared.StateReady},
+				{JSONPath: "status.health", Value: "red", MappedState: shared.StateError},
+			},
+			nil,
+			nil,
+		},
+		{
+			"without Error mapping",
+			[]*v1beta2.CustomStateCheck{
+				{JSONPath: "status.health", Value: "green", MappedState: shared.StateReady},
+				{JSONPath: "status.health", Value: "yellow", MappedState: shared.StateWarning},
+			},
+			nil,
+			[]string{"spec.customStateCheck"},
+		},
+		{
+			"without Ready and Error mapping",
+			[]*v1beta2.CustomStateCheck{
+				{JSONPath: "status.health", Value: "yellow", MappedState: shared.StateWarning},
+			},
+			nil,
+			[]string{"spec.customStateCheck", "spec.customStateCheck"},
+		},
+		{"data rejected by the schema", nil, rejectingSchemaValidator{}, []string{"spec.data"}},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			validator := &v1beta2.ModuleTemplateValidator{Schema: testCase.schema}
+			template := builder.NewModuleTemplateBuilder().WithOCM(compdescv2.SchemaVersion).Build()
+			template.Spec.CustomStateCheck = testCase.stateChecks
+
+			_, err := validator.ValidateCreate(context.Background(), template)
+
+			if testCase.expectedFields == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, apierrors.IsInvalid(err))
+			var statusErr *apierrors.StatusError
+			require.ErrorAs(t, err, &statusErr)
+			var fields []string
+			for _, cause := range statusErr.ErrStatus.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			assert.Equal(t, testCase.expectedFields, fields)
+		})
+	}
+}
+
+func TestModuleTemplateValidator_ValidateUpdate(t *testing.T) {
+	t.Parallel()
+	invalidTemplate := builder.NewModuleTemplateBuilder().WithOCM(compdescv2.SchemaVersion).Build()
+	tests := []struct {
+		name        string
+		update      func(template *v1beta2.ModuleTemplate)
+		expectError bool
+	}{
+		{
+			"unchanged spec",
+			func(template *v1beta2.ModuleTemplate) { template.Finalizers = append(template.Finalizers, "test") },
+			false,
+		},
+		{"changed spec", func(template *v1beta2.ModuleTemplate) { template.Spec.Channel = "fast" }, true},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			validator := &v1beta2.ModuleTemplateValidator{Schema: rejectingSchemaValidator{}}
+			updated := invalidTemplate.DeepCopy()
+			testCase.update(updated)
+
+			_, err := validator.ValidateUpdate(context.Background(), invalidTemplate, updated)
+
+			if testCase.expectError {
+				require.True(t, apierrors.IsInvalid(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -20,14 +20,27 @@ const (
 )
 
 func ParseManifestToObjects(path string) (ManifestResources, error) {
-	objects := &ManifestResources{}
 	builder := resource.NewLocalBuilder().
 		Unstructured().
 		Path(false, path).
 		Flatten().
 		ContinueOnError()
+	return parseBuilderResult(builder.Do())
+}
 
-	result := builder.Do()
+// ParseManifestStringToObjects parses a multi-document YAML manifest, e.g. the output of a rendering,
+// in the same way as ParseManifestToObjects parses a manifest file.
+func ParseManifestStringToObjects(name, manifest string) (ManifestResources, error) {
+	builder := resource.NewLocalBuilder().
+		Unstructured().
+		Stream(strings.NewReader(manifest), name).
+		Flatten().
+		ContinueOnError()
+	return parseBuilderResult(builder.Do())
+}
+
+func parseBuilderResult(result *resource.Result) (ManifestResources, error) {
+	objects := &ManifestResources{}
 
 	if err := result.Err(); err != nil {
 		return ManifestResources{}, fmt.Errorf("parse manifest: %w", err)
@@ -4,11 +4,16 @@ import (
 	"context"
 
 	"k8s.io/client-go/tools/record"
+
+	"github.com/kyma-project/lifecycle-manager/pkg/cloudevents"
 )
 
 type key string
 
-const contextKey key = "EventRecorder"
+const (
+	contextKey          key = "EventRecorder"
+	eventSinkContextKey key = "EventSink"
+)
 
 type EventingAdapter func(eventType, reason, message string)
 
@@ -23,3 +28,36 @@ func RecorderFromContext(ctx context.Context) record.EventRecorder {
 func ContextWithRecorder(ctx context.Context, recorder record.EventRecorder) context.Context {
 	return context.WithValue(ctx, contextKey, recorder)
 }
+
+// EventSinkFromContext returns the sink for lifecycle events, which discards the events if no sink is set.
+func EventSinkFromContext(ctx context.Context) cloudevents.Sink {
+	sink, ok := ctx.Value(eventSinkContextKey).(cloudevents.Sink)
+	if !ok || sink == nil {
+		return cloudevents.NopSink{}
+	}
+	return sink
+}
+
+func ContextWithEventSink(ctx context.Context, sink cloudevents.Sink) context.Context {
+	return context.WithValue(ctx, eventSinkContextKey, sink)
+}
+
+// ContextWithPendingEvents holds back the lifecycle events published to the sink of the context
+// until PublishPendingEvents is called.
+func ContextWithPendingEvents(ctx context.Context) context.Context {
+	return ContextWithEventSink(ctx, cloudevents.NewPendingSink(EventSinkFromContext(ctx)))
+}
+
+// PublishPendingEvents publishes the lifecycle events held back by ContextWithPendingEvents.
+func PublishPendingEvents(ctx context.Context) {
+	if pending, ok := ctx.Value(eventSinkContextKey).(*cloudevents.PendingSink); ok {
+		pending.Flush(ctx)
+	}
+}
+
+// DiscardPendingEvents drops the lifecycle events held back by ContextWithPendingEvents.
+func DiscardPendingEvents(ctx context.Context) {
+	if pending, ok := ctx.Value(eventSinkContextKey).(*cloudevents.PendingSink); ok {
+		pending.Discard()
+	}
+}
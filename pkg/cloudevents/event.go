@@ -0,0 +1,84 @@
+package cloudevents
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+)
+
+const (
+	// SpecVersion is the version of the CloudEvents specification the events conform to.
+	SpecVersion = "1.0"
+	// ContentType is the content type of events in the structured content mode of the HTTP protocol binding.
+	ContentType = "application/cloudevents+json"
+	// Source identifies Lifecycle Manager as the source of the events.
+	Source = "/kyma-project/lifecycle-manager"
+)
+
+const (
+	TypeModuleInstalled = "io.kyma-project.lifecycle-manager.module.installed"
+	TypeModuleUpgraded  = "io.kyma-project.lifecycle-manager.module.upgraded"
+	TypeModuleRecovered = "io.kyma-project.lifecycle-manager.module.recovered"
+	TypeModuleFailed    = "io.kyma-project.lifecycle-manager.module.failed"
+	TypeModuleDeleted   = "io.kyma-project.lifecycle-manager.module.deleted"
+	TypeKymaReady       = "io.kyma-project.lifecycle-manager.kyma.ready"
+	TypeKymaError       = "io.kyma-project.lifecycle-manager.kyma.error"
+)
+
+// Event is a CloudEvent in the structured content mode.
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype,omitempty"`
+	Data            any       `json:"data,omitempty"`
+}
+
+// KymaData is the data of the Kyma events.
+type KymaData struct {
+	Kyma          string       `json:"kyma"`
+	Namespace     string       `json:"namespace"`
+	State         shared.State `json:"state"`
+	PreviousState shared.State `json:"previousState,omitempty"`
+	Message       string       `json:"message,omitempty"`
+}
+
+// ModuleData is the data of the module events.
+type ModuleData struct {
+	Kyma          string       `json:"kyma"`
+	Namespace     string       `json:"namespace"`
+	Module        string       `json:"module"`
+	Channel       string       `json:"channel,omitempty"`
+	Version       string       `json:"version,omitempty"`
+	State         shared.State `json:"state,omitempty"`
+	PreviousState shared.State `json:"previousState,omitempty"`
+	Message       string       `json:"message,omitempty"`
+}
+
+// NewKymaEvent creates an event of the Kyma, which is the subject of the event.
+func NewKymaEvent(eventType string, data KymaData) Event {
+	return newEvent(eventType, data.Namespace+"/"+data.Kyma, data)
+}
+
+// NewModuleEvent creates an event of the module of a Kyma, which is the subject of the event.
+func NewModuleEvent(eventType string, data ModuleData) Event {
+	return newEvent(eventType, data.Namespace+"/"+data.Kyma+"/"+data.Module, data)
+}
+
+func newEvent(eventType, subject string, data any) Event {
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              string(uuid.NewUUID()),
+		Source:          Source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+}
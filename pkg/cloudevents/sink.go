@@ -0,0 +1,156 @@
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kyma-project/lifecycle-manager/pkg/log"
+)
+
+var ErrDeliveryFailed = errors.New("cloud event delivery failed")
+
+const sendTimeout = 10 * time.Second
+
+// Sink publishes lifecycle events. Publish must not block the reconciliation.
+type Sink interface {
+	Publish(ctx context.Context, event Event)
+}
+
+// NopSink discards all events. It is used if no sink is configured.
+type NopSink struct{}
+
+func (NopSink) Publish(context.Context, Event) {}
+
+// PendingSink holds back the published events until Flush passes them on to the sink, e.g. once the state
+// they report is persisted. Discard drops the held back events.
+type PendingSink struct {
+	sink   Sink
+	mu     sync.Mutex
+	events []Event
+}
+
+func NewPendingSink(sink Sink) *PendingSink {
+	return &PendingSink{sink: sink}
+}
+
+func (s *PendingSink) Publish(_ context.Context, event Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+}
+
+// Flush publishes the held back events to the sink in the order they were published.
+func (s *PendingSink) Flush(ctx context.Context) {
+	s.mu.Lock()
+	events := s.events
+	s.events = nil
+	s.mu.Unlock()
+	for _, event := range events {
+		s.sink.Publish(ctx, event)
+	}
+}
+
+func (s *PendingSink) Discard() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = nil
+}
+
+// HTTPSink publishes events in the structured content mode to an HTTP endpoint.
+// Events are buffered and delivered in the order they were published by Start.
+type HTTPSink struct {
+	url           string
+	client        *http.Client
+	events        chan Event
+	maxRetries    int
+	retryInterval time.Duration
+}
+
+// NewHTTPSink creates a sink that buffers up to bufferSize events and retries the delivery of an event
+// up to maxRetries times with an exponential backoff starting at the retryInterval.
+func NewHTTPSink(url string, bufferSize, maxRetries int, retryInterval time.Duration) *HTTPSink {
+	return &HTTPSink{
+		url:           url,
+		client:        &http.Client{Timeout: sendTimeout},
+		events:        make(chan Event, bufferSize),
+		maxRetries:    maxRetries,
+		retryInterval: retryInterval,
+	}
+}
+
+// Publish adds the event to the buffer. If the buffer is full, the event is dropped.
+func (s *HTTPSink) Publish(ctx context.Context, event Event) {
+	select {
+	case s.events <- event:
+	default:
+		logf.FromContext(ctx).Info("dropped cloud event as the buffer is full",
+			"type", event.Type, "subject", event.Subject)
+	}
+}
+
+// Start delivers the buffered events until the context is done. It implements manager.Runnable.
+func (s *HTTPSink) Start(ctx context.Context) error {
+	logger := logf.FromContext(ctx).WithName("cloudevents")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-s.events:
+			if err := s.deliver(ctx, event); err != nil {
+				logger.Error(err, "dropped cloud event", "type", event.Type, "subject", event.Subject)
+				continue
+			}
+			logger.V(log.DebugLevel).Info("delivered cloud event", "type", event.Type, "subject", event.Subject)
+		}
+	}
+}
+
+func (s *HTTPSink) deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cloud event: %w", err)
+	}
+	for attempt := 0; ; attempt++ {
+		retry, err := s.send(ctx, body)
+		if err == nil || !retry {
+			return err
+		}
+		if attempt == s.maxRetries {
+			return fmt.Errorf("%w after %d attempts", err, attempt+1)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", err, ctx.Err())
+		case <-time.After(s.retryInterval << attempt):
+		}
+	}
+}
+
+// send posts the event and reports whether a failed delivery can be retried.
+func (s *HTTPSink) send(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrDeliveryFailed, err)
+	}
+	req.Header.Set("Content-Type", ContentType)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("%w: %w", ErrDeliveryFailed, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return false, nil
+	}
+	retry := resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("%w: endpoint responded with %s", ErrDeliveryFailed, resp.Status)
+}
@@ -0,0 +1,175 @@
+package cloudevents_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/pkg/cloudevents"
+)
+
+// endpoint is a local stand-in for a CloudEvents receiver that responds with the given status codes in order.
+type endpoint struct {
+	mu          sync.Mutex
+	statusCodes []int
+	attempts    int
+	received    []map[string]any
+}
+
+func (e *endpoint) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	statusCode := http.StatusAccepted
+	if e.attempts < len(e.statusCodes) {
+		statusCode = e.statusCodes[e.attempts]
+	}
+	e.attempts++
+	if req.Header.Get("Content-Type") != cloudevents.ContentType {
+		statusCode = http.StatusUnsupportedMediaType
+	}
+	if statusCode < http.StatusMultipleChoices {
+		body, _ := io.ReadAll(req.Body)
+		event := map[string]any{}
+		_ = json.Unmarshal(body, &event)
+		e.received = append(e.received, event)
+	}
+	writer.WriteHeader(statusCode)
+}
+
+func (e *endpoint) state() (int, []map[string]any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.attempts, e.received
+}
+
+func TestHTTPSink_Delivery(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		statusCodes      []int
+		expectedAttempts int
+		expectDelivered  bool
+	}{
+		{"delivered", nil, 1, true},
+		{"retried after server errors", []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}, 3, true},
+		{
+			"dropped after max retries",
+			[]int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout},
+			3,
+			false,
+		},
+		{"dropped after client error", []int{http.StatusBadRequest}, 1, false},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			receiver := &endpoint{statusCodes: testCase.statusCodes}
+			server := httptest.NewServer(receiver)
+			defer server.Close()
+			sink := cloudevents.NewHTTPSink(server.URL, 10, 2, time.Millisecond)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() { _ = sink.Start(ctx) }()
+
+			sink.Publish(ctx, cloudevents.NewKymaEvent(cloudevents.TypeKymaReady, cloudevents.KymaData{
+				Kyma: "test-kyma", Namespace: "kcp-system", State: shared.StateReady,
+			}))
+
+			require.Eventually(t, func() bool {
+				attempts, _ := receiver.state()
+				return attempts == testCase.expectedAttempts
+			}, time.Second, time.Millisecond)
+			_, received := receiver.state()
+			if !testCase.expectDelivered {
+				assert.Empty(t, received)
+				return
+			}
+			require.Len(t, received, 1)
+			assert.Equal(t, "1.0", received[0]["specversion"])
+			assert.Equal(t, cloudevents.TypeKymaReady, received[0]["type"])
+			assert.Equal(t, cloudevents.Source, received[0]["source"])
+			assert.Equal(t, "kcp-system/test-kyma", received[0]["subject"])
+			assert.NotEmpty(t, received[0]["id"])
+			assert.Equal(t, map[string]any{"kyma": "test-kyma", "namespace": "kcp-system", "state": "Ready"},
+				received[0]["data"])
+		})
+	}
+}
+
+func TestHTTPSink_DropsEventsIfBufferIsFull(t *testing.T) {
+	t.Parallel()
+	receiver := &endpoint{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	sink := cloudevents.NewHTTPSink(server.URL, 2, 0, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, module := range []string{"first", "second", "dropped"} {
+		sink.Publish(ctx, cloudevents.NewModuleEvent(cloudevents.TypeModuleInstalled, cloudevents.ModuleData{
+			Kyma: "test-kyma", Namespace: "kcp-system", Module: module,
+		}))
+	}
+	go func() { _ = sink.Start(ctx) }()
+
+	require.Eventually(t, func() bool {
+		_, received := receiver.state()
+		return len(received) == 2
+	}, time.Second, time.Millisecond)
+	_, received := receiver.state()
+	assert.Equal(t, "kcp-system/test-kyma/first", received[0]["subject"])
+	assert.Equal(t, "kcp-system/test-kyma/second", received[1]["subject"])
+	require.Never(t, func() bool {
+		attempts, _ := receiver.state()
+		return attempts > 2
+	}, 50*time.Millisecond, time.Millisecond)
+}
+
+type recordingSink struct {
+	types []string
+}
+
+func (s *recordingSink) Publish(_ context.Context, event cloudevents.Event) {
+	s.types = append(s.types, event.Type)
+}
+
+func TestPendingSink_PublishesEventsOnFlush(t *testing.T) {
+	t.Parallel()
+	sink := &recordingSink{}
+	pending := cloudevents.NewPendingSink(sink)
+
+	pending.Publish(context.Background(), cloudevents.NewModuleEvent(cloudevents.TypeModuleInstalled,
+		cloudevents.ModuleData{Kyma: "kyma", Module: "module"}))
+	pending.Publish(context.Background(), cloudevents.NewKymaEvent(cloudevents.TypeKymaReady,
+		cloudevents.KymaData{Kyma: "kyma"}))
+	assert.Empty(t, sink.types)
+
+	pending.Flush(context.Background())
+	assert.Equal(t, []string{cloudevents.TypeModuleInstalled, cloudevents.TypeKymaReady}, sink.types)
+
+	pending.Flush(context.Background())
+	assert.Len(t, sink.types, 2)
+}
+
+func TestPendingSink_DiscardsEvents(t *testing.T) {
+	t.Parallel()
+	sink := &recordingSink{}
+	pending := cloudevents.NewPendingSink(sink)
+
+	pending.Publish(context.Background(), cloudevents.NewModuleEvent(cloudevents.TypeModuleFailed,
+		cloudevents.ModuleData{Kyma: "kyma", Module: "module"}))
+	pending.Discard()
+	pending.Flush(context.Background())
+
+	assert.Empty(t, sink.types)
+}
@@ -0,0 +1,179 @@
+package crdschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/jellydator/ttlcache/v3"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	machineryaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/internal/descriptor/provider"
+	"github.com/kyma-project/lifecycle-manager/internal/manifest"
+	"github.com/kyma-project/lifecycle-manager/pkg/img"
+	"github.com/kyma-project/lifecycle-manager/pkg/ocmextensions"
+	"github.com/kyma-project/lifecycle-manager/pkg/util"
+)
+
+const (
+	crdDecoderBufferSize = 2048
+	// layerPullTimeout bounds the time the webhook waits for the crds layer of a descriptor, well below the
+	// timeout of the webhook, so a slow registry does not block the admission of ModuleTemplates.
+	layerPullTimeout = 3 * time.Second
+	// maxCachedLayers bounds the crds layers whose CRDs are kept in memory.
+	maxCachedLayers = 100
+)
+
+// ClusterLookup resolves the CRD of a module from the cluster the ModuleTemplate is stored in.
+type ClusterLookup struct {
+	reader client.Reader
+	mapper meta.RESTMapper
+}
+
+func NewClusterLookup(reader client.Reader, mapper meta.RESTMapper) *ClusterLookup {
+	return &ClusterLookup{reader: reader, mapper: mapper}
+}
+
+func (l *ClusterLookup) LookupCRD(ctx context.Context, _ *v1beta2.ModuleTemplate,
+	groupKind schema.GroupKind,
+) (*apiextensionsv1.CustomResourceDefinition, error) {
+	mapping, err := l.mapper.RESTMapping(groupKind)
+	if meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("%w: %s is not served by the cluster", ErrCRDNotFound, groupKind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to map %s to its resource: %w", groupKind, err)
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	name := mapping.Resource.GroupResource().String()
+	if err := l.reader.Get(ctx, client.ObjectKey{Name: name}, crd); err != nil {
+		if util.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrCRDNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to get CRD %s: %w", name, err)
+	}
+	return crd, nil
+}
+
+// DescriptorLookup resolves the CRD of a module from the crds layer of the descriptor of the ModuleTemplate.
+// The CRDs of a layer are kept in memory, as the layers are referenced by their digest. The layers are not stored
+// in the layer cache, so the webhook does not evict the layers of the Manifests that are reconciled.
+// A layer that cannot be pulled within the layerPullTimeout is treated like a layer without the CRD.
+type DescriptorLookup struct {
+	clnt               client.Client
+	descriptorProvider *provider.CachedDescriptorProvider
+	crdsByLayer        *ttlcache.Cache[string, []*apiextensionsv1.CustomResourceDefinition]
+}
+
+func NewDescriptorLookup(clnt client.Client, descriptorProvider *provider.CachedDescriptorProvider,
+) *DescriptorLookup {
+	return &DescriptorLookup{
+		clnt:               clnt,
+		descriptorProvider: descriptorProvider,
+		crdsByLayer: ttlcache.New[string, []*apiextensionsv1.CustomResourceDefinition](
+			ttlcache.WithCapacity[string, []*apiextensionsv1.CustomResourceDefinition](maxCachedLayers),
+		),
+	}
+}
+
+func (l *DescriptorLookup) LookupCRD(ctx context.Context, template *v1beta2.ModuleTemplate,
+	groupKind schema.GroupKind,
+) (*apiextensionsv1.CustomResourceDefinition, error) {
+	descriptor, err := l.descriptorProvider.GetDescriptor(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get descriptor from template: %w", err)
+	}
+	layers, err := img.Parse(descriptor.ComponentDescriptor)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse descriptor: %w", err)
+	}
+	for _, layer := range layers {
+		if layer.LayerName != img.CRDsLayer {
+			continue
+		}
+		ociImage, ok := layer.LayerRepresentation.(*img.OCI)
+		if !ok {
+			return nil, fmt.Errorf("layer %s is not an OCI image: %w", layer.LayerName, ErrCRDNotFound)
+		}
+		crds, err := l.crdsOfLayer(ctx, v1beta2.ImageSpec{
+			Repo:               ociImage.Repo,
+			Name:               ociImage.Name,
+			Ref:                ociImage.Ref,
+			Type:               v1beta2.OciRefType,
+			CredSecretSelector: ociImage.CredSecretSelector,
+		})
+		if err != nil {
+			logf.FromContext(ctx).Info("CRD of the module data is not validated", "reason", err.Error())
+			return nil, err
+		}
+		for _, crd := range crds {
+			if crd.Spec.Group == groupKind.Group && crd.Spec.Names.Kind == groupKind.Kind {
+				return crd, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s is not shipped with the descriptor", ErrCRDNotFound, groupKind)
+}
+
+func (l *DescriptorLookup) crdsOfLayer(ctx context.Context,
+	imageSpec v1beta2.ImageSpec,
+) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	key := fmt.Sprintf("%s/%s@%s", imageSpec.Repo, imageSpec.Name, imageSpec.Ref)
+	if item := l.crdsByLayer.Get(key); item != nil {
+		return item.Value(), nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, layerPullTimeout)
+	defer cancel()
+	keyChain, err := l.lookupKeyChain(ctx, imageSpec)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch keyChain for layer %s: %w", ErrCRDNotFound, key, err)
+	}
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	if err := manifest.ReadLayer(ctx, imageSpec, keyChain, func(reader io.Reader) error {
+		crds, err = readCRDs(reader)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("%w: failed to read layer %s: %w", ErrCRDNotFound, key, err)
+	}
+	l.crdsByLayer.Set(key, crds, ttlcache.NoTTL)
+	return crds, nil
+}
+
+func (l *DescriptorLookup) lookupKeyChain(ctx context.Context, imageSpec v1beta2.ImageSpec) (authn.Keychain, error) {
+	var keyChain authn.Keychain = authn.DefaultKeychain
+	if imageSpec.CredSecretSelector != nil {
+		var err error
+		if keyChain, err = ocmextensions.GetAuthnKeychain(ctx, imageSpec.CredSecretSelector, l.clnt); err != nil {
+			return nil, err
+		}
+	}
+	return authn.NewMultiKeychain(google.Keychain, keyChain), nil
+}
+
+// readCRDs decodes all CRDs of the YAML stream. Other documents are skipped.
+func readCRDs(reader io.Reader) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	decoder := machineryaml.NewYAMLOrJSONDecoder(reader, crdDecoderBufferSize)
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	for {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := decoder.Decode(crd); err != nil {
+			if errors.Is(err, io.EOF) {
+				return crds, nil
+			}
+			return nil, fmt.Errorf("failed to decode CRDs: %w", err)
+		}
+		if crd.Kind == "CustomResourceDefinition" {
+			crds = append(crds, crd)
+		}
+	}
+}
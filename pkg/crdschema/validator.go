@@ -0,0 +1,159 @@
+package crdschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+)
+
+var ErrCRDNotFound = errors.New("module CRD not found")
+
+// CRDLookup resolves the CRD of the module of a ModuleTemplate by the group and kind of its data.
+// It returns ErrCRDNotFound if it does not know the CRD.
+type CRDLookup interface {
+	LookupCRD(ctx context.Context, template *v1beta2.ModuleTemplate,
+		groupKind schema.GroupKind) (*apiextensionsv1.CustomResourceDefinition, error)
+}
+
+// Validator validates the data and the customStateChecks of ModuleTemplates against the schema of the module CRD.
+// The CRD is resolved with the first CRDLookup that knows it. Modules without a known CRD are not validated.
+type Validator struct {
+	lookups []CRDLookup
+}
+
+func NewValidator(lookups ...CRDLookup) *Validator {
+	return &Validator{lookups: lookups}
+}
+
+var _ v1beta2.ModuleTemplateSchemaValidator = &Validator{}
+
+// ValidateSchema rejects data that does not match the schema of the served CRD version, and JSONPaths of
+// customStateChecks that cannot point to a string field in the schema, as such checks would never match.
+func (v *Validator) ValidateSchema(ctx context.Context, template *v1beta2.ModuleTemplate) field.ErrorList {
+	if template.Spec.Data == nil {
+		return nil
+	}
+	dataPath := field.NewPath("spec").Child("data")
+	gvk := template.Spec.Data.GroupVersionKind()
+	crd, err := v.lookupCRD(ctx, template, gvk.GroupKind())
+	if errors.Is(err, ErrCRDNotFound) {
+		return nil
+	}
+	if err != nil {
+		return field.ErrorList{field.InternalError(dataPath, err)}
+	}
+
+	version, served := servedVersion(crd, gvk.Version)
+	if version == nil {
+		return field.ErrorList{field.NotSupported(dataPath.Child("apiVersion"), gvk.GroupVersion().String(), served)}
+	}
+	if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+		return nil
+	}
+	openAPISchema := version.Schema.OpenAPIV3Schema
+
+	errs, err := validateData(dataPath, template.Spec.Data.UnstructuredContent(), openAPISchema)
+	if err != nil {
+		return field.ErrorList{field.InternalError(dataPath, err)}
+	}
+	for i, stateCheck := range template.Spec.CustomStateCheck {
+		if stateCheck == nil || stateCheck.JSONPath == "" {
+			continue
+		}
+		if !stringFieldExists(openAPISchema, strings.Split(stateCheck.JSONPath, ".")) {
+			errs = append(errs, field.Invalid(
+				field.NewPath("spec").Child("customStateCheck").Index(i).Child("jsonPath"), stateCheck.JSONPath,
+				fmt.Sprintf("no string field exists at this path in version %s of CRD %s", version.Name, crd.Name)))
+		}
+	}
+	return errs
+}
+
+func (v *Validator) lookupCRD(ctx context.Context, template *v1beta2.ModuleTemplate,
+	groupKind schema.GroupKind,
+) (*apiextensionsv1.CustomResourceDefinition, error) {
+	for _, lookup := range v.lookups {
+		crd, err := lookup.LookupCRD(ctx, template, groupKind)
+		if errors.Is(err, ErrCRDNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return crd, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrCRDNotFound, groupKind)
+}
+
+// servedVersion returns the served version of the CRD with the given name, together with all served versions.
+func servedVersion(crd *apiextensionsv1.CustomResourceDefinition,
+	name string,
+) (*apiextensionsv1.CustomResourceDefinitionVersion, []string) {
+	var found *apiextensionsv1.CustomResourceDefinitionVersion
+	served := make([]string, 0, len(crd.Spec.Versions))
+	for i := range crd.Spec.Versions {
+		version := &crd.Spec.Versions[i]
+		if !version.Served {
+			continue
+		}
+		served = append(served, crd.Spec.Group+"/"+version.Name)
+		if version.Name == name {
+			found = version
+		}
+	}
+	return found, served
+}
+
+func validateData(path *field.Path, data map[string]any,
+	openAPISchema *apiextensionsv1.JSONSchemaProps,
+) (field.ErrorList, error) {
+	internalSchema := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(
+		openAPISchema, internalSchema, nil); err != nil {
+		return nil, fmt.Errorf("failed to convert CRD schema: %w", err)
+	}
+	schemaValidator, _, err := validation.NewSchemaValidator(internalSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CRD schema validator: %w", err)
+	}
+	return validation.ValidateCustomResource(path, data, schemaValidator), nil
+}
+
+// stringFieldExists reports whether objects of the structural schema can have a string field at the path.
+// Fields that are not specified in the schema are pruned by the API server, unless unknown fields are preserved.
+// The apiVersion, kind and metadata of the object are not part of the schema.
+func stringFieldExists(openAPISchema *apiextensionsv1.JSONSchemaProps, path []string) bool {
+	switch path[0] {
+	case "apiVersion", "kind":
+		return len(path) == 1
+	case "metadata":
+		return true
+	}
+	current := openAPISchema
+	for _, name := range path {
+		if property, found := current.Properties[name]; found {
+			current = &property
+			continue
+		}
+		switch {
+		case current.AdditionalProperties != nil && current.AdditionalProperties.Schema != nil:
+			current = current.AdditionalProperties.Schema
+		case current.AdditionalProperties != nil && current.AdditionalProperties.Allows,
+			current.XPreserveUnknownFields != nil && *current.XPreserveUnknownFields:
+			return true
+		default:
+			return false
+		}
+	}
+	return current.Type == "string" || current.XIntOrString ||
+		(current.Type == "" && current.XPreserveUnknownFields != nil && *current.XPreserveUnknownFields)
+}
@@ -0,0 +1,152 @@
+package crdschema_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/crdschema"
+	"github.com/kyma-project/lifecycle-manager/pkg/testutils/builder"
+)
+
+var errLookupFailed = errors.New("lookup failed")
+
+type staticLookup struct {
+	crd *apiextensionsv1.CustomResourceDefinition
+	err error
+}
+
+func (l staticLookup) LookupCRD(_ context.Context, _ *v1beta2.ModuleTemplate,
+	groupKind schema.GroupKind,
+) (*apiextensionsv1.CustomResourceDefinition, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	if l.crd == nil || l.crd.Spec.Group != groupKind.Group || l.crd.Spec.Names.Kind != groupKind.Kind {
+		return nil, crdschema.ErrCRDNotFound
+	}
+	return l.crd, nil
+}
+
+func sampleCRD() *apiextensionsv1.CustomResourceDefinition {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	crd.Name = "samples." + v1beta2.GroupVersion.Group
+	crd.Spec.Group = v1beta2.GroupVersion.Group
+	crd.Spec.Names.Kind = "Sample"
+	crd.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{
+		{Name: "v1alpha0", Served: false},
+		{
+			Name:   "v1alpha1",
+			Served: true,
+			Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"spec": {
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"replicas": {Type: "integer"},
+							"labels": {
+								Type:                 "object",
+								AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}},
+							},
+						},
+					},
+					"status": {
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"state":      {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"Ready"`)}}},
+							"count":      {Type: "integer"},
+							"conditions": {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "object"}}},
+							"details":    {Type: "object", XPreserveUnknownFields: ptr.To(true)},
+						},
+					},
+				},
+			}},
+		},
+	}
+	return crd
+}
+
+func TestValidator_ValidateSchema(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		lookup         staticLookup
+		data           *unstructured.Unstructured
+		jsonPaths      []string
+		expectedFields []string
+	}{
+		{
+			"module without CRD",
+			staticLookup{},
+			builder.NewModuleCRBuilder().WithSpec("replicas", "three").Build(),
+			[]string{"status.missing"},
+			nil,
+		},
+		{
+			"valid data and JSONPaths",
+			staticLookup{crd: sampleCRD()},
+			builder.NewModuleCRBuilder().WithName("default").Build(),
+			[]string{"status.state", "status.details.phase", "spec.labels.health", "metadata.name"},
+			nil,
+		},
+		{
+			"data not matching the schema",
+			staticLookup{crd: sampleCRD()},
+			builder.NewModuleCRBuilder().WithSpec("replicas", "three").Build(),
+			nil,
+			[]string{"spec.data.spec.replicas"},
+		},
+		{
+			"version not served",
+			staticLookup{crd: sampleCRD()},
+			builder.NewModuleCRBuilder().WithGroupVersionKind(v1beta2.GroupVersion.Group, "v1alpha0", "Sample").Build(),
+			nil,
+			[]string{"spec.data.apiVersion"},
+		},
+		{
+			"JSONPaths not in the schema",
+			staticLookup{crd: sampleCRD()},
+			builder.NewModuleCRBuilder().Build(),
+			[]string{"status.missing", "status.count", "status.conditions.type", "status.state.value"},
+			[]string{
+				"spec.customStateCheck[0].jsonPath", "spec.customStateCheck[1].jsonPath",
+				"spec.customStateCheck[2].jsonPath", "spec.customStateCheck[3].jsonPath",
+			},
+		},
+		{
+			"failed lookup",
+			staticLookup{err: errLookupFailed},
+			builder.NewModuleCRBuilder().Build(),
+			nil,
+			[]string{"spec.data"},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			template := builder.NewModuleTemplateBuilder().WithModuleCR(testCase.data).Build()
+			for _, jsonPath := range testCase.jsonPaths {
+				template.Spec.CustomStateCheck = append(template.Spec.CustomStateCheck,
+					&v1beta2.CustomStateCheck{JSONPath: jsonPath, Value: "Ready", MappedState: shared.StateReady})
+			}
+
+			errs := crdschema.NewValidator(testCase.lookup).ValidateSchema(context.Background(), template)
+
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, testCase.expectedFields, fields)
+		})
+	}
+}
@@ -81,6 +81,13 @@ func parseLayersByName(repo *genericocireg.RepositorySpec, descriptor *compdesc.
 			if err != nil {
 				return nil, fmt.Errorf("building the digest url: %w", err)
 			}
+			// helm charts and kustomizations are shipped as archives and need to be rendered before installation
+			switch resource.Name {
+			case v1beta2.HelmChartLayerName:
+				layerRef.Type = string(v1beta2.HelmChartType)
+			case v1beta2.KustomizeLayerName:
+				layerRef.Type = string(v1beta2.KustomizeType)
+			}
 			layerRepresentation = layerRef
 		// this resource type is not relevant for module rendering but for security scanning only
 		case ociartifact.Type:
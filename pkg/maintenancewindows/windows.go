@@ -0,0 +1,106 @@
+package maintenancewindows
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+)
+
+const (
+	timeOfDayLayout = "15:04"
+	// lookAheadDays covers a full week plus the previous day, as a window opened yesterday can still be open.
+	lookAheadDays = 8
+)
+
+var (
+	ErrInvalidWindow = errors.New("invalid maintenance window")
+	ErrNoWindow      = errors.New("no maintenance window opens in the next week")
+)
+
+// IsOpen reports whether any of the windows is open at the given time.
+func IsOpen(windows []v1beta2.MaintenanceWindow, now time.Time) (bool, error) {
+	for _, window := range windows {
+		ranges, err := occurrences(window, now)
+		if err != nil {
+			return false, err
+		}
+		for _, occurrence := range ranges {
+			if !now.Before(occurrence.start) && now.Before(occurrence.end) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// NextOpening returns the earliest time after now at which any of the windows opens.
+func NextOpening(windows []v1beta2.MaintenanceWindow, now time.Time) (time.Time, error) {
+	var next time.Time
+	for _, window := range windows {
+		ranges, err := occurrences(window, now)
+		if err != nil {
+			return time.Time{}, err
+		}
+		for _, occurrence := range ranges {
+			if occurrence.start.After(now) && (next.IsZero() || occurrence.start.Before(next)) {
+				next = occurrence.start
+			}
+		}
+	}
+	if next.IsZero() {
+		return time.Time{}, ErrNoWindow
+	}
+	return next, nil
+}
+
+type timeRange struct {
+	start, end time.Time
+}
+
+// occurrences returns the time ranges of the window from the day before now until a week after now.
+func occurrences(window v1beta2.MaintenanceWindow, now time.Time) ([]timeRange, error) {
+	location, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: time zone %q: %w", ErrInvalidWindow, window.TimeZone, err)
+	}
+	start, err := time.Parse(timeOfDayLayout, window.Start)
+	if err != nil {
+		return nil, fmt.Errorf("%w: start %q: %w", ErrInvalidWindow, window.Start, err)
+	}
+	end, err := time.Parse(timeOfDayLayout, window.End)
+	if err != nil {
+		return nil, fmt.Errorf("%w: end %q: %w", ErrInvalidWindow, window.End, err)
+	}
+
+	localNow := now.In(location)
+	ranges := make([]timeRange, 0, lookAheadDays)
+	for offset := -1; offset < lookAheadDays; offset++ {
+		day := time.Date(localNow.Year(), localNow.Month(), localNow.Day()+offset, 0, 0, 0, 0, location)
+		if !matchesDay(window.Days, day.Weekday()) {
+			continue
+		}
+		occurrence := timeRange{
+			start: time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, location),
+			end:   time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, location),
+		}
+		if !occurrence.end.After(occurrence.start) {
+			occurrence.end = occurrence.end.AddDate(0, 0, 1)
+		}
+		ranges = append(ranges, occurrence)
+	}
+	return ranges, nil
+}
+
+func matchesDay(days []v1beta2.Weekday, weekday time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, day := range days {
+		if string(day) == weekday.String() {
+			return true
+		}
+	}
+	return false
+}
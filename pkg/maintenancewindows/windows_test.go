@@ -0,0 +1,85 @@
+package maintenancewindows_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/maintenancewindows"
+)
+
+// 2024-03-09 is a Saturday.
+func utc(day, hour, minute int) time.Time {
+	return time.Date(2024, time.March, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestIsOpen(t *testing.T) {
+	t.Parallel()
+	weekend := v1beta2.MaintenanceWindow{
+		Days:  []v1beta2.Weekday{"Saturday", "Sunday"},
+		Start: "02:00",
+		End:   "06:00",
+	}
+	overnight := v1beta2.MaintenanceWindow{Start: "22:00", End: "02:00"}
+	berlin := v1beta2.MaintenanceWindow{Start: "02:00", End: "03:00", TimeZone: "Europe/Berlin"}
+	tests := []struct {
+		name     string
+		windows  []v1beta2.MaintenanceWindow
+		now      time.Time
+		expected bool
+	}{
+		{"inside weekly window", []v1beta2.MaintenanceWindow{weekend}, utc(9, 3, 0), true},
+		{"at start of window", []v1beta2.MaintenanceWindow{weekend}, utc(9, 2, 0), true},
+		{"at end of window", []v1beta2.MaintenanceWindow{weekend}, utc(9, 6, 0), false},
+		{"on other weekday", []v1beta2.MaintenanceWindow{weekend}, utc(8, 3, 0), false},
+		{"overnight window before midnight", []v1beta2.MaintenanceWindow{overnight}, utc(8, 23, 0), true},
+		{"overnight window after midnight", []v1beta2.MaintenanceWindow{overnight}, utc(9, 1, 0), true},
+		{"outside overnight window", []v1beta2.MaintenanceWindow{overnight}, utc(9, 12, 0), false},
+		{"in time zone of window", []v1beta2.MaintenanceWindow{berlin}, utc(9, 1, 30), true},
+		{"outside time zone of window", []v1beta2.MaintenanceWindow{berlin}, utc(9, 2, 30), false},
+		{"any of several windows", []v1beta2.MaintenanceWindow{weekend, overnight}, utc(8, 23, 0), true},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			open, err := maintenancewindows.IsOpen(testCase.windows, testCase.now)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, open)
+		})
+	}
+}
+
+func TestNextOpening(t *testing.T) {
+	t.Parallel()
+	windows := []v1beta2.MaintenanceWindow{
+		{Days: []v1beta2.Weekday{"Sunday"}, Start: "02:00", End: "06:00"},
+		{Days: []v1beta2.Weekday{"Wednesday"}, Start: "01:00", End: "02:00", TimeZone: "Europe/Berlin"},
+	}
+
+	next, err := maintenancewindows.NextOpening(windows, utc(9, 12, 0))
+	require.NoError(t, err)
+	assert.Equal(t, utc(10, 2, 0), next.UTC())
+
+	next, err = maintenancewindows.NextOpening(windows, utc(10, 3, 0))
+	require.NoError(t, err)
+	assert.Equal(t, utc(13, 0, 0), next.UTC())
+}
+
+func TestInvalidWindow(t *testing.T) {
+	t.Parallel()
+	tests := []v1beta2.MaintenanceWindow{
+		{Start: "02:00", End: "03:00", TimeZone: "Mars/Olympus"},
+		{Start: "2am", End: "03:00"},
+		{Start: "02:00", End: "25:00"},
+	}
+	for _, window := range tests {
+		_, err := maintenancewindows.IsOpen([]v1beta2.MaintenanceWindow{window}, utc(9, 2, 0))
+		require.ErrorIs(t, err, maintenancewindows.ErrInvalidWindow)
+		_, err = maintenancewindows.NextOpening([]v1beta2.MaintenanceWindow{window}, utc(9, 2, 0))
+		require.ErrorIs(t, err, maintenancewindows.ErrInvalidWindow)
+	}
+}
@@ -22,6 +22,10 @@ type (
 		Template   *templatelookup.ModuleTemplateInfo
 		*v1beta2.Manifest
 		Enabled bool
+		// DeferredUpgrade is set if the Manifest is not upgraded until the next maintenance window of the Kyma.
+		DeferredUpgrade *v1beta2.DeferredUpgrade
+		// FailedUpgrade is set if an upgrade of the Manifest was rolled back to the last known good version.
+		FailedUpgrade *v1beta2.FailedUpgrade
 	}
 )
 
@@ -42,6 +46,7 @@ func (m *Module) ApplyLabelsAndAnnotations(
 		lbls = make(map[string]string)
 	}
 	lbls[shared.KymaName] = kyma.Name
+	lbls[shared.ModuleName] = m.ModuleName
 
 	templateLabels := m.Template.GetLabels()
 	if templateLabels != nil {
@@ -61,9 +66,26 @@ func (m *Module) ApplyLabelsAndAnnotations(
 		anns = make(map[string]string)
 	}
 	anns[shared.FQDN] = m.FQDN
+	if dependencies := m.DependencyNames(); len(dependencies) > 0 {
+		anns[shared.DependenciesAnnotation] = strings.Join(dependencies, ",")
+	} else {
+		delete(anns, shared.DependenciesAnnotation)
+	}
 	m.SetAnnotations(anns)
 }
 
+// DependencyNames returns the names of the modules declared as dependencies in the template of the module.
+func (m *Module) DependencyNames() []string {
+	if m.Template == nil || m.Template.ModuleTemplate == nil {
+		return nil
+	}
+	names := make([]string, 0, len(m.Template.Spec.Dependencies))
+	for _, dependency := range m.Template.Spec.Dependencies {
+		names = append(names, dependency.Name)
+	}
+	return names
+}
+
 func (m *Module) IsRemoteModuleTemplate(kyma *v1beta2.Kyma) bool {
 	for _, module := range kyma.Spec.Modules {
 		if module.Name == m.ModuleName {
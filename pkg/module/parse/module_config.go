@@ -0,0 +1,59 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+
+	machineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+)
+
+var ErrModuleConfigParsing = errors.New("module config could not be parsed")
+
+// MergeModuleConfig deep-merges the module config of the Kyma spec over the module CR of the Manifest and records
+// the config in the ModuleConfigAnnotation. The kind, name and namespace of the module CR cannot be changed.
+func MergeModuleConfig(manifest *v1beta2.Manifest, config *machineryruntime.RawExtension) error {
+	if manifest.Spec.Resource == nil || config == nil || len(config.Raw) == 0 {
+		return nil
+	}
+	values := map[string]any{}
+	if err := json.Unmarshal(config.Raw, &values); err != nil {
+		return fmt.Errorf("%w: %w", ErrModuleConfigParsing, err)
+	}
+	resource := manifest.Spec.Resource
+	gvk, name, namespace := resource.GroupVersionKind(), resource.GetName(), resource.GetNamespace()
+	resource.Object = mergeValues(resource.Object, values)
+	resource.SetGroupVersionKind(gvk)
+	resource.SetName(name)
+	resource.SetNamespace(namespace)
+
+	annotations := manifest.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[shared.ModuleConfigAnnotation] = string(config.Raw)
+	manifest.SetAnnotations(annotations)
+	return nil
+}
+
+// mergeValues merges the overrides into the defaults. Maps are merged recursively,
+// all other values of the overrides replace the defaults.
+func mergeValues(defaults, overrides map[string]any) map[string]any {
+	merged := make(map[string]any, len(defaults)+len(overrides))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, override := range overrides {
+		overrideMap, overrideIsMap := override.(map[string]any)
+		defaultMap, defaultIsMap := merged[key].(map[string]any)
+		if overrideIsMap && defaultIsMap {
+			merged[key] = mergeValues(defaultMap, overrideMap)
+			continue
+		}
+		merged[key] = machineryruntime.DeepCopyJSONValue(override)
+	}
+	return merged
+}
@@ -0,0 +1,73 @@
+package parse_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	machineryruntime "k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/module/parse"
+)
+
+func newManifestWithResource() *v1beta2.Manifest {
+	resource := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "operator.kyma-project.io/v1alpha1",
+		"kind":       "Sample",
+		"metadata":   map[string]any{"name": "sample", "namespace": "kyma-system"},
+		"spec": map[string]any{
+			"replicas": int64(1),
+			"features": map[string]any{"tracing": false, "metrics": true},
+			"zones":    []any{"a", "b"},
+		},
+	}}
+	return &v1beta2.Manifest{Spec: v1beta2.ManifestSpec{Resource: resource}}
+}
+
+func TestMergeModuleConfig(t *testing.T) {
+	t.Parallel()
+	manifest := newManifestWithResource()
+	config := &machineryruntime.RawExtension{Raw: []byte(
+		`{"kind":"Other","metadata":{"name":"other","labels":{"team":"a"}},` +
+			`"spec":{"replicas":3,"features":{"tracing":true},"zones":["c"]}}`,
+	)}
+
+	require.NoError(t, parse.MergeModuleConfig(manifest, config))
+
+	resource := manifest.Spec.Resource
+	assert.Equal(t, "Sample", resource.GetKind())
+	assert.Equal(t, "sample", resource.GetName())
+	assert.Equal(t, "kyma-system", resource.GetNamespace())
+	assert.Equal(t, map[string]string{"team": "a"}, resource.GetLabels())
+	assert.Equal(t, map[string]any{
+		"replicas": int64(3),
+		"features": map[string]any{"tracing": true, "metrics": true},
+		"zones":    []any{"c"},
+	}, resource.Object["spec"])
+	assert.Equal(t, string(config.Raw), manifest.GetAnnotations()[shared.ModuleConfigAnnotation])
+}
+
+func TestMergeModuleConfig_WithoutConfigOrResource(t *testing.T) {
+	t.Parallel()
+	manifest := newManifestWithResource()
+	expected := manifest.Spec.Resource.DeepCopy()
+	require.NoError(t, parse.MergeModuleConfig(manifest, nil))
+	assert.Equal(t, expected, manifest.Spec.Resource)
+	assert.Empty(t, manifest.GetAnnotations())
+
+	withoutResource := &v1beta2.Manifest{}
+	require.NoError(t, parse.MergeModuleConfig(withoutResource,
+		&machineryruntime.RawExtension{Raw: []byte(`{"spec":{"replicas":3}}`)}))
+	assert.Nil(t, withoutResource.Spec.Resource)
+	assert.Empty(t, withoutResource.GetAnnotations())
+}
+
+func TestMergeModuleConfig_InvalidConfig(t *testing.T) {
+	t.Parallel()
+	err := parse.MergeModuleConfig(newManifestWithResource(),
+		&machineryruntime.RawExtension{Raw: []byte(`["not", "an", "object"]`)})
+	require.ErrorIs(t, err, parse.ErrModuleConfigParsing)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/kyma-project/lifecycle-manager/pkg/img"
 	"github.com/kyma-project/lifecycle-manager/pkg/log"
 	"github.com/kyma-project/lifecycle-manager/pkg/module/common"
+	"github.com/kyma-project/lifecycle-manager/pkg/signature"
 	"github.com/kyma-project/lifecycle-manager/pkg/templatelookup"
 )
 
@@ -26,22 +27,28 @@ type Parser struct {
 	descriptorProvider  *provider.CachedDescriptorProvider
 	inKCPMode           bool
 	remoteSyncNamespace string
+	signatureName       string
 }
 
+// NewParser creates a Parser. If signatureName is not empty, the descriptors of all ModuleTemplates
+// are verified against the signature with that name, unless the template is labeled with a different signature.
 func NewParser(clnt client.Client,
 	descriptorProvider *provider.CachedDescriptorProvider,
 	inKCPMode bool,
 	remoteSyncNamespace string,
+	signatureName string,
 ) *Parser {
 	return &Parser{
 		Client:              clnt,
 		descriptorProvider:  descriptorProvider,
 		inKCPMode:           inKCPMode,
 		remoteSyncNamespace: remoteSyncNamespace,
+		signatureName:       signatureName,
 	}
 }
 
-func (p *Parser) GenerateModulesFromTemplates(kyma *v1beta2.Kyma, templates templatelookup.ModuleTemplatesByModuleName,
+func (p *Parser) GenerateModulesFromTemplates(ctx context.Context,
+	kyma *v1beta2.Kyma, templates templatelookup.ModuleTemplatesByModuleName,
 ) common.Modules {
 	// First, we fetch the module spec from the template and use it to resolve it into an arbitrary object
 	// (since we do not know which module we are dealing with)
@@ -49,7 +56,7 @@ func (p *Parser) GenerateModulesFromTemplates(kyma *v1beta2.Kyma, templates temp
 
 	for _, module := range kyma.GetAvailableModules() {
 		template := templates[module.Name]
-		modules = p.appendModuleWithInformation(module, kyma, template, modules)
+		modules = p.appendModuleWithInformation(ctx, module, kyma, template, modules)
 	}
 	return modules
 }
@@ -69,7 +76,7 @@ func (p *Parser) GenerateMandatoryModulesFromTemplates(ctx context.Context,
 			moduleName = template.Name
 		}
 
-		modules = p.appendModuleWithInformation(v1beta2.AvailableModule{
+		modules = p.appendModuleWithInformation(ctx, v1beta2.AvailableModule{
 			Module: v1beta2.Module{
 				Name:                 moduleName,
 				CustomResourcePolicy: v1beta2.CustomResourcePolicyCreateAndDelete,
@@ -81,7 +88,7 @@ func (p *Parser) GenerateMandatoryModulesFromTemplates(ctx context.Context,
 	return modules
 }
 
-func (p *Parser) appendModuleWithInformation(module v1beta2.AvailableModule, kyma *v1beta2.Kyma,
+func (p *Parser) appendModuleWithInformation(ctx context.Context, module v1beta2.AvailableModule, kyma *v1beta2.Kyma,
 	template *templatelookup.ModuleTemplateInfo, modules common.Modules,
 ) common.Modules {
 	if template.Err != nil && !errors.Is(template.Err, templatelookup.ErrTemplateNotAllowed) {
@@ -106,7 +113,7 @@ func (p *Parser) appendModuleWithInformation(module v1beta2.AvailableModule, kym
 	name := common.CreateModuleName(fqdn, kyma.Name, module.Name)
 	setNameAndNamespaceIfEmpty(template, name, p.remoteSyncNamespace)
 	var manifest *v1beta2.Manifest
-	if manifest, err = p.newManifestFromTemplate(module.Module,
+	if manifest, err = p.newManifestFromTemplate(ctx, module.Module,
 		template.ModuleTemplate); err != nil {
 		template.Err = err
 		modules = append(modules, &common.Module{
@@ -145,6 +152,7 @@ func setNameAndNamespaceIfEmpty(template *templatelookup.ModuleTemplateInfo, nam
 }
 
 func (p *Parser) newManifestFromTemplate(
+	ctx context.Context,
 	module v1beta2.Module,
 	template *v1beta2.ModuleTemplate,
 ) (*v1beta2.Manifest, error) {
@@ -154,12 +162,20 @@ func (p *Parser) newManifestFromTemplate(
 	switch module.CustomResourcePolicy {
 	case v1beta2.CustomResourcePolicyIgnore:
 		manifest.Spec.Resource = nil
+	case v1beta2.CustomResourcePolicyReconcile:
+		manifest.SetAnnotations(map[string]string{
+			shared.CustomResourcePolicyAnnotation: v1beta2.CustomResourcePolicyReconcile,
+		})
+		fallthrough
 	case v1beta2.CustomResourcePolicyCreateAndDelete:
 		fallthrough
 	default:
 		if template.Spec.Data != nil {
 			manifest.Spec.Resource = template.Spec.Data.DeepCopy()
 		}
+		if err := MergeModuleConfig(manifest, module.Config); err != nil {
+			return nil, err
+		}
 	}
 
 	var layers img.Layers
@@ -168,6 +184,9 @@ func (p *Parser) newManifestFromTemplate(
 	if err != nil {
 		return nil, fmt.Errorf("failed to get descriptor from template: %w", err)
 	}
+	if err := p.verifySignature(ctx, manifest, template, descriptor); err != nil {
+		return nil, err
+	}
 
 	if layers, err = img.Parse(descriptor.ComponentDescriptor); err != nil {
 		return nil, fmt.Errorf("could not parse descriptor: %w", err)
@@ -180,10 +199,33 @@ func (p *Parser) newManifestFromTemplate(
 	if err := appendOptionalCustomStateCheck(manifest, template.Spec.CustomStateCheck); err != nil {
 		return nil, fmt.Errorf("could not translate custom state check: %w", err)
 	}
+	appendOptionalTimeouts(manifest, template.Spec.Timeouts)
 	manifest.Spec.Version = descriptor.Version
 	return manifest, nil
 }
 
+// verifySignature verifies the descriptor signature of the template if required and labels the manifest
+// with the signature name, so the layers of the manifest are verified with the same public keys.
+func (p *Parser) verifySignature(ctx context.Context,
+	manifest *v1beta2.Manifest,
+	template *v1beta2.ModuleTemplate,
+	descriptor *v1beta2.Descriptor,
+) error {
+	signatureName := signature.NameFor(template, p.signatureName)
+	if signatureName == "" {
+		return nil
+	}
+	keys, err := signature.PublicKeys(ctx, p, template.GetNamespace(), signatureName)
+	if err != nil {
+		return fmt.Errorf("%w: %w", signature.ErrVerificationFailed, err)
+	}
+	if err := signature.VerifyDescriptor(descriptor.ComponentDescriptor, signatureName, keys); err != nil {
+		return err
+	}
+	manifest.SetLabels(map[string]string{shared.Signature: signatureName})
+	return nil
+}
+
 func appendOptionalCustomStateCheck(manifest *v1beta2.Manifest, stateCheck []*v1beta2.CustomStateCheck) error {
 	if manifest.Spec.Resource == nil || stateCheck == nil {
 		return nil
@@ -199,6 +241,22 @@ func appendOptionalCustomStateCheck(manifest *v1beta2.Manifest, stateCheck []*v1
 	return nil
 }
 
+// appendOptionalTimeouts records the timeouts of the module in the annotations of the manifest.
+func appendOptionalTimeouts(manifest *v1beta2.Manifest, timeouts *v1beta2.ModuleTimeouts) {
+	if timeouts == nil || (timeouts.Install == nil && timeouts.Delete == nil) {
+		return
+	}
+	if manifest.Annotations == nil {
+		manifest.Annotations = make(map[string]string)
+	}
+	if timeouts.Install != nil {
+		manifest.Annotations[shared.InstallTimeoutAnnotation] = timeouts.Install.Duration.String()
+	}
+	if timeouts.Delete != nil {
+		manifest.Annotations[shared.DeleteTimeoutAnnotation] = timeouts.Delete.Duration.String()
+	}
+}
+
 func translateLayersAndMergeIntoManifest(
 	manifest *v1beta2.Manifest, layers img.Layers,
 ) error {
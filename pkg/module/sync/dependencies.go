@@ -0,0 +1,189 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/module/common"
+)
+
+var (
+	ErrDependencyMissing           = errors.New("module dependency is not enabled")
+	ErrDependencyVersionMismatch   = errors.New("module dependency does not satisfy the version constraint")
+	ErrDependencyCycle             = errors.New("module dependencies contain a cycle")
+	ErrDependenciesNotReady        = errors.New("waiting for module dependencies to become Ready")
+	ErrInvalidDependencyConstraint = errors.New("invalid version constraint of module dependency")
+)
+
+// ResolveDependencies validates the dependencies declared in the templates of all enabled modules.
+// Modules whose dependencies are not enabled in the same Kyma, do not satisfy the version constraint,
+// or that depend on each other in a cycle get an error in their template, so they are not installed
+// and their status explains which dependency is missing. Modules depending on such a module fail as well.
+func ResolveDependencies(modules common.Modules) {
+	for {
+		if !markMissingDependencies(modules) {
+			break
+		}
+	}
+	_, unresolved := dependencyLevels(modules)
+	for _, module := range unresolved {
+		module.Template.Err = fmt.Errorf("%w: module %s depends on %s", ErrDependencyCycle,
+			module.ModuleName, strings.Join(module.DependencyNames(), ", "))
+	}
+}
+
+// markMissingDependencies marks the modules with a missing or mismatching dependency
+// and reports whether any module was marked.
+func markMissingDependencies(modules common.Modules) bool {
+	installable := installableModules(modules)
+	marked := false
+	for _, module := range installable {
+		if err := checkDependencies(module, installable); err != nil {
+			module.Template.Err = err
+			marked = true
+		}
+	}
+	return marked
+}
+
+func checkDependencies(module *common.Module, installable map[string]*common.Module) error {
+	for _, dependency := range module.Template.Spec.Dependencies {
+		dependencyModule, found := installable[dependency.Name]
+		if !found {
+			return fmt.Errorf("%w: module %s requires module %s, which has to be enabled and installable "+
+				"in the same Kyma", ErrDependencyMissing, module.ModuleName, dependency.Name)
+		}
+		if dependency.Version == "" {
+			continue
+		}
+		constraint, err := semver.NewConstraint(dependency.Version)
+		if err != nil {
+			return fmt.Errorf("%w %q of module %s: %w", ErrInvalidDependencyConstraint, dependency.Version,
+				module.ModuleName, err)
+		}
+		version, err := semver.NewVersion(dependencyModule.Manifest.Spec.Version)
+		if err != nil || !constraint.Check(version) {
+			return fmt.Errorf("%w: module %s requires module %s in version %s, but version %s is enabled",
+				ErrDependencyVersionMismatch, module.ModuleName, dependency.Name, dependency.Version,
+				dependencyModule.Manifest.Spec.Version)
+		}
+	}
+	return nil
+}
+
+// installableModules returns the enabled modules without template errors by their name.
+func installableModules(modules common.Modules) map[string]*common.Module {
+	installable := make(map[string]*common.Module, len(modules))
+	for _, module := range modules {
+		if module.Enabled && module.Template.Err == nil {
+			installable[module.ModuleName] = module
+		}
+	}
+	return installable
+}
+
+// dependencyLevels groups the modules so that every installable module is in a later level than all
+// of its installable dependencies. Modules that are not installable are part of the first level.
+// Installable modules that cannot be placed because of a dependency cycle are returned as unresolved.
+func dependencyLevels(modules common.Modules) ([]common.Modules, common.Modules) {
+	installable := installableModules(modules)
+	placed := make(map[string]bool, len(modules))
+	var levels []common.Modules
+	remaining := modules
+	for len(remaining) > 0 {
+		var level, next common.Modules
+		for _, module := range remaining {
+			if _, found := installable[module.ModuleName]; !found ||
+				dependenciesPlaced(module, installable, placed) {
+				level = append(level, module)
+			} else {
+				next = append(next, module)
+			}
+		}
+		if len(level) == 0 {
+			return levels, remaining
+		}
+		for _, module := range level {
+			placed[module.ModuleName] = true
+		}
+		levels = append(levels, level)
+		remaining = next
+	}
+	return levels, nil
+}
+
+func dependenciesPlaced(module *common.Module, installable map[string]*common.Module, placed map[string]bool) bool {
+	for _, name := range module.DependencyNames() {
+		if _, found := installable[name]; found && !placed[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// notReadyDependencies returns the names of the dependencies of the module that are not Ready yet.
+func notReadyDependencies(module *common.Module, ready map[string]bool) []string {
+	var notReady []string
+	for _, name := range module.DependencyNames() {
+		if !ready[name] {
+			notReady = append(notReady, name)
+		}
+	}
+	return notReady
+}
+
+// DependentModules maps the names of modules to the names of the modules depending on them,
+// based on the dependencies annotation of the given Manifests. A module must not be deleted
+// as long as another Manifest depends on it.
+func DependentModules(manifests []v1beta2.Manifest) map[string][]string {
+	dependents := make(map[string][]string)
+	for i := range manifests {
+		manifest := &manifests[i]
+		dependencies := manifest.GetAnnotations()[shared.DependenciesAnnotation]
+		if dependencies == "" {
+			continue
+		}
+		name := moduleNameOf(manifest)
+		for _, dependency := range strings.Split(dependencies, ",") {
+			if dependency != name {
+				dependents[dependency] = append(dependents[dependency], name)
+			}
+		}
+	}
+	for dependency := range dependents {
+		sort.Strings(dependents[dependency])
+	}
+	return dependents
+}
+
+// DeletableManifests returns the Manifests that no other of the given Manifests depends on,
+// so modules are deleted in reverse dependency order. If every Manifest is depended on, e.g. because
+// of a dependency cycle, all Manifests are returned so the deletion cannot get stuck.
+func DeletableManifests(manifests []v1beta2.Manifest) []v1beta2.Manifest {
+	dependents := DependentModules(manifests)
+	deletable := make([]v1beta2.Manifest, 0, len(manifests))
+	for i := range manifests {
+		if len(dependents[moduleNameOf(&manifests[i])]) == 0 {
+			deletable = append(deletable, manifests[i])
+		}
+	}
+	if len(deletable) == 0 {
+		return manifests
+	}
+	return deletable
+}
+
+// moduleNameOf returns the module name of the Manifest. Manifests created without the module name label
+// are identified by their name.
+func moduleNameOf(manifest *v1beta2.Manifest) string {
+	if name := manifest.GetLabels()[shared.ModuleName]; name != "" {
+		return name
+	}
+	return manifest.GetName()
+}
@@ -0,0 +1,226 @@
+package sync_test
+
+import (
+	"context"
+	gosync "sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	machineryruntime "k8s.io/apimachinery/pkg/runtime"
+	machineryutilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/module/common"
+	"github.com/kyma-project/lifecycle-manager/pkg/module/sync"
+	"github.com/kyma-project/lifecycle-manager/pkg/testutils/builder"
+)
+
+func newDependentModule(name, version string, dependencies ...v1beta2.ModuleDependency) *common.Module {
+	module := newPlannedModule(name, version)
+	module.Template.Spec.Dependencies = dependencies
+	return module
+}
+
+func TestResolveDependencies(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		modules     func() common.Modules
+		expectedErr map[string]error
+	}{
+		{
+			name: "satisfied dependencies",
+			modules: func() common.Modules {
+				return common.Modules{
+					newPlannedModule("istio", "1.2.0"),
+					newDependentModule("eventing", "1.0.0", v1beta2.ModuleDependency{Name: "istio", Version: ">=1.0.0"}),
+					newDependentModule("serverless", "1.0.0", v1beta2.ModuleDependency{Name: "eventing"}),
+				}
+			},
+			expectedErr: map[string]error{},
+		},
+		{
+			name: "missing dependency fails its dependents transitively",
+			modules: func() common.Modules {
+				return common.Modules{
+					newDependentModule("eventing", "1.0.0", v1beta2.ModuleDependency{Name: "istio"}),
+					newDependentModule("serverless", "1.0.0", v1beta2.ModuleDependency{Name: "eventing"}),
+				}
+			},
+			expectedErr: map[string]error{
+				"eventing":   sync.ErrDependencyMissing,
+				"serverless": sync.ErrDependencyMissing,
+			},
+		},
+		{
+			name: "disabled dependency",
+			modules: func() common.Modules {
+				istio := newPlannedModule("istio", "1.2.0")
+				istio.Enabled = false
+				return common.Modules{
+					istio,
+					newDependentModule("eventing", "1.0.0", v1beta2.ModuleDependency{Name: "istio"}),
+				}
+			},
+			expectedErr: map[string]error{"eventing": sync.ErrDependencyMissing},
+		},
+		{
+			name: "dependency version does not satisfy the constraint",
+			modules: func() common.Modules {
+				return common.Modules{
+					newPlannedModule("istio", "0.9.0"),
+					newDependentModule("eventing", "1.0.0", v1beta2.ModuleDependency{Name: "istio", Version: ">=1.0.0"}),
+				}
+			},
+			expectedErr: map[string]error{"eventing": sync.ErrDependencyVersionMismatch},
+		},
+		{
+			name: "invalid version constraint",
+			modules: func() common.Modules {
+				return common.Modules{
+					newPlannedModule("istio", "1.2.0"),
+					newDependentModule("eventing", "1.0.0", v1beta2.ModuleDependency{Name: "istio", Version: "latest"}),
+				}
+			},
+			expectedErr: map[string]error{"eventing": sync.ErrInvalidDependencyConstraint},
+		},
+		{
+			name: "dependency cycle",
+			modules: func() common.Modules {
+				return common.Modules{
+					newPlannedModule("istio", "1.2.0"),
+					newDependentModule("eventing", "1.0.0", v1beta2.ModuleDependency{Name: "serverless"}),
+					newDependentModule("serverless", "1.0.0", v1beta2.ModuleDependency{Name: "eventing"}),
+				}
+			},
+			expectedErr: map[string]error{
+				"eventing":   sync.ErrDependencyCycle,
+				"serverless": sync.ErrDependencyCycle,
+			},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			modules := testCase.modules()
+			sync.ResolveDependencies(modules)
+			for _, module := range modules {
+				if expectedErr, found := testCase.expectedErr[module.ModuleName]; found {
+					require.ErrorIs(t, module.Template.Err, expectedErr, module.ModuleName)
+				} else {
+					require.NoError(t, module.Template.Err, module.ModuleName)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileManifests_InstallsDependenciesFirst(t *testing.T) {
+	t.Parallel()
+	scheme := machineryruntime.NewScheme()
+	machineryutilruntime.Must(v1beta2.AddToScheme(scheme))
+
+	var mu gosync.Mutex
+	var applied []string
+	readyModules := map[string]bool{"istio": true}
+	clnt := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch,
+			_ ...client.PatchOption,
+		) error {
+			mu.Lock()
+			defer mu.Unlock()
+			applied = append(applied, obj.GetName())
+			if manifest, ok := obj.(*v1beta2.Manifest); ok && readyModules[obj.GetName()] {
+				manifest.Status.State = shared.StateReady
+			}
+			return nil
+		},
+	}).Build()
+
+	istio := newPlannedModule("istio", "1.2.0")
+	eventing := newDependentModule("eventing", "1.0.0", v1beta2.ModuleDependency{Name: "istio"})
+	serverless := newDependentModule("serverless", "1.0.0", v1beta2.ModuleDependency{Name: "eventing"})
+	modules := common.Modules{serverless, eventing, istio}
+	kyma := builder.NewKymaBuilder().Build()
+	kyma.SetUID("kyma-uid")
+
+	err := sync.New(clnt).ReconcileManifests(context.Background(), kyma, modules)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"istio", "eventing"}, applied)
+	require.NoError(t, eventing.Template.Err)
+	require.ErrorIs(t, serverless.Template.Err, sync.ErrDependenciesNotReady)
+	assert.Equal(t, "istio", eventing.GetAnnotations()[shared.DependenciesAnnotation])
+	assert.Equal(t, "eventing", eventing.GetLabels()[shared.ModuleName])
+}
+
+func TestDeletableManifests(t *testing.T) {
+	t.Parallel()
+	newManifest := func(name string, dependencies string) v1beta2.Manifest {
+		manifest := v1beta2.Manifest{ObjectMeta: apimetav1.ObjectMeta{
+			Name:   name + "-manifest",
+			Labels: map[string]string{shared.ModuleName: name},
+		}}
+		if dependencies != "" {
+			manifest.SetAnnotations(map[string]string{shared.DependenciesAnnotation: dependencies})
+		}
+		return manifest
+	}
+	tests := []struct {
+		name      string
+		manifests []v1beta2.Manifest
+		expected  []string
+	}{
+		{
+			name: "dependents are deleted first",
+			manifests: []v1beta2.Manifest{
+				newManifest("istio", ""),
+				newManifest("eventing", "istio"),
+				newManifest("serverless", "eventing,istio"),
+				newManifest("keda", ""),
+			},
+			expected: []string{"serverless-manifest", "keda-manifest"},
+		},
+		{
+			name: "dependency is deleted once its dependents are gone",
+			manifests: []v1beta2.Manifest{
+				newManifest("istio", ""),
+			},
+			expected: []string{"istio-manifest"},
+		},
+		{
+			name: "all manifests are deleted in a cycle",
+			manifests: []v1beta2.Manifest{
+				newManifest("eventing", "serverless"),
+				newManifest("serverless", "eventing"),
+			},
+			expected: []string{"eventing-manifest", "serverless-manifest"},
+		},
+		{
+			name: "manifest without module name label is identified by its name",
+			manifests: []v1beta2.Manifest{
+				{ObjectMeta: apimetav1.ObjectMeta{Name: "istio"}},
+				newManifest("eventing", "istio"),
+			},
+			expected: []string{"eventing-manifest"},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			var deletable []string
+			for _, manifest := range sync.DeletableManifests(testCase.manifests) {
+				deletable = append(deletable, manifest.GetName())
+			}
+			assert.Equal(t, testCase.expected, deletable)
+		})
+	}
+}
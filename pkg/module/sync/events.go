@@ -0,0 +1,70 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/adapter"
+	"github.com/kyma-project/lifecycle-manager/pkg/cloudevents"
+)
+
+// publishModuleEvent publishes the lifecycle event of a module. A module that is Ready in another version than the
+// one it was last Ready in is reported as upgraded, even if it stayed Ready. Otherwise, a module that becomes Ready
+// is reported as installed, if it was never Ready before, or as recovered. A module that enters the Error state
+// is reported as failed.
+func publishModuleEvent(ctx context.Context, kyma *v1beta2.Kyma, existStatus, latestStatus *v1beta2.ModuleStatus) {
+	var previousState shared.State
+	var lastReadyVersion string
+	if existStatus != nil {
+		previousState, lastReadyVersion = existStatus.State, existStatus.LastReadyVersion
+	}
+	var eventType string
+	switch {
+	case latestStatus.State == shared.StateReady && lastReadyVersion != "" &&
+		lastReadyVersion != latestStatus.Version:
+		eventType = cloudevents.TypeModuleUpgraded
+	case previousState == latestStatus.State:
+		return
+	case latestStatus.State == shared.StateReady && lastReadyVersion == "":
+		eventType = cloudevents.TypeModuleInstalled
+	case latestStatus.State == shared.StateReady:
+		eventType = cloudevents.TypeModuleRecovered
+	case latestStatus.State == shared.StateError:
+		eventType = cloudevents.TypeModuleFailed
+	default:
+		return
+	}
+	data := moduleEventData(kyma, latestStatus)
+	data.PreviousState = previousState
+	adapter.EventSinkFromContext(ctx).Publish(ctx, cloudevents.NewModuleEvent(eventType, data))
+}
+
+// recordLastReadyVersion carries the last Ready version of the existing module status over to the latest status,
+// and replaces it with the version of the latest status if the module is Ready.
+func recordLastReadyVersion(existStatus, latestStatus *v1beta2.ModuleStatus) {
+	if latestStatus.State == shared.StateReady {
+		latestStatus.LastReadyVersion = latestStatus.Version
+	} else if existStatus != nil {
+		latestStatus.LastReadyVersion = existStatus.LastReadyVersion
+	}
+}
+
+// publishModuleDeletedEvent publishes the lifecycle event of a module that was removed from the Kyma.
+func publishModuleDeletedEvent(ctx context.Context, kyma *v1beta2.Kyma, moduleStatus *v1beta2.ModuleStatus) {
+	data := moduleEventData(kyma, moduleStatus)
+	data.State, data.PreviousState = "", moduleStatus.State
+	adapter.EventSinkFromContext(ctx).Publish(ctx, cloudevents.NewModuleEvent(cloudevents.TypeModuleDeleted, data))
+}
+
+func moduleEventData(kyma *v1beta2.Kyma, moduleStatus *v1beta2.ModuleStatus) cloudevents.ModuleData {
+	return cloudevents.ModuleData{
+		Kyma:      kyma.GetName(),
+		Namespace: kyma.GetNamespace(),
+		Module:    moduleStatus.Name,
+		Channel:   moduleStatus.Channel,
+		Version:   moduleStatus.Version,
+		State:     moduleStatus.State,
+		Message:   moduleStatus.Message,
+	}
+}
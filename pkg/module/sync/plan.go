@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/module/common"
+	"github.com/kyma-project/lifecycle-manager/pkg/templatelookup"
+	"github.com/kyma-project/lifecycle-manager/pkg/util"
+)
+
+// PlanManifests calculates the changes ReconcileManifests and the deletion of no longer existing modules
+// would apply to the Manifests of the given modules, without applying any of them.
+func (r *Runner) PlanManifests(ctx context.Context, kyma *v1beta2.Kyma,
+	modules common.Modules,
+) ([]v1beta2.ModulePlan, error) {
+	moduleStatusMap := kyma.GetModuleStatusMap()
+	plans := make([]v1beta2.ModulePlan, 0, len(modules))
+	for _, module := range modules {
+		plan, err := r.planManifest(ctx, module, moduleStatusMap[module.ModuleName])
+		if err != nil {
+			return nil, fmt.Errorf("could not plan module %s: %w", module.ModuleName, err)
+		}
+		plans = append(plans, plan)
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		return plans[i].Name < plans[j].Name
+	})
+	return plans, nil
+}
+
+func (r *Runner) planManifest(ctx context.Context, module *common.Module,
+	moduleStatus *v1beta2.ModuleStatus,
+) (v1beta2.ModulePlan, error) {
+	plan := v1beta2.ModulePlan{
+		Name:    module.ModuleName,
+		FQDN:    module.FQDN,
+		Channel: module.Template.DesiredChannel,
+	}
+	if moduleStatus != nil {
+		plan.PreviousVersion = moduleStatus.Version
+	}
+	if module.Template.ModuleTemplate != nil {
+		plan.Channel = module.Template.Spec.Channel
+		plan.Template = trackingObjectFor(module.Template.ModuleTemplate)
+	}
+
+	switch {
+	case errors.Is(module.Template.Err, templatelookup.ErrTemplateNotAllowed):
+		plan.Action = v1beta2.ModulePlanActionDelete
+		if module.Manifest != nil {
+			plan.Manifest = manifestTrackingObject(module.Manifest)
+		}
+		plan.Message = module.Template.Err.Error()
+		return plan, nil
+	case !module.Enabled:
+		plan.Action = v1beta2.ModulePlanActionDelete
+		if moduleStatus != nil {
+			plan.Manifest = moduleStatus.Manifest
+		}
+		plan.Message = "module is no longer part of the Kyma spec"
+		return plan, nil
+	case errors.Is(module.Template.Err, templatelookup.ErrTemplateUpdateNotAllowed):
+		plan.Action = v1beta2.ModulePlanActionUnchanged
+		plan.Version = plan.PreviousVersion
+		plan.Message = module.Template.Err.Error()
+		return plan, nil
+	case module.Template.Err != nil:
+		plan.Action = v1beta2.ModulePlanActionError
+		plan.Message = module.Template.Err.Error()
+		return plan, nil
+	}
+
+	plan.Manifest = manifestTrackingObject(module.Manifest)
+	plan.Version = module.Manifest.Spec.Version
+
+	manifestInCluster := &v1beta2.Manifest{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(module.Manifest), manifestInCluster)
+	if util.IsNotFound(err) {
+		plan.Action = v1beta2.ModulePlanActionCreate
+		return plan, nil
+	}
+	if err != nil {
+		return plan, fmt.Errorf("error get manifest %s: %w", client.ObjectKeyFromObject(module.Manifest), err)
+	}
+
+	plan.PreviousVersion = manifestInCluster.Spec.Version
+	if needToUpdate(manifestInCluster, module.Manifest) {
+		plan.Action = v1beta2.ModulePlanActionUpdate
+		plan.Message = fmt.Sprintf("version changes from %s to %s",
+			manifestInCluster.Spec.Version, module.Manifest.Spec.Version)
+		return plan, nil
+	}
+	plan.Action = v1beta2.ModulePlanActionUnchanged
+	return plan, nil
+}
+
+// manifestTrackingObject sets the TypeMeta explicitly, as Manifests generated from templates
+// are only converted to the preferred version when they are applied.
+func manifestTrackingObject(manifest *v1beta2.Manifest) *v1beta2.TrackingObject {
+	return &v1beta2.TrackingObject{
+		PartialMeta: v1beta2.PartialMetaFromObject(manifest),
+		TypeMeta:    apimetav1.TypeMeta{Kind: string(shared.ManifestKind), APIVersion: v1beta2.GroupVersion.String()},
+	}
+}
+
+func trackingObjectFor(obj client.Object) *v1beta2.TrackingObject {
+	apiVersion, kind := obj.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+	return &v1beta2.TrackingObject{
+		PartialMeta: v1beta2.PartialMetaFromObject(obj),
+		TypeMeta:    apimetav1.TypeMeta{Kind: kind, APIVersion: apiVersion},
+	}
+}
@@ -0,0 +1,101 @@
+package sync_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	machineryruntime "k8s.io/apimachinery/pkg/runtime"
+	machineryutilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/module/common"
+	"github.com/kyma-project/lifecycle-manager/pkg/module/sync"
+	"github.com/kyma-project/lifecycle-manager/pkg/templatelookup"
+	"github.com/kyma-project/lifecycle-manager/pkg/testutils/builder"
+)
+
+func newPlannedModule(name, version string) *common.Module {
+	template := builder.NewModuleTemplateBuilder().WithName(name + "-template").WithChannel("regular").Build()
+	manifest := &v1beta2.Manifest{
+		ObjectMeta: apimetav1.ObjectMeta{Name: name, Namespace: apimetav1.NamespaceDefault},
+		Spec:       v1beta2.ManifestSpec{Version: version},
+	}
+	return &common.Module{
+		ModuleName: name,
+		FQDN:       "kyma-project.io/module/" + name,
+		Template:   &templatelookup.ModuleTemplateInfo{ModuleTemplate: template, DesiredChannel: "regular"},
+		Manifest:   manifest,
+		Enabled:    true,
+	}
+}
+
+func TestPlanManifests(t *testing.T) {
+	t.Parallel()
+	scheme := machineryruntime.NewScheme()
+	machineryutilruntime.Must(v1beta2.AddToScheme(scheme))
+
+	existingManifests := []*v1beta2.Manifest{
+		{
+			ObjectMeta: apimetav1.ObjectMeta{Name: "unchanged", Namespace: apimetav1.NamespaceDefault},
+			Spec:       v1beta2.ManifestSpec{Version: "1.0.0"},
+		},
+		{
+			ObjectMeta: apimetav1.ObjectMeta{Name: "updated", Namespace: apimetav1.NamespaceDefault},
+			Spec:       v1beta2.ManifestSpec{Version: "1.0.0"},
+		},
+	}
+	clientBuilder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, manifest := range existingManifests {
+		clientBuilder = clientBuilder.WithObjects(manifest)
+	}
+	runner := sync.New(clientBuilder.Build())
+
+	failed := &common.Module{
+		ModuleName: "failed",
+		Template: &templatelookup.ModuleTemplateInfo{
+			Err:            templatelookup.ErrNoTemplatesInListResult,
+			DesiredChannel: "fast",
+		},
+		Enabled: true,
+	}
+	removed := newPlannedModule("removed", "1.0.0")
+	removed.Enabled = false
+	kyma := builder.NewKymaBuilder().Build()
+	kyma.Status.Modules = []v1beta2.ModuleStatus{{
+		Name:     "removed",
+		Version:  "1.0.0",
+		Manifest: &v1beta2.TrackingObject{PartialMeta: v1beta2.PartialMeta{Name: "removed"}},
+	}}
+	modules := common.Modules{
+		newPlannedModule("updated", "1.1.0"),
+		newPlannedModule("created", "1.0.0"),
+		newPlannedModule("unchanged", "1.0.0"),
+		failed,
+		removed,
+	}
+
+	plans, err := runner.PlanManifests(context.Background(), kyma, modules)
+
+	require.NoError(t, err)
+	actions := map[string]v1beta2.ModulePlanAction{}
+	names := make([]string, 0, len(plans))
+	for _, plan := range plans {
+		actions[plan.Name] = plan.Action
+		names = append(names, plan.Name)
+	}
+	assert.Equal(t, []string{"created", "failed", "removed", "unchanged", "updated"}, names)
+	assert.Equal(t, map[string]v1beta2.ModulePlanAction{
+		"created":   v1beta2.ModulePlanActionCreate,
+		"failed":    v1beta2.ModulePlanActionError,
+		"removed":   v1beta2.ModulePlanActionDelete,
+		"unchanged": v1beta2.ModulePlanActionUnchanged,
+		"updated":   v1beta2.ModulePlanActionUpdate,
+	}, actions)
+	assert.Equal(t, "1.0.0", plans[4].PreviousVersion)
+	assert.Equal(t, "1.1.0", plans[4].Version)
+	assert.Equal(t, "removed", plans[2].Manifest.Name)
+}
@@ -0,0 +1,213 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/adapter"
+	"github.com/kyma-project/lifecycle-manager/pkg/module/common"
+	"github.com/kyma-project/lifecycle-manager/pkg/util"
+)
+
+var (
+	ErrRollbackNotPossible  = errors.New("rollback is not possible")
+	ErrInvalidLastKnownGood = errors.New("invalid last known good annotation")
+)
+
+// rollbackRequestPatch removes the RollbackToAnnotation from a Manifest.
+var rollbackRequestPatch = client.RawPatch(types.JSONPatchType, []byte(
+	`[{"op":"remove","path":"/metadata/annotations/`+strings.ReplaceAll(shared.RollbackToAnnotation, "/", "~1")+`"}]`,
+))
+
+// versionAnnotations are derived from the ModuleTemplate of the version of a Manifest and the module config the
+// spec was rendered with, so they are rolled back together with the spec.
+var versionAnnotations = []string{
+	shared.CustomStateCheckAnnotation,
+	shared.InstallTimeoutAnnotation,
+	shared.DeleteTimeoutAnnotation,
+	shared.ModuleConfigAnnotation,
+}
+
+// lastKnownGood is stored as JSON in the LastKnownGoodAnnotation of a Manifest.
+type lastKnownGood struct {
+	// Spec is the Manifest spec of the last version that was Ready before the current upgrade.
+	Spec v1beta2.ManifestSpec `json:"spec"`
+	// Annotations are the versionAnnotations of the Manifest of the last version that was Ready.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// UpgradeTime is the time the current upgrade was started at. It is removed once the upgrade is Ready.
+	UpgradeTime *apimetav1.Time `json:"upgradeTime,omitempty"`
+}
+
+// trackUpgrade records the last known good spec of the module in the Manifest that is applied next and replaces the
+// spec with the last known good one, if the upgrade is not Ready within the rollback timeout or a rollback is
+// requested with the RollbackToAnnotation. It reports whether the Manifest in the cluster requests a rollback.
+func (r *Runner) trackUpgrade(ctx context.Context, module *common.Module) (bool, error) {
+	manifestInCluster := &v1beta2.Manifest{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(module.Manifest), manifestInCluster); err != nil {
+		if util.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("could not get manifest of module %s: %w", module.GetName(), err)
+	}
+	good, err := parseLastKnownGood(manifestInCluster)
+	if err != nil {
+		return false, err
+	}
+
+	now := apimetav1.Now()
+	state := manifestInCluster.Status.State
+	if manifestInCluster.Spec.Version != module.Manifest.Spec.Version {
+		if state == shared.StateReady {
+			good = &lastKnownGood{
+				Spec:        *manifestInCluster.Spec.DeepCopy(),
+				Annotations: versionAnnotationsOf(manifestInCluster),
+			}
+		}
+		if good != nil {
+			good.UpgradeTime = &now
+		}
+	} else if good != nil && good.UpgradeTime != nil && state == shared.StateReady {
+		good.UpgradeTime = nil
+	}
+
+	reason := r.rollbackReason(ctx, manifestInCluster, module.Manifest.Spec.Version, good, now.Time)
+	if reason != "" {
+		module.FailedUpgrade = &v1beta2.FailedUpgrade{
+			Version:      module.Manifest.Spec.Version,
+			RolledBackTo: good.Spec.Version,
+			Time:         now,
+			Reason:       reason,
+		}
+		module.Manifest.Spec = *good.Spec.DeepCopy()
+		restoreVersionAnnotations(module.Manifest, good.Annotations)
+		good.UpgradeTime = nil
+		logf.FromContext(ctx).Info("rolling back module upgrade", "module", module.ModuleName,
+			"version", module.FailedUpgrade.Version, "rolledBackTo", good.Spec.Version, "reason", reason)
+	}
+	_, rollbackRequested := manifestInCluster.GetAnnotations()[shared.RollbackToAnnotation]
+	return rollbackRequested, setLastKnownGood(module.Manifest, good)
+}
+
+// rollbackReason returns why the Manifest in the cluster has to be rolled back, or an empty string.
+// A rollback request that cannot be applied is reported with a Warning event on the Manifest and ignored,
+// the RollbackToAnnotation is removed anyway, so it does not fail every following reconciliation.
+func (r *Runner) rollbackReason(ctx context.Context, manifestInCluster *v1beta2.Manifest, version string,
+	good *lastKnownGood, now time.Time,
+) string {
+	if requested, found := manifestInCluster.GetAnnotations()[shared.RollbackToAnnotation]; found {
+		if err := validateRollbackRequest(requested, version, good); err != nil {
+			logf.FromContext(ctx).Info("ignoring rollback request", "manifest",
+				client.ObjectKeyFromObject(manifestInCluster), "reason", err.Error())
+			if recorder := adapter.RecorderFromContext(ctx); recorder != nil {
+				recorder.Event(manifestInCluster, "Warning", "RollbackRejected", err.Error())
+			}
+			return ""
+		}
+		return "rollback to " + requested + " was requested"
+	}
+	if r.RollbackTimeout <= 0 || good == nil || good.UpgradeTime == nil {
+		return ""
+	}
+	state := manifestInCluster.Status.State
+	if (state == shared.StateError || state == shared.StateProcessing) &&
+		now.Sub(good.UpgradeTime.Time) > r.RollbackTimeout {
+		return fmt.Sprintf("upgrade is in state %s for more than %s", state, r.RollbackTimeout)
+	}
+	return ""
+}
+
+func validateRollbackRequest(requested, version string, good *lastKnownGood) error {
+	if good == nil {
+		return fmt.Errorf("%w: no last known good version", ErrRollbackNotPossible)
+	}
+	if requested != good.Spec.Version {
+		return fmt.Errorf("%w: version %s is not the last known good version %s",
+			ErrRollbackNotPossible, requested, good.Spec.Version)
+	}
+	if requested == version {
+		return fmt.Errorf("%w: version %s is already the desired version", ErrRollbackNotPossible, requested)
+	}
+	return nil
+}
+
+func versionAnnotationsOf(manifest *v1beta2.Manifest) map[string]string {
+	var annotations map[string]string
+	for _, key := range versionAnnotations {
+		if value, found := manifest.GetAnnotations()[key]; found {
+			if annotations == nil {
+				annotations = make(map[string]string, len(versionAnnotations))
+			}
+			annotations[key] = value
+		}
+	}
+	return annotations
+}
+
+// restoreVersionAnnotations replaces the versionAnnotations of the Manifest with the given ones.
+func restoreVersionAnnotations(manifest *v1beta2.Manifest, good map[string]string) {
+	annotations := manifest.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	for _, key := range versionAnnotations {
+		delete(annotations, key)
+		if value, found := good[key]; found {
+			annotations[key] = value
+		}
+	}
+	manifest.SetAnnotations(annotations)
+}
+
+// clearRollbackRequest removes the RollbackToAnnotation from the Manifest once the rollback is applied.
+func (r *Runner) clearRollbackRequest(ctx context.Context, manifest *v1beta2.Manifest) error {
+	manifestToPatch := &v1beta2.Manifest{}
+	manifestToPatch.SetName(manifest.GetName())
+	manifestToPatch.SetNamespace(manifest.GetNamespace())
+	if err := r.Patch(ctx, manifestToPatch, rollbackRequestPatch); err != nil {
+		return fmt.Errorf("failed to remove rollback request from manifest %s: %w",
+			client.ObjectKeyFromObject(manifest), err)
+	}
+	return nil
+}
+
+// parseLastKnownGood returns the last known good spec of the Manifest, or nil if the Manifest has none.
+func parseLastKnownGood(manifest *v1beta2.Manifest) (*lastKnownGood, error) {
+	value := manifest.GetAnnotations()[shared.LastKnownGoodAnnotation]
+	if value == "" {
+		return nil, nil //nolint:nilnil // a missing annotation is no error
+	}
+	good := &lastKnownGood{}
+	if err := json.Unmarshal([]byte(value), good); err != nil {
+		return nil, fmt.Errorf("%w on manifest %s: %w", ErrInvalidLastKnownGood,
+			client.ObjectKeyFromObject(manifest), err)
+	}
+	return good, nil
+}
+
+func setLastKnownGood(manifest *v1beta2.Manifest, good *lastKnownGood) error {
+	if good == nil {
+		return nil
+	}
+	value, err := json.Marshal(good)
+	if err != nil {
+		return fmt.Errorf("failed to marshal last known good spec: %w", err)
+	}
+	annotations := manifest.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[shared.LastKnownGoodAnnotation] = string(value)
+	manifest.SetAnnotations(annotations)
+	return nil
+}
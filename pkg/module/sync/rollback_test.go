@@ -0,0 +1,240 @@
+package sync_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	machineryruntime "k8s.io/apimachinery/pkg/runtime"
+	machineryutilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/module/common"
+	"github.com/kyma-project/lifecycle-manager/pkg/module/sync"
+	"github.com/kyma-project/lifecycle-manager/pkg/testutils/builder"
+)
+
+func lastKnownGoodAnnotation(t *testing.T, version string, upgradeTime *time.Time) string {
+	t.Helper()
+	good := map[string]any{"spec": v1beta2.ManifestSpec{Version: version}}
+	if upgradeTime != nil {
+		good["upgradeTime"] = apimetav1.NewTime(*upgradeTime)
+	}
+	value, err := json.Marshal(good)
+	require.NoError(t, err)
+	return string(value)
+}
+
+// reconcileWithInstalledManifest reconciles the module against the installed Manifest
+// and returns the Manifest that is applied.
+func reconcileWithInstalledManifest(t *testing.T, installed *v1beta2.Manifest, module *common.Module,
+	rollbackTimeout time.Duration,
+) (*v1beta2.Manifest, client.Client) {
+	t.Helper()
+	scheme := machineryruntime.NewScheme()
+	machineryutilruntime.Must(v1beta2.AddToScheme(scheme))
+	var applied *v1beta2.Manifest
+	clnt := fake.NewClientBuilder().WithScheme(scheme).WithObjects(installed).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, clnt client.WithWatch, obj client.Object, patch client.Patch,
+				opts ...client.PatchOption,
+			) error {
+				if patch != client.Apply {
+					return clnt.Patch(ctx, obj, patch, opts...)
+				}
+				manifest, ok := obj.(*v1beta2.Manifest)
+				require.True(t, ok)
+				applied = manifest.DeepCopy()
+				return nil
+			},
+		}).Build()
+	runner := sync.New(clnt)
+	runner.RollbackTimeout = rollbackTimeout
+
+	kyma := builder.NewKymaBuilder().WithModule(v1beta2.Module{Name: module.ModuleName}).Build()
+	require.NoError(t, runner.ReconcileManifests(context.Background(), kyma, common.Modules{module}))
+	require.NotNil(t, applied)
+	return applied, clnt
+}
+
+func TestReconcileManifests_RecordsLastKnownGoodOnUpgrade(t *testing.T) {
+	t.Parallel()
+	installed := &v1beta2.Manifest{
+		ObjectMeta: apimetav1.ObjectMeta{Name: "upgraded", Namespace: apimetav1.NamespaceDefault},
+		Spec:       v1beta2.ManifestSpec{Version: "1.0.0"},
+		Status:     shared.Status{State: shared.StateReady},
+	}
+	module := newPlannedModule("upgraded", "1.1.0")
+
+	applied, _ := reconcileWithInstalledManifest(t, installed, module, time.Hour)
+
+	assert.Equal(t, "1.1.0", applied.Spec.Version)
+	assert.Nil(t, module.FailedUpgrade)
+	good := map[string]any{}
+	require.NoError(t, json.Unmarshal([]byte(applied.GetAnnotations()[shared.LastKnownGoodAnnotation]), &good))
+	assert.Equal(t, "1.0.0", good["spec"].(map[string]any)["version"])
+	assert.NotEmpty(t, good["upgradeTime"])
+}
+
+func TestReconcileManifests_RollsBackFailedUpgrade(t *testing.T) {
+	t.Parallel()
+	upgradeTime := time.Now().Add(-2 * time.Hour)
+	tests := []struct {
+		name            string
+		state           shared.State
+		upgradeTime     *time.Time
+		rollbackTimeout time.Duration
+		expectRollback  bool
+	}{
+		{"error after timeout", shared.StateError, &upgradeTime, time.Hour, true},
+		{"processing after timeout", shared.StateProcessing, &upgradeTime, time.Hour, true},
+		{"error within timeout", shared.StateError, &upgradeTime, 3 * time.Hour, false},
+		{"ready after timeout", shared.StateReady, &upgradeTime, time.Hour, false},
+		{"completed upgrade", shared.StateError, nil, time.Hour, false},
+		{"automatic rollback disabled", shared.StateError, &upgradeTime, 0, false},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			installed := &v1beta2.Manifest{
+				ObjectMeta: apimetav1.ObjectMeta{
+					Name:      "upgraded",
+					Namespace: apimetav1.NamespaceDefault,
+					Annotations: map[string]string{
+						shared.LastKnownGoodAnnotation: lastKnownGoodAnnotation(t, "1.0.0", testCase.upgradeTime),
+					},
+				},
+				Spec:   v1beta2.ManifestSpec{Version: "1.1.0"},
+				Status: shared.Status{State: testCase.state},
+			}
+			module := newPlannedModule("upgraded", "1.1.0")
+
+			applied, _ := reconcileWithInstalledManifest(t, installed, module, testCase.rollbackTimeout)
+
+			if !testCase.expectRollback {
+				assert.Equal(t, "1.1.0", applied.Spec.Version)
+				assert.Nil(t, module.FailedUpgrade)
+				return
+			}
+			assert.Equal(t, "1.0.0", applied.Spec.Version)
+			require.NotNil(t, module.FailedUpgrade)
+			assert.Equal(t, "1.1.0", module.FailedUpgrade.Version)
+			assert.Equal(t, "1.0.0", module.FailedUpgrade.RolledBackTo)
+			assert.NotContains(t, applied.GetAnnotations()[shared.LastKnownGoodAnnotation], "upgradeTime")
+		})
+	}
+}
+
+func TestReconcileManifests_RollsBackOnRequest(t *testing.T) {
+	t.Parallel()
+	installed := &v1beta2.Manifest{
+		ObjectMeta: apimetav1.ObjectMeta{
+			Name:      "upgraded",
+			Namespace: apimetav1.NamespaceDefault,
+			Annotations: map[string]string{
+				shared.LastKnownGoodAnnotation: lastKnownGoodAnnotation(t, "1.0.0", nil),
+				shared.RollbackToAnnotation:    "1.0.0",
+			},
+		},
+		Spec:   v1beta2.ManifestSpec{Version: "1.1.0"},
+		Status: shared.Status{State: shared.StateReady},
+	}
+	module := newPlannedModule("upgraded", "1.1.0")
+
+	applied, clnt := reconcileWithInstalledManifest(t, installed, module, 0)
+
+	assert.Equal(t, "1.0.0", applied.Spec.Version)
+	require.NotNil(t, module.FailedUpgrade)
+	assert.Equal(t, "1.1.0", module.FailedUpgrade.Version)
+	manifestInCluster := &v1beta2.Manifest{}
+	require.NoError(t, clnt.Get(context.Background(), client.ObjectKeyFromObject(installed), manifestInCluster))
+	assert.NotContains(t, manifestInCluster.GetAnnotations(), shared.RollbackToAnnotation)
+}
+
+func TestReconcileManifests_IgnoresRollbackToUnknownVersion(t *testing.T) {
+	t.Parallel()
+	installed := &v1beta2.Manifest{
+		ObjectMeta: apimetav1.ObjectMeta{
+			Name:      "upgraded",
+			Namespace: apimetav1.NamespaceDefault,
+			Annotations: map[string]string{
+				shared.LastKnownGoodAnnotation: lastKnownGoodAnnotation(t, "1.0.0", nil),
+				shared.RollbackToAnnotation:    "0.9.0",
+			},
+		},
+		Spec:   v1beta2.ManifestSpec{Version: "1.1.0"},
+		Status: shared.Status{State: shared.StateReady},
+	}
+	module := newPlannedModule("upgraded", "1.1.0")
+
+	applied, clnt := reconcileWithInstalledManifest(t, installed, module, 0)
+
+	assert.Equal(t, "1.1.0", applied.Spec.Version)
+	assert.Nil(t, module.FailedUpgrade)
+	manifestInCluster := &v1beta2.Manifest{}
+	require.NoError(t, clnt.Get(context.Background(), client.ObjectKeyFromObject(installed), manifestInCluster))
+	assert.NotContains(t, manifestInCluster.GetAnnotations(), shared.RollbackToAnnotation)
+}
+
+func TestReconcileManifests_RollsBackTemplateAnnotations(t *testing.T) {
+	t.Parallel()
+	good, err := json.Marshal(map[string]any{
+		"spec":        v1beta2.ManifestSpec{Version: "1.0.0"},
+		"annotations": map[string]string{shared.InstallTimeoutAnnotation: "5m0s"},
+	})
+	require.NoError(t, err)
+	installed := &v1beta2.Manifest{
+		ObjectMeta: apimetav1.ObjectMeta{
+			Name:      "upgraded",
+			Namespace: apimetav1.NamespaceDefault,
+			Annotations: map[string]string{
+				shared.LastKnownGoodAnnotation: string(good),
+				shared.RollbackToAnnotation:    "1.0.0",
+			},
+		},
+		Spec:   v1beta2.ManifestSpec{Version: "1.1.0"},
+		Status: shared.Status{State: shared.StateReady},
+	}
+	module := newPlannedModule("upgraded", "1.1.0")
+	module.Manifest.SetAnnotations(map[string]string{
+		shared.InstallTimeoutAnnotation:   "10m0s",
+		shared.CustomStateCheckAnnotation: `[{"jsonPath":"status.health","value":"green","mappedState":"Ready"}]`,
+	})
+
+	applied, _ := reconcileWithInstalledManifest(t, installed, module, 0)
+
+	assert.Equal(t, "1.0.0", applied.Spec.Version)
+	assert.Equal(t, "5m0s", applied.GetAnnotations()[shared.InstallTimeoutAnnotation])
+	assert.NotContains(t, applied.GetAnnotations(), shared.CustomStateCheckAnnotation)
+}
+
+func TestReconcileManifests_RecordsTemplateAnnotationsOfLastKnownGood(t *testing.T) {
+	t.Parallel()
+	installed := &v1beta2.Manifest{
+		ObjectMeta: apimetav1.ObjectMeta{
+			Name:        "upgraded",
+			Namespace:   apimetav1.NamespaceDefault,
+			Annotations: map[string]string{shared.DeleteTimeoutAnnotation: "5m0s"},
+		},
+		Spec:   v1beta2.ManifestSpec{Version: "1.0.0"},
+		Status: shared.Status{State: shared.StateReady},
+	}
+	module := newPlannedModule("upgraded", "1.1.0")
+
+	applied, _ := reconcileWithInstalledManifest(t, installed, module, 0)
+
+	good := struct {
+		Annotations map[string]string `json:"annotations"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(applied.GetAnnotations()[shared.LastKnownGoodAnnotation]), &good))
+	assert.Equal(t, map[string]string{shared.DeleteTimeoutAnnotation: "5m0s"}, good.Annotations)
+}
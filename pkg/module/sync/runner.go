@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	machineryruntime "k8s.io/apimachinery/pkg/runtime"
@@ -20,11 +22,15 @@ import (
 	"github.com/kyma-project/lifecycle-manager/pkg/log"
 	"github.com/kyma-project/lifecycle-manager/pkg/module/common"
 	"github.com/kyma-project/lifecycle-manager/pkg/templatelookup"
+	"github.com/kyma-project/lifecycle-manager/pkg/tracing"
 	"github.com/kyma-project/lifecycle-manager/pkg/util"
 )
 
 var ErrServerSideApplyFailed = errors.New("ServerSideApply failed")
 
+// maxUnhealthyResourcesInMessage limits the unhealthy resources of the Manifest that are listed in the module status.
+const maxUnhealthyResourcesInMessage = 3
+
 func New(clnt client.Client) *Runner {
 	return &Runner{
 		Client:    clnt,
@@ -43,14 +49,47 @@ type Runner struct {
 	client.Client
 	versioner machineryruntime.GroupVersioner
 	converter machineryruntime.ObjectConvertor
+	// RollbackTimeout is the time after which an upgrade whose Manifest is still in the Error or Processing state
+	// is rolled back to the last known good version. Automatic rollbacks are disabled if it is zero.
+	RollbackTimeout time.Duration
 }
 
+// ReconcileManifests applies the Manifests of all modules. Modules are applied level by level in dependency order,
+// modules within a level in parallel. A module is only applied once all of its dependencies are Ready,
+// otherwise it is marked as waiting for its dependencies and applied in a later reconciliation.
 func (r *Runner) ReconcileManifests(ctx context.Context, kyma *v1beta2.Kyma,
 	modules common.Modules,
 ) error {
 	ssaStart := time.Now()
 	baseLogger := logf.FromContext(ctx)
+	ctx, span := tracing.Start(ctx, "Runner.ReconcileManifests")
+
+	levels, _ := dependencyLevels(modules)
+	ready := make(map[string]bool, len(modules))
+	var errs []error
+	for _, level := range levels {
+		errs = append(errs, r.reconcileLevel(ctx, kyma, level, ready)...)
+		for _, module := range level {
+			ready[module.ModuleName] = module.Template.Err == nil &&
+				module.Manifest.Status.State == shared.StateReady
+		}
+	}
+	ssaFinish := time.Since(ssaStart)
+	if len(errs) != 0 {
+		errs = append(errs, fmt.Errorf("%w (after %s)", ErrServerSideApplyFailed, ssaFinish))
+		err := errors.Join(errs...)
+		tracing.End(span, err)
+		return err
+	}
+	tracing.End(span, nil)
+	baseLogger.V(log.DebugLevel).Info("ServerSideApply finished", "time", ssaFinish)
+	return nil
+}
 
+func (r *Runner) reconcileLevel(ctx context.Context, kyma *v1beta2.Kyma,
+	modules common.Modules, ready map[string]bool,
+) []error {
+	baseLogger := logf.FromContext(ctx)
 	results := make(chan error, len(modules))
 	for _, module := range modules {
 		go func(module *common.Module) {
@@ -64,7 +103,20 @@ func (r *Runner) ReconcileManifests(ctx context.Context, kyma *v1beta2.Kyma,
 				results <- nil
 				return
 			}
-			if err := r.updateManifests(ctx, kyma, module); err != nil {
+			if notReady := notReadyDependencies(module, ready); module.Enabled && len(notReady) > 0 {
+				module.Template.Err = fmt.Errorf("%w: %s", ErrDependenciesNotReady, strings.Join(notReady, ", "))
+				results <- nil
+				return
+			}
+			if module.DeferredUpgrade != nil {
+				results <- r.keepDeferredManifest(ctx, module)
+				return
+			}
+			moduleCtx, span := tracing.Start(ctx, "Runner.updateManifests",
+				trace.WithAttributes(tracing.ModuleNameKey.String(module.ModuleName)))
+			err := r.updateManifests(moduleCtx, kyma, module)
+			tracing.End(span, err)
+			if err != nil {
 				results <- fmt.Errorf("could not update module %s: %w", module.GetName(), err)
 				return
 			}
@@ -78,12 +130,17 @@ func (r *Runner) ReconcileManifests(ctx context.Context, kyma *v1beta2.Kyma,
 			errs = append(errs, err)
 		}
 	}
-	ssaFinish := time.Since(ssaStart)
-	if len(errs) != 0 {
-		errs = append(errs, fmt.Errorf("%w (after %s)", ErrServerSideApplyFailed, ssaFinish))
-		return errors.Join(errs...)
+	return errs
+}
+
+// keepDeferredManifest replaces the Manifest of a module with a deferred upgrade with the Manifest in the cluster,
+// so the status reflects the installed version until the upgrade is applied.
+func (r *Runner) keepDeferredManifest(ctx context.Context, module *common.Module) error {
+	manifestInCluster := &v1beta2.Manifest{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(module.Manifest), manifestInCluster); err != nil {
+		return fmt.Errorf("could not get manifest of module %s with deferred upgrade: %w", module.GetName(), err)
 	}
-	baseLogger.V(log.DebugLevel).Info("ServerSideApply finished", "time", ssaFinish)
+	module.Manifest = manifestInCluster
 	return nil
 }
 
@@ -101,6 +158,16 @@ func (r *Runner) updateManifests(ctx context.Context, kyma *v1beta2.Kyma,
 	if err := r.setupModule(module, kyma); err != nil {
 		return err
 	}
+	rollbackRequested := false
+	if module.Enabled {
+		var err error
+		if rollbackRequested, err = r.trackUpgrade(ctx, module); err != nil {
+			return err
+		}
+		if err := r.linkTrace(ctx, module); err != nil {
+			return err
+		}
+	}
 	obj, err := r.converter.ConvertToVersion(module.Manifest, r.versioner)
 	if err != nil {
 		return fmt.Errorf("failed to convert object to version: %w", err)
@@ -115,6 +182,9 @@ func (r *Runner) updateManifests(ctx context.Context, kyma *v1beta2.Kyma,
 		return err
 	}
 	module.Manifest = manifestObj
+	if rollbackRequested {
+		return r.clearRollbackRequest(ctx, manifestObj)
+	}
 	return nil
 }
 
@@ -163,6 +233,36 @@ func needToUpdate(manifestInCluster, manifestObj *v1beta2.Manifest) bool {
 	return manifestInCluster.Spec.Version != manifestObj.Spec.Version
 }
 
+// linkTrace sets the TraceParentAnnotation of the Manifest to the trace of the reconciliation if the Manifest is
+// installed, or its version or module config is changed. Otherwise, the annotation of the Manifest in the cluster
+// is kept, so the Manifest is not changed by every reconciliation.
+func (r *Runner) linkTrace(ctx context.Context, module *common.Module) error {
+	traceParent := tracing.TraceParent(ctx)
+	if traceParent == "" {
+		return nil
+	}
+	manifestInCluster := &v1beta2.Manifest{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(module.Manifest), manifestInCluster)
+	if err != nil && !util.IsNotFound(err) {
+		return fmt.Errorf("could not get manifest of module %s: %w", module.GetName(), err)
+	}
+	if err == nil && !needToUpdate(manifestInCluster, module.Manifest) &&
+		manifestInCluster.GetAnnotations()[shared.ModuleConfigAnnotation] ==
+			module.Manifest.GetAnnotations()[shared.ModuleConfigAnnotation] {
+		traceParent = manifestInCluster.GetAnnotations()[shared.TraceParentAnnotation]
+	}
+	if traceParent == "" {
+		return nil
+	}
+	annotations := module.Manifest.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[shared.TraceParentAnnotation] = traceParent
+	module.Manifest.SetAnnotations(annotations)
+	return nil
+}
+
 func (r *Runner) deleteManifest(ctx context.Context, module *common.Module) error {
 	err := r.Delete(ctx, module.Manifest)
 	if util.IsNotFound(err) {
@@ -187,11 +287,11 @@ func (r *Runner) setupModule(module *common.Module, kyma *v1beta2.Kyma) error {
 func (r *Runner) SyncModuleStatus(ctx context.Context, kyma *v1beta2.Kyma, modules common.Modules,
 	metrics ModuleMetrics,
 ) {
-	updateModuleStatusFromExistingModules(modules, kyma)
+	updateModuleStatusFromExistingModules(ctx, modules, kyma)
 	DeleteNoLongerExistingModuleStatus(ctx, kyma, r.getModule, metrics)
 }
 
-func updateModuleStatusFromExistingModules(
+func updateModuleStatusFromExistingModules(ctx context.Context,
 	modules common.Modules,
 	kyma *v1beta2.Kyma,
 ) {
@@ -201,6 +301,9 @@ func updateModuleStatusFromExistingModules(
 		module := modules[idx]
 		moduleStatus, exists := moduleStatusMap[module.ModuleName]
 		latestModuleStatus := generateModuleStatus(module, moduleStatus)
+		recordModuleStateTransition(module, moduleStatus, &latestModuleStatus)
+		publishModuleEvent(ctx, kyma, moduleStatus, &latestModuleStatus)
+		recordLastReadyVersion(moduleStatus, &latestModuleStatus)
 		if exists {
 			*moduleStatus = latestModuleStatus
 		} else {
@@ -209,7 +312,35 @@ func updateModuleStatusFromExistingModules(
 	}
 }
 
+// recordModuleStateTransition carries the history of the existing module status over to the latest status
+// and records the transition of the state with the message of the module or the last operation of its Manifest.
+func recordModuleStateTransition(module *common.Module, existStatus, latestStatus *v1beta2.ModuleStatus) {
+	var previousState shared.State
+	if existStatus != nil {
+		previousState = existStatus.State
+		latestStatus.History = existStatus.History
+	}
+	reason := latestStatus.Message
+	if reason == "" && module.Manifest != nil {
+		reason = module.Manifest.Status.Operation
+	}
+	latestStatus.RecordStateTransition(previousState, reason)
+}
+
 func generateModuleStatus(module *common.Module, existStatus *v1beta2.ModuleStatus) v1beta2.ModuleStatus {
+	if errors.Is(module.Template.Err, ErrDependenciesNotReady) {
+		newModuleStatus := v1beta2.ModuleStatus{
+			Name:    module.ModuleName,
+			Channel: module.Template.Spec.Channel,
+			FQDN:    module.FQDN,
+		}
+		if existStatus != nil {
+			newModuleStatus = *existStatus.DeepCopy()
+		}
+		newModuleStatus.State = shared.StateProcessing
+		newModuleStatus.Message = module.Template.Err.Error()
+		return newModuleStatus
+	}
 	if errors.Is(module.Template.Err, templatelookup.ErrTemplateUpdateNotAllowed) {
 		newModuleStatus := existStatus.DeepCopy()
 		newModuleStatus.State = shared.StateWarning
@@ -251,11 +382,15 @@ func generateModuleStatus(module *common.Module, existStatus *v1beta2.ModuleStat
 		}
 	}
 
+	channel := module.Template.Spec.Channel
+	if module.DeferredUpgrade != nil {
+		channel = manifestObject.GetLabels()[shared.ChannelLabel]
+	}
 	return v1beta2.ModuleStatus{
 		Name:    module.ModuleName,
 		FQDN:    module.FQDN,
 		State:   manifestObject.Status.State,
-		Channel: module.Template.Spec.Channel,
+		Channel: channel,
 		Version: manifestObject.Spec.Version,
 		Manifest: &v1beta2.TrackingObject{
 			PartialMeta: v1beta2.PartialMetaFromObject(manifestObject),
@@ -265,8 +400,21 @@ func generateModuleStatus(module *common.Module, existStatus *v1beta2.ModuleStat
 			PartialMeta: v1beta2.PartialMetaFromObject(module.Template),
 			TypeMeta:    apimetav1.TypeMeta{Kind: templateKind, APIVersion: templateAPIVersion},
 		},
-		Resource: moduleResource,
+		Resource:        moduleResource,
+		DeferredUpgrade: module.DeferredUpgrade,
+		FailedUpgrade:   module.FailedUpgrade,
+		Message:         describeUnhealthyResources(manifestObject.Status),
+	}
+}
+
+// describeUnhealthyResources summarizes the unhealthy resources of the Manifest for the module status.
+// The Manifest only lists the first unhealthy resources, so the remaining ones are counted with the total count.
+func describeUnhealthyResources(status shared.Status) string {
+	if len(status.UnhealthyResources) == 0 {
+		return ""
 	}
+	return "unhealthy resources: " + util.DescribeFirst(status.UnhealthyResources, maxUnhealthyResourcesInMessage,
+		status.UnhealthyResourcesCount)
 }
 
 func stateFromManifest(obj client.Object) shared.State {
@@ -304,6 +452,7 @@ func DeleteNoLongerExistingModuleStatus(ctx context.Context, kyma *v1beta2.Kyma,
 			if metrics != nil {
 				metrics.RemoveModuleStateMetrics(kyma.Name, moduleStatus.Name)
 			}
+			publishModuleDeletedEvent(ctx, kyma, moduleStatus)
 			delete(moduleStatusMap, moduleStatus.Name)
 		} else {
 			moduleStatus.State = stateFromManifest(module)
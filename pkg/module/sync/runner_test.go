@@ -5,16 +5,29 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	machineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	machineryutilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
+	"github.com/kyma-project/lifecycle-manager/api/shared"
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/adapter"
+	"github.com/kyma-project/lifecycle-manager/pkg/cloudevents"
+	"github.com/kyma-project/lifecycle-manager/pkg/module/common"
 	"github.com/kyma-project/lifecycle-manager/pkg/module/sync"
 	"github.com/kyma-project/lifecycle-manager/pkg/testutils"
+	"github.com/kyma-project/lifecycle-manager/pkg/testutils/builder"
 )
 
 const (
@@ -186,3 +199,294 @@ func configureModuleInKyma(
 		kyma.Status.Modules = append(kyma.Status.Modules, module)
 	}
 }
+
+func TestReconcileManifests_KeepsManifestOfDeferredUpgrade(t *testing.T) {
+	t.Parallel()
+	scheme := machineryruntime.NewScheme()
+	machineryutilruntime.Must(v1beta2.AddToScheme(scheme))
+	installed := &v1beta2.Manifest{
+		ObjectMeta: apimetav1.ObjectMeta{
+			Name:      "upgraded",
+			Namespace: apimetav1.NamespaceDefault,
+			Labels:    map[string]string{shared.ChannelLabel: "regular"},
+		},
+		Spec:   v1beta2.ManifestSpec{Version: "1.0.0"},
+		Status: shared.Status{State: shared.StateReady},
+	}
+	patched := false
+	clnt := fake.NewClientBuilder().WithScheme(scheme).WithObjects(installed).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(_ context.Context, _ client.WithWatch, _ client.Object, _ client.Patch,
+				_ ...client.PatchOption,
+			) error {
+				patched = true
+				return nil
+			},
+		}).Build()
+	runner := sync.New(clnt)
+
+	module := newPlannedModule("upgraded", "1.1.0")
+	module.Template.Spec.Channel = "fast"
+	nextWindow := apimetav1.NewTime(time.Date(2024, time.March, 10, 2, 0, 0, 0, time.UTC))
+	module.DeferredUpgrade = &v1beta2.DeferredUpgrade{
+		Version:    "1.1.0",
+		Channel:    "fast",
+		NextWindow: &nextWindow,
+	}
+	kyma := builder.NewKymaBuilder().WithModule(v1beta2.Module{Name: "upgraded"}).Build()
+
+	require.NoError(t, runner.ReconcileManifests(context.Background(), kyma, common.Modules{module}))
+	runner.SyncModuleStatus(context.Background(), kyma, common.Modules{module}, nil)
+
+	assert.False(t, patched)
+	require.Len(t, kyma.Status.Modules, 1)
+	moduleStatus := kyma.Status.Modules[0]
+	assert.Equal(t, "1.0.0", moduleStatus.Version)
+	assert.Equal(t, "regular", moduleStatus.Channel)
+	assert.Equal(t, shared.StateReady, moduleStatus.State)
+	assert.Equal(t, module.DeferredUpgrade, moduleStatus.DeferredUpgrade)
+}
+
+func TestSyncModuleStatus_SummarizesUnhealthyResources(t *testing.T) {
+	t.Parallel()
+	unhealthy := func(name string) shared.UnhealthyResource {
+		return shared.UnhealthyResource{
+			Resource: shared.Resource{
+				Name:             name,
+				Namespace:        "kyma-system",
+				GroupVersionKind: apimetav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			},
+			Reason:  "LessReplicas",
+			Message: "Replicas: 0/1",
+		}
+	}
+	tests := []struct {
+		name               string
+		unhealthyResources []shared.UnhealthyResource
+		expectedMessage    string
+	}{
+		{"healthy resources", nil, ""},
+		{
+			"unhealthy resources",
+			[]shared.UnhealthyResource{unhealthy("first")},
+			"unhealthy resources: Deployment kyma-system/first (Replicas: 0/1)",
+		},
+		{
+			"too many unhealthy resources",
+			[]shared.UnhealthyResource{unhealthy("a"), unhealthy("b"), unhealthy("c"), unhealthy("d"), unhealthy("e")},
+			"unhealthy resources: Deployment kyma-system/a (Replicas: 0/1); Deployment kyma-system/b (Replicas: 0/1); " +
+				"Deployment kyma-system/c (Replicas: 0/1); and 2 more",
+		},
+		{
+			"more unhealthy resources than listed in the Manifest",
+			[]shared.UnhealthyResource{
+				unhealthy("a"), unhealthy("b"), unhealthy("c"), unhealthy("d"), unhealthy("e"), unhealthy("f"),
+				unhealthy("g"), unhealthy("h"), unhealthy("i"), unhealthy("j"), unhealthy("k"), unhealthy("l"),
+			},
+			"unhealthy resources: Deployment kyma-system/a (Replicas: 0/1); Deployment kyma-system/b (Replicas: 0/1); " +
+				"Deployment kyma-system/c (Replicas: 0/1); and 9 more",
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			module := newPlannedModule("unhealthy", "1.0.0")
+			module.Manifest.Status = shared.Status{State: shared.StateProcessing}.
+				WithUnhealthyResources(testCase.unhealthyResources)
+			kyma := builder.NewKymaBuilder().WithModule(v1beta2.Module{Name: "unhealthy"}).Build()
+
+			sync.New(fake.NewClientBuilder().Build()).SyncModuleStatus(context.Background(), kyma,
+				common.Modules{module}, nil)
+
+			require.Len(t, kyma.Status.Modules, 1)
+			assert.Equal(t, shared.StateProcessing, kyma.Status.Modules[0].State)
+			assert.Equal(t, testCase.expectedMessage, kyma.Status.Modules[0].Message)
+		})
+	}
+}
+
+func TestSyncModuleStatus_RecordsStateTransitions(t *testing.T) {
+	t.Parallel()
+	module := newPlannedModule("transitioned", "1.1.0")
+	module.Manifest.Status = shared.Status{
+		State:         shared.StateError,
+		LastOperation: shared.LastOperation{Operation: "resources failed"},
+	}
+	kyma := builder.NewKymaBuilder().WithModule(v1beta2.Module{Name: "transitioned"}).Build()
+	firstTransition := v1beta2.StateTransition{To: shared.StateReady, Version: "1.0.0"}
+	kyma.Status.Modules = []v1beta2.ModuleStatus{{
+		Name:    "transitioned",
+		State:   shared.StateReady,
+		History: []v1beta2.StateTransition{firstTransition},
+	}}
+	runner := sync.New(fake.NewClientBuilder().Build())
+
+	runner.SyncModuleStatus(context.Background(), kyma, common.Modules{module}, nil)
+	runner.SyncModuleStatus(context.Background(), kyma, common.Modules{module}, nil)
+
+	require.Len(t, kyma.Status.Modules, 1)
+	history := kyma.Status.Modules[0].History
+	require.Len(t, history, 2)
+	assert.Equal(t, firstTransition, history[0])
+	assert.Equal(t, shared.StateReady, history[1].From)
+	assert.Equal(t, shared.StateError, history[1].To)
+	assert.Equal(t, "resources failed", history[1].Reason)
+	assert.Equal(t, "1.1.0", history[1].Version)
+}
+
+type recordingSink struct {
+	events []cloudevents.Event
+}
+
+func (s *recordingSink) Publish(_ context.Context, event cloudevents.Event) {
+	s.events = append(s.events, event)
+}
+
+func TestSyncModuleStatus_PublishesModuleEvents(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		previous      *v1beta2.ModuleStatus
+		state         shared.State
+		expectedTypes []string
+	}{
+		{"installed", &v1beta2.ModuleStatus{State: shared.StateProcessing}, shared.StateReady,
+			[]string{cloudevents.TypeModuleInstalled}},
+		{
+			"upgraded",
+			&v1beta2.ModuleStatus{State: shared.StateProcessing, Version: "1.1.0", LastReadyVersion: "1.0.0"},
+			shared.StateReady,
+			[]string{cloudevents.TypeModuleUpgraded},
+		},
+		{
+			"upgraded without leaving the Ready state",
+			&v1beta2.ModuleStatus{State: shared.StateReady, Version: "1.0.0", LastReadyVersion: "1.0.0"},
+			shared.StateReady,
+			[]string{cloudevents.TypeModuleUpgraded},
+		},
+		{
+			"recovered",
+			&v1beta2.ModuleStatus{State: shared.StateError, Version: "1.1.0", LastReadyVersion: "1.1.0"},
+			shared.StateReady,
+			[]string{cloudevents.TypeModuleRecovered},
+		},
+		{
+			"recovered after more state transitions than the history holds",
+			&v1beta2.ModuleStatus{
+				State: shared.StateError, Version: "1.1.0", LastReadyVersion: "1.1.0",
+				History: flappingHistory(v1beta2.MaxModuleStateTransitions),
+			},
+			shared.StateReady,
+			[]string{cloudevents.TypeModuleRecovered},
+		},
+		{"failed", &v1beta2.ModuleStatus{State: shared.StateReady}, shared.StateError,
+			[]string{cloudevents.TypeModuleFailed}},
+		{"unchanged", &v1beta2.ModuleStatus{State: shared.StateReady}, shared.StateReady, nil},
+		{"processing", nil, shared.StateProcessing, nil},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			module := newPlannedModule("evented", "1.1.0")
+			module.Manifest.Status = shared.Status{State: testCase.state}
+			kyma := builder.NewKymaBuilder().WithModule(v1beta2.Module{Name: "evented"}).Build()
+			if testCase.previous != nil {
+				testCase.previous.Name = "evented"
+				kyma.Status.Modules = []v1beta2.ModuleStatus{*testCase.previous}
+			}
+			sink := &recordingSink{}
+			ctx := adapter.ContextWithEventSink(context.Background(), sink)
+
+			sync.New(fake.NewClientBuilder().Build()).SyncModuleStatus(ctx, kyma, common.Modules{module}, nil)
+
+			var types []string
+			for _, event := range sink.events {
+				types = append(types, event.Type)
+				data, ok := event.Data.(cloudevents.ModuleData)
+				require.True(t, ok)
+				assert.Equal(t, "evented", data.Module)
+				assert.Equal(t, "1.1.0", data.Version)
+				assert.Equal(t, testCase.state, data.State)
+			}
+			assert.Equal(t, testCase.expectedTypes, types)
+			if testCase.state == shared.StateReady {
+				assert.Equal(t, "1.1.0", kyma.Status.Modules[0].LastReadyVersion)
+			}
+		})
+	}
+}
+
+func flappingHistory(length int) []v1beta2.StateTransition {
+	history := make([]v1beta2.StateTransition, 0, length)
+	for len(history) < length {
+		history = append(history,
+			v1beta2.StateTransition{From: shared.StateError, To: shared.StateProcessing, Version: "1.1.0"},
+			v1beta2.StateTransition{From: shared.StateProcessing, To: shared.StateError, Version: "1.1.0"})
+	}
+	return history
+}
+
+func TestReconcileManifests_LinksManifestToTrace(t *testing.T) {
+	t.Parallel()
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	tracedCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled,
+	}))
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	const previousTraceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	tests := []struct {
+		name                string
+		ctx                 context.Context
+		installedVersion    string
+		expectedTraceParent string
+	}{
+		{"installed module", tracedCtx, "", traceParent},
+		{"upgraded module", tracedCtx, "1.0.0", traceParent},
+		{"unchanged module", tracedCtx, "1.1.0", previousTraceParent},
+		{"tracing disabled", context.Background(), "1.0.0", ""},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			scheme := machineryruntime.NewScheme()
+			machineryutilruntime.Must(v1beta2.AddToScheme(scheme))
+			clientBuilder := fake.NewClientBuilder().WithScheme(scheme)
+			if testCase.installedVersion != "" {
+				clientBuilder = clientBuilder.WithObjects(&v1beta2.Manifest{
+					ObjectMeta: apimetav1.ObjectMeta{
+						Name:        "traced",
+						Namespace:   apimetav1.NamespaceDefault,
+						Annotations: map[string]string{shared.TraceParentAnnotation: previousTraceParent},
+					},
+					Spec:   v1beta2.ManifestSpec{Version: testCase.installedVersion},
+					Status: shared.Status{State: shared.StateReady},
+				})
+			}
+			var applied *v1beta2.Manifest
+			clnt := clientBuilder.WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, clnt client.WithWatch, obj client.Object, patch client.Patch,
+					opts ...client.PatchOption,
+				) error {
+					manifest, ok := obj.(*v1beta2.Manifest)
+					require.True(t, ok)
+					applied = manifest.DeepCopy()
+					return nil
+				},
+			}).Build()
+			module := newPlannedModule("traced", "1.1.0")
+			kyma := builder.NewKymaBuilder().WithModule(v1beta2.Module{Name: "traced"}).Build()
+
+			require.NoError(t, sync.New(clnt).ReconcileManifests(testCase.ctx, kyma, common.Modules{module}))
+
+			require.NotNil(t, applied)
+			assert.Equal(t, testCase.expectedTraceParent, applied.GetAnnotations()[shared.TraceParentAnnotation])
+		})
+	}
+}
@@ -1,45 +1,271 @@
 package remote
 
 import (
+	"container/list"
+	"context"
+	"fmt"
 	"sync"
+	"time"
 
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kyma-project/lifecycle-manager/internal/pkg/metrics"
+)
+
+const (
+	defaultProbeTimeout     = 10 * time.Second
+	defaultProbeParallelism = 10
 )
 
-func NewClientCache() *ClientCache {
-	return &ClientCache{internal: &sync.Map{}}
+// ProbeFunc checks whether the API server of the cluster behind the given Client is still answering.
+type ProbeFunc func(ctx context.Context, clnt Client) error
+
+type ClientCacheOption func(cache *ClientCache)
+
+// WithTTL drops clients from the cache once they are older than the given duration,
+// so they are recreated with fresh credentials on the next lookup. A TTL of 0 keeps clients forever.
+func WithTTL(ttl time.Duration) ClientCacheOption {
+	return func(cache *ClientCache) {
+		cache.ttl = ttl
+	}
+}
+
+// WithMaxEntries limits the number of cached clients, the least recently used clients are evicted first.
+// A maximum of 0 does not limit the cache.
+func WithMaxEntries(maxEntries int) ClientCacheOption {
+	return func(cache *ClientCache) {
+		cache.maxEntries = maxEntries
+	}
+}
+
+// WithHealthProbe probes all cached clients in the given interval once the cache is started as a Runnable,
+// and drops the clients whose API server does not answer within the timeout.
+func WithHealthProbe(interval, timeout time.Duration) ClientCacheOption {
+	return func(cache *ClientCache) {
+		cache.probeInterval = interval
+		cache.probeTimeout = timeout
+	}
+}
+
+// WithProbeFunc replaces the default health probe, which requests the server version of the cluster.
+func WithProbeFunc(probe ProbeFunc) ClientCacheOption {
+	return func(cache *ClientCache) {
+		cache.probe = probe
+	}
+}
+
+func WithClientCacheMetrics(cacheMetrics *metrics.ClientCacheMetrics) ClientCacheOption {
+	return func(cache *ClientCache) {
+		cache.metrics = cacheMetrics
+	}
+}
+
+func NewClientCache(opts ...ClientCacheOption) *ClientCache {
+	cache := &ClientCache{
+		entries:      make(map[client.ObjectKey]*list.Element),
+		lru:          list.New(),
+		probeTimeout: defaultProbeTimeout,
+		probe:        probeServerVersion,
+	}
+	for _, opt := range opts {
+		opt(cache)
+	}
+	return cache
 }
 
-// ClientCache is an optimized concurrency-safe in-memory cache based on sync.Map.
+// ClientCache is a concurrency-safe in-memory cache of Clients for remote clusters.
 // It is mainly written so that a program that needs multiple Clients in different goroutines
-// can access them without recreation. It does this by holding a concurrency-safe reference map
-// based on an access key (ClientCacheID). It is not optimized for multi-write scenarios, but rather
-// append-only cases where clients are expected to live longer than their calling goroutine.
+// can access them without recreation. It does this by holding a reference map
+// based on an access key (the key of the Kyma the remote cluster belongs to).
 //
-// It thus borrows the same optimizations from it:
-// The ClientCache type is optimized for when the entry for a given
-// key is only ever written once but read many times, as in caches that only grow.
+// Clients are dropped once they exceed their TTL, when the maximum number of entries is reached
+// (least recently used first), or when the health probe finds their API server unresponsive,
+// so clients of deleted clusters do not pile up.
 type ClientCache struct {
-	internal *sync.Map
+	mu      sync.Mutex
+	entries map[client.ObjectKey]*list.Element
+	lru     *list.List
+
+	ttl           time.Duration
+	maxEntries    int
+	probeInterval time.Duration
+	probeTimeout  time.Duration
+	probe         ProbeFunc
+	metrics       *metrics.ClientCacheMetrics
+}
+
+type clientCacheEntry struct {
+	key     client.ObjectKey
+	client  Client
+	created time.Time
 }
 
 func (cache *ClientCache) Get(key client.ObjectKey) Client {
-	value, ok := cache.internal.Load(key)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	element, ok := cache.entries[key]
 	if !ok {
+		cache.recordMiss()
 		return nil
 	}
-	clnt, ok := value.(Client)
-	if !ok {
+	entry, _ := element.Value.(*clientCacheEntry)
+	if cache.expired(entry) {
+		cache.remove(element, metrics.ClientCacheEvictionExpired)
+		cache.recordMiss()
 		return nil
 	}
-
-	return clnt
+	cache.lru.MoveToFront(element)
+	cache.recordHit()
+	return entry.client
 }
 
 func (cache *ClientCache) Set(key client.ObjectKey, value Client) {
-	cache.internal.Store(key, value)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if element, ok := cache.entries[key]; ok {
+		cache.lru.Remove(element)
+	}
+	cache.entries[key] = cache.lru.PushFront(&clientCacheEntry{key: key, client: value, created: time.Now()})
+	for cache.maxEntries > 0 && cache.lru.Len() > cache.maxEntries {
+		cache.remove(cache.lru.Back(), metrics.ClientCacheEvictionCapacity)
+	}
+	cache.recordSize()
 }
 
 func (cache *ClientCache) Del(key client.ObjectKey) {
-	cache.internal.Delete(key)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if element, ok := cache.entries[key]; ok {
+		cache.remove(element, metrics.ClientCacheEvictionInvalidated)
+	}
+}
+
+// Len returns the number of cached clients, including expired ones that were not accessed since they expired.
+func (cache *ClientCache) Len() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.lru.Len()
+}
+
+// Start runs the health probe until the context is done, so the ClientCache can be added to a manager as Runnable.
+// Expired clients are dropped in every probe cycle as well. Without a probe interval, Start only waits for the context.
+func (cache *ClientCache) Start(ctx context.Context) error {
+	if cache.probeInterval <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+	ticker := time.NewTicker(cache.probeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			cache.ProbeAll(ctx)
+		}
+	}
+}
+
+// ProbeAll drops all expired clients and probes the remaining ones, dropping those whose API server does not answer.
+func (cache *ClientCache) ProbeAll(ctx context.Context) {
+	logger := logf.FromContext(ctx).WithName("client-cache")
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, defaultProbeParallelism)
+	for _, entry := range cache.collectProbeCandidates() {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(entry *clientCacheEntry) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			probeCtx, cancel := context.WithTimeout(ctx, cache.probeTimeout)
+			defer cancel()
+			if err := cache.probe(probeCtx, entry.client); err != nil {
+				logger.V(1).Info("dropping client of unresponsive cluster", "key", entry.key, "error", err.Error())
+				cache.dropIfUnchanged(entry)
+			}
+		}(entry)
+	}
+	wg.Wait()
+}
+
+// collectProbeCandidates drops all expired clients and returns the remaining ones.
+func (cache *ClientCache) collectProbeCandidates() []*clientCacheEntry {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	candidates := make([]*clientCacheEntry, 0, cache.lru.Len())
+	for element := cache.lru.Front(); element != nil; {
+		next := element.Next()
+		entry, _ := element.Value.(*clientCacheEntry)
+		if cache.expired(entry) {
+			cache.remove(element, metrics.ClientCacheEvictionExpired)
+		} else {
+			candidates = append(candidates, entry)
+		}
+		element = next
+	}
+	return candidates
+}
+
+// dropIfUnchanged removes the entry unless the client was replaced while it was probed.
+func (cache *ClientCache) dropIfUnchanged(entry *clientCacheEntry) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if element, ok := cache.entries[entry.key]; ok && element.Value == entry {
+		cache.remove(element, metrics.ClientCacheEvictionUnhealthy)
+	}
+}
+
+func (cache *ClientCache) expired(entry *clientCacheEntry) bool {
+	return cache.ttl > 0 && time.Since(entry.created) > cache.ttl
+}
+
+func (cache *ClientCache) remove(element *list.Element, reason metrics.ClientCacheEvictionReason) {
+	entry, _ := element.Value.(*clientCacheEntry)
+	cache.lru.Remove(element)
+	delete(cache.entries, entry.key)
+	if cache.metrics != nil {
+		cache.metrics.RecordEviction(reason)
+	}
+	cache.recordSize()
+}
+
+func (cache *ClientCache) recordHit() {
+	if cache.metrics != nil {
+		cache.metrics.RecordHit()
+	}
+}
+
+func (cache *ClientCache) recordMiss() {
+	if cache.metrics != nil {
+		cache.metrics.RecordMiss()
+	}
+}
+
+func (cache *ClientCache) recordSize() {
+	if cache.metrics != nil {
+		cache.metrics.SetSize(cache.lru.Len())
+	}
+}
+
+func probeServerVersion(ctx context.Context, clnt Client) error {
+	config := rest.CopyConfig(clnt.Config())
+	if deadline, ok := ctx.Deadline(); ok {
+		config.Timeout = time.Until(deadline)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	if _, err := discoveryClient.ServerVersion(); err != nil {
+		return fmt.Errorf("failed to get server version: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,131 @@
+package remote_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/lifecycle-manager/pkg/remote"
+)
+
+var errClusterUnreachable = errors.New("cluster unreachable")
+
+func newTestClient(host string) remote.Client {
+	return remote.NewClientWithConfig(fake.NewClientBuilder().Build(), &rest.Config{Host: host})
+}
+
+func cacheKey(name string) client.ObjectKey {
+	return client.ObjectKey{Name: name, Namespace: "kcp-system"}
+}
+
+func TestClientCache_GetSet(t *testing.T) {
+	t.Parallel()
+	cache := remote.NewClientCache()
+	clnt := newTestClient("https://skr.example.com")
+
+	assert.Nil(t, cache.Get(cacheKey("kyma")))
+	cache.Set(cacheKey("kyma"), clnt)
+	assert.Equal(t, clnt, cache.Get(cacheKey("kyma")))
+
+	cache.Del(cacheKey("kyma"))
+	assert.Nil(t, cache.Get(cacheKey("kyma")))
+	assert.Zero(t, cache.Len())
+}
+
+func TestClientCache_TTL(t *testing.T) {
+	t.Parallel()
+	cache := remote.NewClientCache(remote.WithTTL(10 * time.Millisecond))
+	cache.Set(cacheKey("kyma"), newTestClient("https://skr.example.com"))
+	require.NotNil(t, cache.Get(cacheKey("kyma")))
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Nil(t, cache.Get(cacheKey("kyma")))
+	assert.Zero(t, cache.Len())
+}
+
+func TestClientCache_MaxEntries(t *testing.T) {
+	t.Parallel()
+	cache := remote.NewClientCache(remote.WithMaxEntries(2))
+	cache.Set(cacheKey("first"), newTestClient("https://first.example.com"))
+	cache.Set(cacheKey("second"), newTestClient("https://second.example.com"))
+	// access the first client, so the second one is the least recently used
+	require.NotNil(t, cache.Get(cacheKey("first")))
+	cache.Set(cacheKey("third"), newTestClient("https://third.example.com"))
+
+	assert.Equal(t, 2, cache.Len())
+	assert.NotNil(t, cache.Get(cacheKey("first")))
+	assert.Nil(t, cache.Get(cacheKey("second")))
+	assert.NotNil(t, cache.Get(cacheKey("third")))
+}
+
+func TestClientCache_ProbeAll(t *testing.T) {
+	t.Parallel()
+	healthy := newTestClient("https://healthy.example.com")
+	cache := remote.NewClientCache(remote.WithProbeFunc(func(_ context.Context, clnt remote.Client) error {
+		if clnt.Config().Host == healthy.Config().Host {
+			return nil
+		}
+		return errClusterUnreachable
+	}))
+	cache.Set(cacheKey("healthy"), healthy)
+	cache.Set(cacheKey("deleted"), newTestClient("https://deleted.example.com"))
+
+	cache.ProbeAll(context.Background())
+
+	assert.Equal(t, 1, cache.Len())
+	assert.NotNil(t, cache.Get(cacheKey("healthy")))
+	assert.Nil(t, cache.Get(cacheKey("deleted")))
+}
+
+func TestClientCache_ProbeAll_ServerVersion(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = writer.Write([]byte(`{"major":"1","minor":"29","gitVersion":"v1.29.2"}`))
+	}))
+	t.Cleanup(server.Close)
+	unresponsive := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(unresponsive.Close)
+
+	cache := remote.NewClientCache(remote.WithHealthProbe(time.Hour, time.Second))
+	cache.Set(cacheKey("healthy"), newTestClient(server.URL))
+	cache.Set(cacheKey("unresponsive"), newTestClient(unresponsive.URL))
+
+	cache.ProbeAll(context.Background())
+
+	assert.NotNil(t, cache.Get(cacheKey("healthy")))
+	assert.Nil(t, cache.Get(cacheKey("unresponsive")))
+}
+
+func TestClientCache_Start(t *testing.T) {
+	t.Parallel()
+	cache := remote.NewClientCache(
+		remote.WithHealthProbe(5*time.Millisecond, time.Second),
+		remote.WithProbeFunc(func(context.Context, remote.Client) error {
+			return errClusterUnreachable
+		}),
+	)
+	cache.Set(cacheKey("deleted"), newTestClient("https://deleted.example.com"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- cache.Start(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		return cache.Len() == 0
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
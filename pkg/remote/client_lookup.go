@@ -6,20 +6,28 @@ import (
 
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/tracing"
 )
 
 type ClientLookup struct {
 	kcp   Client
 	cache *ClientCache
 
-	strategy v1beta2.SyncStrategy
+	strategy  v1beta2.SyncStrategy
+	providers ConfigProviders
 }
 
-func NewClientLookup(kcp Client, cache *ClientCache, strategy v1beta2.SyncStrategy) *ClientLookup {
-	return &ClientLookup{kcp: kcp, cache: cache, strategy: strategy}
+// NewClientLookup creates a ClientLookup resolving the runtime cluster access with the ConfigProvider of the strategy.
+// If no providers are given, the providers created by NewConfigProviders with default options are used.
+func NewClientLookup(kcp Client, cache *ClientCache, strategy v1beta2.SyncStrategy,
+	providers ConfigProviders,
+) *ClientLookup {
+	if providers == nil {
+		providers = NewConfigProviders(kcp, ConfigProviderOptions{})
+	}
+	return &ClientLookup{kcp: kcp, cache: cache, strategy: strategy, providers: providers}
 }
 
 func (l *ClientLookup) Lookup(ctx context.Context, key client.ObjectKey) (Client, error) {
@@ -46,27 +54,12 @@ func (l *ClientLookup) Lookup(ctx context.Context, key client.ObjectKey) (Client
 }
 
 func (l *ClientLookup) restConfigFromStrategy(ctx context.Context, key client.ObjectKey) (*rest.Config, error) {
-	var err error
-	var restConfig *rest.Config
-
-	clusterClient := ClusterClient{
-		DefaultClient: l.kcp,
-		Logger:        logf.FromContext(ctx),
+	strategy := l.providers.SupportedSyncStrategy(ctx, l.strategy)
+	provider, found := l.providers[strategy]
+	if !found {
+		return nil, fmt.Errorf("%w %s for %s", ErrUnsupportedSyncStrategy, strategy, key)
 	}
-
-	switch l.strategy {
-	case v1beta2.SyncStrategyLocalClient:
-		if LocalClient != nil {
-			restConfig = LocalClient()
-		} else {
-			restConfig = l.kcp.Config()
-		}
-	case v1beta2.SyncStrategyLocalSecret:
-		fallthrough
-	default:
-		restConfig, err = clusterClient.GetRestConfigFromSecret(ctx, key.Name, key.Namespace)
-	}
-
+	restConfig, err := provider.RestConfig(ctx, key)
 	if err != nil {
 		return nil, err
 	}
@@ -75,5 +68,5 @@ func (l *ClientLookup) restConfigFromStrategy(ctx context.Context, key client.Ob
 	restConfig.QPS = l.kcp.Config().QPS
 	restConfig.Burst = l.kcp.Config().Burst
 
-	return restConfig, err
+	return tracing.InstrumentConfig(restConfig), nil
 }
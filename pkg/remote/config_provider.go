@@ -0,0 +1,218 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/oauth2"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apicorev1 "k8s.io/api/core/v1"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/transport"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+)
+
+// tokenRefreshBuffer is the time before the expiry of a minted token at which it is refreshed.
+const tokenRefreshBuffer = 1 * time.Minute
+
+var (
+	ErrUnsupportedSyncStrategy = errors.New("unsupported sync strategy")
+	ErrKubeconfigFileNotFound  = errors.New("kubeconfig file not found")
+	ErrTokenRequestFailed      = errors.New("failed to request service account token")
+)
+
+// ConfigProvider resolves the rest.Config to access the runtime cluster of the Kyma with the given key.
+type ConfigProvider interface {
+	RestConfig(ctx context.Context, key client.ObjectKey) (*rest.Config, error)
+}
+
+// ConfigProviders maps the sync strategies of Kymas to the ConfigProvider resolving their runtime cluster access.
+type ConfigProviders map[v1beta2.SyncStrategy]ConfigProvider
+
+// ConfigProviderOptions configures the ConfigProviders created by NewConfigProviders.
+type ConfigProviderOptions struct {
+	// KubeconfigDir is the directory with a kubeconfig file per Kyma, named after the Kyma.
+	KubeconfigDir string
+	// TokenDir is the directory with a bearer token file per Kyma, named after the Kyma.
+	TokenDir string
+	// TokenAudiences are the audiences of minted ServiceAccount tokens.
+	TokenAudiences []string
+	// TokenExpiration is the requested lifetime of minted ServiceAccount tokens.
+	TokenExpiration time.Duration
+}
+
+// NewConfigProviders returns a ConfigProvider for every supported sync strategy.
+func NewConfigProviders(kcp Client, opts ConfigProviderOptions) ConfigProviders {
+	return ConfigProviders{
+		v1beta2.SyncStrategyLocalSecret:    &SecretConfigProvider{Client: kcp},
+		v1beta2.SyncStrategyLocalClient:    &LocalConfigProvider{Config: kcp.Config()},
+		v1beta2.SyncStrategyKubeconfigFile: &KubeconfigFileConfigProvider{Dir: opts.KubeconfigDir},
+		v1beta2.SyncStrategyTokenFile:      &TokenFileConfigProvider{Client: kcp, Dir: opts.TokenDir},
+		v1beta2.SyncStrategyServiceAccountToken: &ServiceAccountTokenConfigProvider{
+			Client:     kcp,
+			Audiences:  opts.TokenAudiences,
+			Expiration: opts.TokenExpiration,
+		},
+	}
+}
+
+// SupportedSyncStrategy returns the strategy if a ConfigProvider exists for it. Other strategies fall back to
+// SyncStrategyLocalSecret, which was used for every strategy before ConfigProviders were introduced, so Kymas
+// with legacy or misspelled strategies keep their runtime cluster access.
+func (p ConfigProviders) SupportedSyncStrategy(ctx context.Context, strategy v1beta2.SyncStrategy,
+) v1beta2.SyncStrategy {
+	if _, found := p[strategy]; found {
+		return strategy
+	}
+	logf.FromContext(ctx).Info("unsupported sync strategy, falling back to the kubeconfig secret",
+		"strategy", strategy, "fallback", v1beta2.SyncStrategyLocalSecret)
+	return v1beta2.SyncStrategyLocalSecret
+}
+
+// SyncStrategyFor returns the sync strategy of the Kyma set with the sync-strategy annotation,
+// defaulting to SyncStrategyLocalSecret.
+func SyncStrategyFor(kyma *v1beta2.Kyma) v1beta2.SyncStrategy {
+	if strategy, found := kyma.GetAnnotations()[shared.SyncStrategyAnnotation]; found && strategy != "" {
+		return v1beta2.SyncStrategy(strategy)
+	}
+	return v1beta2.SyncStrategyLocalSecret
+}
+
+// SecretConfigProvider reads the kubeconfig from the Secret labeled with the name of the Kyma.
+type SecretConfigProvider struct {
+	Client client.Client
+}
+
+func (p *SecretConfigProvider) RestConfig(ctx context.Context, key client.ObjectKey) (*rest.Config, error) {
+	clusterClient := ClusterClient{DefaultClient: p.Client, Logger: logf.FromContext(ctx)}
+	return clusterClient.GetRestConfigFromSecret(ctx, key.Name, key.Namespace)
+}
+
+// LocalConfigProvider uses the given rest.Config for every Kyma, so the runtime is the control plane itself.
+// LocalClient takes precedence if it is set.
+type LocalConfigProvider struct {
+	Config *rest.Config
+}
+
+func (p *LocalConfigProvider) RestConfig(_ context.Context, _ client.ObjectKey) (*rest.Config, error) {
+	if LocalClient != nil {
+		return LocalClient(), nil
+	}
+	return rest.CopyConfig(p.Config), nil
+}
+
+// KubeconfigFileConfigProvider reads the kubeconfig from a file named after the Kyma, e.g. mounted by the platform.
+// Exec plugins and token files referenced by the kubeconfig are resolved relative to the file
+// and are re-evaluated by client-go whenever the credentials expire.
+type KubeconfigFileConfigProvider struct {
+	Dir string
+}
+
+func (p *KubeconfigFileConfigProvider) RestConfig(_ context.Context, key client.ObjectKey) (*rest.Config, error) {
+	path := filepath.Join(p.Dir, key.Name)
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrKubeconfigFileNotFound, path)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig file %s: %w", path, err)
+	}
+	restConfig, err := clientcmd.BuildConfigFromFlags("", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rest config from kubeconfig file %s: %w", path, err)
+	}
+	return restConfig, nil
+}
+
+// TokenFileConfigProvider takes the cluster endpoint from the Secret labeled with the name of the Kyma
+// and authenticates with the bearer token in a file named after the Kyma.
+// client-go re-reads the file periodically, so the token can be rotated without a restart.
+type TokenFileConfigProvider struct {
+	Client client.Client
+	Dir    string
+}
+
+func (p *TokenFileConfigProvider) RestConfig(ctx context.Context, key client.ObjectKey) (*rest.Config, error) {
+	restConfig, err := clusterConfigFromSecret(ctx, p.Client, key)
+	if err != nil {
+		return nil, err
+	}
+	restConfig.BearerTokenFile = filepath.Join(p.Dir, key.Name)
+	return restConfig, nil
+}
+
+// ServiceAccountTokenConfigProvider takes the cluster endpoint from the Secret labeled with the name of the Kyma
+// and authenticates with a short-lived token of the ServiceAccount with the same name and namespace as the Kyma.
+// The token is minted with the TokenRequest API of the control plane and refreshed before it expires,
+// so the runtime cluster has to trust the service account issuer of the control plane.
+type ServiceAccountTokenConfigProvider struct {
+	Client     client.Client
+	Audiences  []string
+	Expiration time.Duration
+}
+
+func (p *ServiceAccountTokenConfigProvider) RestConfig(ctx context.Context,
+	key client.ObjectKey,
+) (*rest.Config, error) {
+	restConfig, err := clusterConfigFromSecret(ctx, p.Client, key)
+	if err != nil {
+		return nil, err
+	}
+	source := &serviceAccountTokenSource{provider: p, key: key}
+	// mint the first token right away, so missing permissions or ServiceAccounts are reported on lookup
+	token, err := source.Token()
+	if err != nil {
+		return nil, err
+	}
+	restConfig.WrapTransport = transport.TokenSourceWrapTransport(
+		oauth2.ReuseTokenSourceWithExpiry(token, source, tokenRefreshBuffer))
+	return restConfig, nil
+}
+
+type serviceAccountTokenSource struct {
+	provider *ServiceAccountTokenConfigProvider
+	key      client.ObjectKey
+}
+
+func (s *serviceAccountTokenSource) Token() (*oauth2.Token, error) {
+	serviceAccount := &apicorev1.ServiceAccount{
+		ObjectMeta: apimetav1.ObjectMeta{Name: s.key.Name, Namespace: s.key.Namespace},
+	}
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{Audiences: s.provider.Audiences},
+	}
+	if s.provider.Expiration > 0 {
+		expirationSeconds := int64(s.provider.Expiration.Seconds())
+		tokenRequest.Spec.ExpirationSeconds = &expirationSeconds
+	}
+	// the token source interface has no context, the request is bound by the timeout of the client
+	if err := s.provider.Client.SubResource("token").Create(context.Background(), serviceAccount,
+		tokenRequest); err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrTokenRequestFailed, s.key, err)
+	}
+	return &oauth2.Token{
+		AccessToken: tokenRequest.Status.Token,
+		TokenType:   "Bearer",
+		Expiry:      tokenRequest.Status.ExpirationTimestamp.Time,
+	}, nil
+}
+
+// clusterConfigFromSecret reads the kubeconfig from the Secret labeled with the name of the Kyma
+// and drops all credentials, so only the endpoint and the CA of the cluster are used.
+func clusterConfigFromSecret(ctx context.Context, clnt client.Client, key client.ObjectKey) (*rest.Config, error) {
+	clusterClient := ClusterClient{DefaultClient: clnt, Logger: logf.FromContext(ctx)}
+	restConfig, err := clusterClient.GetRestConfigFromSecret(ctx, key.Name, key.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return rest.AnonymousClientConfig(restConfig), nil
+}
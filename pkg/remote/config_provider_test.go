@@ -0,0 +1,176 @@
+package remote_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apicorev1 "k8s.io/api/core/v1"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/remote"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: skr
+  cluster:
+    server: %s
+contexts:
+- name: skr
+  context:
+    cluster: skr
+    user: admin
+current-context: skr
+users:
+- name: admin
+  user:
+    token: static-admin-token
+`
+
+var testKymaKey = client.ObjectKey{Name: "kyma-sample", Namespace: "kcp-system"}
+
+func kubeconfigSecret(server string) *apicorev1.Secret {
+	return &apicorev1.Secret{
+		ObjectMeta: apimetav1.ObjectMeta{
+			Name:      testKymaKey.Name,
+			Namespace: testKymaKey.Namespace,
+			Labels:    map[string]string{shared.KymaName: testKymaKey.Name},
+		},
+		Data: map[string][]byte{remote.KubeConfigKey: []byte(fmt.Sprintf(testKubeconfig, server))},
+	}
+}
+
+func TestSyncStrategyFor(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    v1beta2.SyncStrategy
+	}{
+		{"no annotation", nil, v1beta2.SyncStrategyLocalSecret},
+		{"empty annotation", map[string]string{shared.SyncStrategyAnnotation: ""}, v1beta2.SyncStrategyLocalSecret},
+		{
+			"kubeconfig-file annotation",
+			map[string]string{shared.SyncStrategyAnnotation: v1beta2.SyncStrategyKubeconfigFile},
+			v1beta2.SyncStrategyKubeconfigFile,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			kyma := &v1beta2.Kyma{}
+			kyma.SetAnnotations(testCase.annotations)
+			assert.Equal(t, testCase.expected, remote.SyncStrategyFor(kyma))
+		})
+	}
+}
+
+func TestKubeconfigFileConfigProvider(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, testKymaKey.Name),
+		[]byte(fmt.Sprintf(testKubeconfig, "https://skr.example.com")), 0o600))
+	provider := &remote.KubeconfigFileConfigProvider{Dir: dir}
+
+	restConfig, err := provider.RestConfig(context.Background(), testKymaKey)
+	require.NoError(t, err)
+	assert.Equal(t, "https://skr.example.com", restConfig.Host)
+	assert.Equal(t, "static-admin-token", restConfig.BearerToken)
+
+	_, err = provider.RestConfig(context.Background(), client.ObjectKey{Name: "unknown", Namespace: "kcp-system"})
+	require.ErrorIs(t, err, remote.ErrKubeconfigFileNotFound)
+}
+
+func TestTokenFileConfigProvider(t *testing.T) {
+	t.Parallel()
+	clnt := fake.NewClientBuilder().WithObjects(kubeconfigSecret("https://skr.example.com")).Build()
+	provider := &remote.TokenFileConfigProvider{Client: clnt, Dir: "/var/run/secrets/skr/tokens"}
+
+	restConfig, err := provider.RestConfig(context.Background(), testKymaKey)
+	require.NoError(t, err)
+	assert.Equal(t, "https://skr.example.com", restConfig.Host)
+	assert.Empty(t, restConfig.BearerToken)
+	assert.Equal(t, "/var/run/secrets/skr/tokens/kyma-sample", restConfig.BearerTokenFile)
+}
+
+func TestServiceAccountTokenConfigProvider(t *testing.T) {
+	t.Parallel()
+	authorization := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		authorization <- request.Header.Get("Authorization")
+		writer.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	var requested *authenticationv1.TokenRequest
+	clnt := fake.NewClientBuilder().WithObjects(kubeconfigSecret(server.URL)).WithInterceptorFuncs(
+		interceptor.Funcs{SubResourceCreate: func(_ context.Context, _ client.Client, subResourceName string,
+			obj client.Object, subResource client.Object, _ ...client.SubResourceCreateOption,
+		) error {
+			require.Equal(t, "token", subResourceName)
+			require.Equal(t, testKymaKey, client.ObjectKeyFromObject(obj))
+			requested, _ = subResource.(*authenticationv1.TokenRequest)
+			requested.Status = authenticationv1.TokenRequestStatus{
+				Token:               "minted-token",
+				ExpirationTimestamp: apimetav1.NewTime(time.Now().Add(time.Hour)),
+			}
+			return nil
+		}},
+	).Build()
+	provider := &remote.ServiceAccountTokenConfigProvider{
+		Client:     clnt,
+		Audiences:  []string{"skr"},
+		Expiration: time.Hour,
+	}
+
+	restConfig, err := provider.RestConfig(context.Background(), testKymaKey)
+	require.NoError(t, err)
+	require.NotNil(t, requested)
+	assert.Equal(t, []string{"skr"}, requested.Spec.Audiences)
+	assert.Equal(t, int64(3600), *requested.Spec.ExpirationSeconds)
+
+	httpClient, err := rest.HTTPClientFor(restConfig)
+	require.NoError(t, err)
+	response, err := httpClient.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+	assert.Equal(t, "Bearer minted-token", <-authorization)
+}
+
+func TestClientLookup_UnsupportedSyncStrategyFallsBackToSecret(t *testing.T) {
+	t.Parallel()
+	kcp := remote.NewClientWithConfig(
+		fake.NewClientBuilder().WithObjects(kubeconfigSecret("https://skr.example.com")).Build(), &rest.Config{})
+
+	skr, err := remote.NewClientLookup(kcp, remote.NewClientCache(), "unknown", nil).
+		Lookup(context.Background(), testKymaKey)
+	require.NoError(t, err)
+	assert.Equal(t, "https://skr.example.com", skr.Config().Host)
+}
+
+func TestClientLookup_UnsupportedSyncStrategyWithoutSecretProvider(t *testing.T) {
+	t.Parallel()
+	kcp := remote.NewClientWithConfig(fake.NewClientBuilder().Build(), &rest.Config{})
+	providers := remote.ConfigProviders{v1beta2.SyncStrategyLocalClient: &remote.LocalConfigProvider{}}
+
+	_, err := remote.NewClientLookup(kcp, remote.NewClientCache(), "unknown", providers).
+		Lookup(context.Background(), testKymaKey)
+	require.ErrorIs(t, err, remote.ErrUnsupportedSyncStrategy)
+}
@@ -14,9 +14,9 @@ type syncContextKey = struct{}
 var ErrIsNoSyncContext = errors.New("the given value is not a pointer to a kyma synchronization context")
 
 func InitializeSyncContext(ctx context.Context, kyma *v1beta2.Kyma,
-	syncNamespace string, kcp Client, cache *ClientCache,
+	syncNamespace string, kcp Client, cache *ClientCache, providers ConfigProviders,
 ) (context.Context, error) {
-	syncContext, err := InitializeKymaSynchronizationContext(ctx, kcp, cache, kyma, syncNamespace)
+	syncContext, err := InitializeKymaSynchronizationContext(ctx, kcp, cache, providers, kyma, syncNamespace)
 	if err != nil {
 		return nil, fmt.Errorf("initializing sync context failed: %w", err)
 	}
@@ -36,14 +36,9 @@ type KymaSynchronizationContext struct {
 }
 
 func InitializeKymaSynchronizationContext(ctx context.Context, kcp Client, cache *ClientCache,
-	kyma *v1beta2.Kyma, syncNamespace string,
+	providers ConfigProviders, kyma *v1beta2.Kyma, syncNamespace string,
 ) (*KymaSynchronizationContext, error) {
-	strategyValue, found := kyma.Annotations[shared.SyncStrategyAnnotation]
-	syncStrategy := v1beta2.SyncStrategyLocalSecret
-	if found && strategyValue == v1beta2.SyncStrategyLocalClient {
-		syncStrategy = v1beta2.SyncStrategyLocalClient
-	}
-	skr, err := NewClientLookup(kcp, cache, v1beta2.SyncStrategy(syncStrategy)).
+	skr, err := NewClientLookup(kcp, cache, SyncStrategyFor(kyma), providers).
 		Lookup(ctx, client.ObjectKeyFromObject(kyma))
 	if err != nil {
 		return nil, err
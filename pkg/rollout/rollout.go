@@ -0,0 +1,202 @@
+package rollout
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/Masterminds/semver/v3"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+)
+
+const (
+	percent = 100
+	// NoWave is returned by WaveOf for Kymas that match no wave of a rollout.
+	NoWave = -1
+)
+
+// Applies reports whether the rollout governs the upgrade of the module to the given version in the given channel.
+func Applies(rollout *v1beta2.ModuleRollout, moduleName, channel, version string) bool {
+	return rollout.Spec.Module == moduleName && rollout.Spec.Version == version &&
+		(rollout.Spec.Channel == "" || rollout.Spec.Channel == channel)
+}
+
+// IsCompleted reports whether all waves of the current version of the rollout are released.
+func IsCompleted(rollout *v1beta2.ModuleRollout) bool {
+	return rollout.Status.Version == rollout.Spec.Version && rollout.Status.State == shared.StateReady
+}
+
+// ReleasedWaves returns the number of waves released for the current version of the rollout.
+// The first wave is released as soon as the rollout exists.
+func ReleasedWaves(rollout *v1beta2.ModuleRollout) int {
+	if rollout.Status.Version != rollout.Spec.Version {
+		return 1
+	}
+	return rollout.Status.CurrentWave + 1
+}
+
+// IsReleased reports whether the version of the rollout is released to the Kyma.
+func IsReleased(rollout *v1beta2.ModuleRollout, kyma *v1beta2.Kyma) (bool, error) {
+	if IsCompleted(rollout) {
+		return true, nil
+	}
+	wave, err := WaveOf(rollout, kyma)
+	if err != nil {
+		return false, err
+	}
+	return wave != NoWave && wave < ReleasedWaves(rollout), nil
+}
+
+// WaveOf returns the index of the first wave of the rollout the Kyma belongs to, or NoWave.
+func WaveOf(rollout *v1beta2.ModuleRollout, kyma *v1beta2.Kyma) (int, error) {
+	for idx, wave := range rollout.Spec.Waves {
+		matches, err := inWave(wave, kyma)
+		if err != nil {
+			return NoWave, fmt.Errorf("invalid wave %s of rollout %s: %w", wave.Name, rollout.Name, err)
+		}
+		if matches {
+			return idx, nil
+		}
+	}
+	return NoWave, nil
+}
+
+func inWave(wave v1beta2.RolloutWave, kyma *v1beta2.Kyma) (bool, error) {
+	if wave.Selector != nil {
+		selector, err := apimetav1.LabelSelectorAsSelector(wave.Selector)
+		if err != nil {
+			return false, fmt.Errorf("failed to parse selector: %w", err)
+		}
+		if !selector.Empty() && selector.Matches(k8slabels.Set(kyma.GetLabels())) {
+			return true, nil
+		}
+	}
+	return wave.Percentage > 0 && bucket(kyma) < wave.Percentage, nil
+}
+
+// bucket assigns the Kyma to one of 100 buckets based on a stable hash of its name,
+// so every Kyma stays in the same percentage range across rollouts.
+func bucket(kyma *v1beta2.Kyma) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(kyma.GetNamespace() + "/" + kyma.GetName()))
+	return int(hash.Sum32() % percent)
+}
+
+// Progress calculates the progress of every wave and the error rate of the rollout across the given Kymas.
+// Only Kymas with the module in their status that the rollout targets are considered, so Kymas that never
+// upgrade to the version of the rollout do not hold back a wave.
+func Progress(rollout *v1beta2.ModuleRollout, kymas []v1beta2.Kyma) ([]v1beta2.RolloutWaveStatus, int, error) {
+	waves := make([]v1beta2.RolloutWaveStatus, len(rollout.Spec.Waves))
+	for idx, wave := range rollout.Spec.Waves {
+		waves[idx].Name = wave.Name
+	}
+	attempted, failed := 0, 0
+	for idx := range kymas {
+		kyma := &kymas[idx]
+		moduleStatus := findModuleStatus(kyma, rollout.Spec.Module)
+		if moduleStatus == nil || !targets(rollout, kyma, moduleStatus) {
+			continue
+		}
+		wave, err := WaveOf(rollout, kyma)
+		if err != nil {
+			return nil, 0, err
+		}
+		outcome := outcomeOf(rollout, moduleStatus)
+		if outcome.attempted {
+			attempted++
+		}
+		if outcome.failed {
+			failed++
+		}
+		if wave == NoWave {
+			continue
+		}
+		waves[wave].Kymas++
+		if outcome.upgraded {
+			waves[wave].Upgraded++
+		}
+		if outcome.failed {
+			waves[wave].Failed++
+		}
+		if outcome.deferred {
+			waves[wave].Deferred++
+		}
+	}
+	errorRate := 0
+	if attempted > 0 {
+		errorRate = failed * percent / attempted
+	}
+	return waves, errorRate, nil
+}
+
+// targets reports whether the rollout can upgrade the module of the Kyma. The module has to be installed from
+// the channel of the rollout, and its version constraint, if any, has to allow the version of the rollout.
+func targets(rollout *v1beta2.ModuleRollout, kyma *v1beta2.Kyma, moduleStatus *v1beta2.ModuleStatus) bool {
+	if rollout.Spec.Channel != "" && moduleStatus.Channel != rollout.Spec.Channel {
+		return false
+	}
+	module := findModule(kyma, rollout.Spec.Module)
+	if module == nil {
+		return false
+	}
+	if module.Version == "" {
+		return true
+	}
+	constraint, err := semver.NewConstraint(module.Version)
+	if err != nil {
+		return false
+	}
+	version, err := semver.NewVersion(rollout.Spec.Version)
+	return err == nil && constraint.Check(version)
+}
+
+// upgradeOutcome classifies the upgrade of the module of a Kyma to the version of a rollout.
+type upgradeOutcome struct {
+	// attempted is set if the module runs the version or the upgrade to it was rolled back.
+	attempted bool
+	// upgraded is set if the module runs the version and is Ready.
+	upgraded bool
+	// failed is set if the module runs the version and is in the Error state, or if the upgrade was rolled back.
+	failed bool
+	// deferred is set if the released upgrade waits for the next maintenance window of the Kyma.
+	deferred bool
+}
+
+func outcomeOf(rollout *v1beta2.ModuleRollout, moduleStatus *v1beta2.ModuleStatus) upgradeOutcome {
+	if moduleStatus.Version == rollout.Spec.Version {
+		return upgradeOutcome{
+			attempted: true,
+			upgraded:  moduleStatus.State == shared.StateReady,
+			failed:    moduleStatus.State == shared.StateError,
+		}
+	}
+	if moduleStatus.FailedUpgrade != nil && moduleStatus.FailedUpgrade.Version == rollout.Spec.Version {
+		return upgradeOutcome{attempted: true, failed: true}
+	}
+	deferredUpgrade := moduleStatus.DeferredUpgrade
+	return upgradeOutcome{
+		deferred: deferredUpgrade != nil && deferredUpgrade.Version == rollout.Spec.Version &&
+			deferredUpgrade.NextWindow != nil && deferredUpgrade.Rollout == "",
+	}
+}
+
+func findModuleStatus(kyma *v1beta2.Kyma, moduleName string) *v1beta2.ModuleStatus {
+	for idx := range kyma.Status.Modules {
+		if kyma.Status.Modules[idx].Name == moduleName {
+			return &kyma.Status.Modules[idx]
+		}
+	}
+	return nil
+}
+
+func findModule(kyma *v1beta2.Kyma, moduleName string) *v1beta2.Module {
+	for idx := range kyma.Spec.Modules {
+		if kyma.Spec.Modules[idx].Name == moduleName {
+			return &kyma.Spec.Modules[idx]
+		}
+	}
+	return nil
+}
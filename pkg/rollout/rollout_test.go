@@ -0,0 +1,189 @@
+package rollout_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/rollout"
+)
+
+const (
+	moduleName = "test-module"
+	version    = "1.1.0"
+)
+
+func testRollout() *v1beta2.ModuleRollout {
+	return &v1beta2.ModuleRollout{
+		ObjectMeta: apimetav1.ObjectMeta{Name: "test-rollout"},
+		Spec: v1beta2.ModuleRolloutSpec{
+			Module:  moduleName,
+			Version: version,
+			Waves: []v1beta2.RolloutWave{
+				{
+					Name:     "canary",
+					Selector: &apimetav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+				},
+				{Name: "everyone", Percentage: 100},
+			},
+		},
+		Status: v1beta2.ModuleRolloutStatus{Version: version},
+	}
+}
+
+func testKyma(name string, labels map[string]string, moduleVersion string, state shared.State) v1beta2.Kyma {
+	kyma := v1beta2.Kyma{ObjectMeta: apimetav1.ObjectMeta{Name: name, Namespace: "kcp-system", Labels: labels}}
+	if moduleVersion != "" {
+		kyma.Spec.Modules = []v1beta2.Module{{Name: moduleName}}
+		kyma.Status.Modules = []v1beta2.ModuleStatus{{Name: moduleName, Version: moduleVersion, State: state}}
+	}
+	return kyma
+}
+
+func TestWaveOf(t *testing.T) {
+	t.Parallel()
+	canary := testKyma("canary", map[string]string{"canary": "true"}, "", "")
+	other := testKyma("other", nil, "", "")
+
+	wave, err := rollout.WaveOf(testRollout(), &canary)
+	require.NoError(t, err)
+	assert.Equal(t, 0, wave)
+
+	wave, err = rollout.WaveOf(testRollout(), &other)
+	require.NoError(t, err)
+	assert.Equal(t, 1, wave)
+
+	selectorOnly := testRollout()
+	selectorOnly.Spec.Waves = selectorOnly.Spec.Waves[:1]
+	wave, err = rollout.WaveOf(selectorOnly, &other)
+	require.NoError(t, err)
+	assert.Equal(t, rollout.NoWave, wave)
+
+	invalid := testRollout()
+	invalid.Spec.Waves[0].Selector.MatchLabels = map[string]string{"in valid": "true"}
+	_, err = rollout.WaveOf(invalid, &other)
+	require.Error(t, err)
+}
+
+func TestIsReleased(t *testing.T) {
+	t.Parallel()
+	canary := testKyma("canary", map[string]string{"canary": "true"}, "", "")
+	other := testKyma("other", nil, "", "")
+
+	staleStatus := testRollout()
+	staleStatus.Status = v1beta2.ModuleRolloutStatus{Version: "1.0.0", CurrentWave: 1}
+	secondWave := testRollout()
+	secondWave.Status.CurrentWave = 1
+	completed := testRollout()
+	completed.Spec.Waves = completed.Spec.Waves[:1]
+	completed.Status.State = shared.StateReady
+
+	tests := []struct {
+		name     string
+		rollout  *v1beta2.ModuleRollout
+		kyma     *v1beta2.Kyma
+		expected bool
+	}{
+		{"first wave is released", testRollout(), &canary, true},
+		{"second wave is not yet released", testRollout(), &other, false},
+		{"stale status releases only first wave", staleStatus, &other, false},
+		{"second wave is released", secondWave, &other, true},
+		{"completed rollout releases kymas of no wave", completed, &other, true},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			released, err := rollout.IsReleased(testCase.rollout, testCase.kyma)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, released)
+		})
+	}
+}
+
+func TestProgress(t *testing.T) {
+	t.Parallel()
+	canaryLabels := map[string]string{"canary": "true"}
+	kymas := []v1beta2.Kyma{
+		testKyma("canary-ready", canaryLabels, version, shared.StateReady),
+		testKyma("canary-error", canaryLabels, version, shared.StateError),
+		testKyma("canary-old", canaryLabels, "1.0.0", shared.StateReady),
+		testKyma("other-ready", nil, version, shared.StateReady),
+		testKyma("other-old", nil, "1.0.0", shared.StateError),
+		testKyma("without-module", nil, "", ""),
+	}
+
+	waves, errorRate, err := rollout.Progress(testRollout(), kymas)
+	require.NoError(t, err)
+	assert.Equal(t, []v1beta2.RolloutWaveStatus{
+		{Name: "canary", Kymas: 3, Upgraded: 1, Failed: 1},
+		{Name: "everyone", Kymas: 2, Upgraded: 1},
+	}, waves)
+	assert.Equal(t, 33, errorRate)
+}
+
+func TestProgress_RolledBackAndDeferredKymas(t *testing.T) {
+	t.Parallel()
+	canaryLabels := map[string]string{"canary": "true"}
+	rolledBack := testKyma("canary-rolled-back", canaryLabels, "1.0.0", shared.StateReady)
+	rolledBack.Status.Modules[0].FailedUpgrade = &v1beta2.FailedUpgrade{Version: version, RolledBackTo: "1.0.0"}
+	inWindow := testKyma("canary-in-window", canaryLabels, "1.0.0", shared.StateReady)
+	inWindow.Status.Modules[0].DeferredUpgrade = &v1beta2.DeferredUpgrade{
+		Version: version, NextWindow: &apimetav1.Time{Time: time.Now().Add(time.Hour)},
+	}
+	notReleased := testKyma("other-not-released", nil, "1.0.0", shared.StateReady)
+	notReleased.Status.Modules[0].DeferredUpgrade = &v1beta2.DeferredUpgrade{
+		Version: version, NextWindow: &apimetav1.Time{Time: time.Now().Add(time.Hour)}, Rollout: "test-rollout",
+	}
+	kymas := []v1beta2.Kyma{
+		testKyma("canary-ready", canaryLabels, version, shared.StateReady),
+		rolledBack,
+		inWindow,
+		notReleased,
+	}
+
+	waves, errorRate, err := rollout.Progress(testRollout(), kymas)
+	require.NoError(t, err)
+	assert.Equal(t, []v1beta2.RolloutWaveStatus{
+		{Name: "canary", Kymas: 3, Upgraded: 1, Failed: 1, Deferred: 1},
+		{Name: "everyone", Kymas: 1},
+	}, waves)
+	assert.Equal(t, 50, errorRate)
+}
+
+func TestProgress_MixedChannelsAndPinnedVersions(t *testing.T) {
+	t.Parallel()
+	inChannel := func(kyma v1beta2.Kyma, channel string) v1beta2.Kyma {
+		kyma.Status.Modules[0].Channel = channel
+		return kyma
+	}
+	pinned := func(kyma v1beta2.Kyma, constraint string) v1beta2.Kyma {
+		kyma.Spec.Modules[0].Version = constraint
+		return kyma
+	}
+	removed := testKyma("fast-removed", nil, "1.0.0", shared.StateReady)
+	removed.Spec.Modules = nil
+	kymas := []v1beta2.Kyma{
+		inChannel(testKyma("fast-ready", nil, version, shared.StateReady), "fast"),
+		inChannel(testKyma("fast-old", nil, "1.0.0", shared.StateReady), "fast"),
+		inChannel(testKyma("regular-old", nil, "1.0.0", shared.StateReady), "regular"),
+		inChannel(pinned(testKyma("fast-pinned", nil, "1.0.0", shared.StateReady), "1.0.x"), "fast"),
+		inChannel(pinned(testKyma("fast-pinned-allowed", nil, "1.0.0", shared.StateReady), ">=1.0.0"), "fast"),
+		inChannel(removed, "fast"),
+	}
+	channelRollout := testRollout()
+	channelRollout.Spec.Channel = "fast"
+
+	waves, errorRate, err := rollout.Progress(channelRollout, kymas)
+	require.NoError(t, err)
+	assert.Equal(t, []v1beta2.RolloutWaveStatus{
+		{Name: "canary"},
+		{Name: "everyone", Kymas: 3, Upgraded: 1},
+	}, waves)
+	assert.Zero(t, errorRate)
+}
@@ -0,0 +1,202 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	containerregistryv1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/kyma-project/lifecycle-manager/pkg/ocmextensions"
+)
+
+const (
+	// CosignSignatureAnnotation is the annotation of a cosign signature layer containing the base64 signature.
+	CosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	cosignSignatureTagSuffix  = ".sig"
+	insecureScheme            = "http://"
+)
+
+var (
+	ErrNoCosignSignature    = errors.New("no valid cosign signature found")
+	ErrLayerNotInArtifact   = errors.New("layer is not part of the signed artifact")
+	ErrUnsupportedPublicKey = errors.New("unsupported public key type")
+)
+
+// cosignPayload is the simple signing payload signed by cosign.
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// VerifyCosignLayer verifies that the artifact referenced by artifactRef is signed with cosign by one of the
+// public keys and that the layer with the given digest is part of this artifact. As layers are pulled by their
+// digest, this ensures that the content of the layer is covered by the signature.
+// Signatures are looked up with the cosign tag convention (sha256-<digest>.sig), transparency logs are not used.
+func VerifyCosignLayer(ctx context.Context,
+	artifactRef, layerDigest string,
+	keyChain authn.Keychain,
+	keys []crypto.PublicKey,
+) error {
+	options := craneOptions(ctx, artifactRef, keyChain)
+	artifactRef = ocmextensions.NoSchemeURL(artifactRef)
+
+	rawManifest, err := crane.Manifest(artifactRef, options...)
+	if err != nil {
+		return fmt.Errorf("%w: failed to fetch artifact %s: %w", ErrVerificationFailed, artifactRef, err)
+	}
+	manifest, err := containerregistryv1.ParseManifest(bytes.NewReader(rawManifest))
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse artifact %s: %w", ErrVerificationFailed, artifactRef, err)
+	}
+	if !containsLayer(manifest, layerDigest) {
+		return fmt.Errorf("%w: %w: %s in %s", ErrVerificationFailed, ErrLayerNotInArtifact, layerDigest, artifactRef)
+	}
+
+	digest, _, err := containerregistryv1.SHA256(bytes.NewReader(rawManifest))
+	if err != nil {
+		return fmt.Errorf("%w: failed to calculate digest of %s: %w", ErrVerificationFailed, artifactRef, err)
+	}
+	if err := verifyCosignSignatures(artifactRef, digest, options, keys); err != nil {
+		return fmt.Errorf("%w: artifact %s: %w", ErrVerificationFailed, artifactRef, err)
+	}
+	return nil
+}
+
+func verifyCosignSignatures(artifactRef string, digest containerregistryv1.Hash,
+	options []crane.Option, keys []crypto.PublicKey,
+) error {
+	signatureRef := fmt.Sprintf("%s:%s-%s%s", repository(artifactRef), digest.Algorithm, digest.Hex,
+		cosignSignatureTagSuffix)
+	signatureImage, err := crane.Pull(signatureRef, options...)
+	if err != nil {
+		return fmt.Errorf("failed to pull signature %s: %w", signatureRef, err)
+	}
+	signatureManifest, err := signatureImage.Manifest()
+	if err != nil {
+		return fmt.Errorf("failed to read signature manifest %s: %w", signatureRef, err)
+	}
+
+	for _, layer := range signatureManifest.Layers {
+		encodedSignature, found := layer.Annotations[CosignSignatureAnnotation]
+		if !found {
+			continue
+		}
+		payload, err := readLayer(signatureImage, layer.Digest)
+		if err != nil {
+			return err
+		}
+		if verifyCosignPayload(payload, encodedSignature, digest, keys) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w in %s", ErrNoCosignSignature, signatureRef)
+}
+
+func verifyCosignPayload(payload []byte, encodedSignature string, digest containerregistryv1.Hash,
+	keys []crypto.PublicKey,
+) bool {
+	var signedPayload cosignPayload
+	if err := json.Unmarshal(payload, &signedPayload); err != nil {
+		return false
+	}
+	if signedPayload.Critical.Image.DockerManifestDigest != digest.String() {
+		return false
+	}
+	signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return false
+	}
+	for _, key := range keys {
+		if err := verifyWithKey(key, payload, signature); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func verifyWithKey(key crypto.PublicKey, payload, signature []byte) error {
+	hash := sha256.Sum256(payload)
+	switch typedKey := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(typedKey, hash[:], signature) {
+			return ErrVerificationFailed
+		}
+		return nil
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(typedKey, crypto.SHA256, hash[:], signature); err != nil {
+			return fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+		}
+		return nil
+	case ed25519.PublicKey:
+		if !ed25519.Verify(typedKey, payload, signature) {
+			return ErrVerificationFailed
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedPublicKey, key)
+	}
+}
+
+func readLayer(image containerregistryv1.Image, digest containerregistryv1.Hash) ([]byte, error) {
+	layer, err := image.LayerByDigest(digest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signature layer %s: %w", digest, err)
+	}
+	reader, err := layer.Compressed()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature layer %s: %w", digest, err)
+	}
+	defer reader.Close()
+	payload, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature layer %s: %w", digest, err)
+	}
+	return payload, nil
+}
+
+func containsLayer(manifest *containerregistryv1.Manifest, layerDigest string) bool {
+	if manifest.Config.Digest.String() == layerDigest {
+		return true
+	}
+	for _, layer := range manifest.Layers {
+		if layer.Digest.String() == layerDigest {
+			return true
+		}
+	}
+	return false
+}
+
+// repository strips the tag or digest from the reference.
+func repository(ref string) string {
+	if idx := strings.Index(ref, "@"); idx >= 0 {
+		return ref[:idx]
+	}
+	if idx := strings.LastIndex(ref, ":"); idx > strings.LastIndex(ref, "/") {
+		return ref[:idx]
+	}
+	return ref
+}
+
+func craneOptions(ctx context.Context, ref string, keyChain authn.Keychain) []crane.Option {
+	options := []crane.Option{crane.WithAuthFromKeychain(keyChain), crane.WithContext(ctx)}
+	if strings.HasPrefix(ref, insecureScheme) {
+		options = append(options, crane.Insecure)
+	}
+	return options
+}
@@ -0,0 +1,145 @@
+package signature_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	containerregistryv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/lifecycle-manager/pkg/signature"
+)
+
+const cosignPayloadMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+type signedArtifact struct {
+	ref         string
+	layerDigest string
+}
+
+func pushSignedArtifact(t *testing.T, host string, privateKey *ecdsa.PrivateKey) signedArtifact {
+	t.Helper()
+	artifact, err := random.Image(64, 1)
+	require.NoError(t, err)
+	ref := host + "/component-descriptors/kyma-project.io/module/sample:1.0.0"
+	require.NoError(t, crane.Push(artifact, ref))
+
+	digest, err := artifact.Digest()
+	require.NoError(t, err)
+	layers, err := artifact.Layers()
+	require.NoError(t, err)
+	layerDigest, err := layers[0].Digest()
+	require.NoError(t, err)
+
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},`+
+		`"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`,
+		ref, digest))
+	hash := sha256.Sum256(payload)
+	rawSignature, err := ecdsa.SignASN1(rand.Reader, privateKey, hash[:])
+	require.NoError(t, err)
+
+	signatureImage, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer(payload, cosignPayloadMediaType),
+		Annotations: map[string]string{
+			signature.CosignSignatureAnnotation: base64.StdEncoding.EncodeToString(rawSignature),
+		},
+	})
+	require.NoError(t, err)
+	signatureRef := fmt.Sprintf("%s/component-descriptors/kyma-project.io/module/sample:%s-%s.sig",
+		host, digest.Algorithm, digest.Hex)
+	require.NoError(t, crane.Push(signatureImage, signatureRef))
+
+	return signedArtifact{ref: ref, layerDigest: layerDigest.String()}
+}
+
+func TestVerifyCosignLayer(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	artifact := pushSignedArtifact(t, serverURL.Host, privateKey)
+
+	tests := []struct {
+		name        string
+		layerDigest string
+		keys        []crypto.PublicKey
+		wantErr     error
+	}{
+		{
+			name:        "layer of signed artifact",
+			layerDigest: artifact.layerDigest,
+			keys:        []crypto.PublicKey{&otherKey.PublicKey, &privateKey.PublicKey},
+		},
+		{
+			name:        "signature of unknown key",
+			layerDigest: artifact.layerDigest,
+			keys:        []crypto.PublicKey{&otherKey.PublicKey},
+			wantErr:     signature.ErrNoCosignSignature,
+		},
+		{
+			name:        "layer not part of the artifact",
+			layerDigest: containerregistryv1.Hash{Algorithm: "sha256", Hex: fmt.Sprintf("%064d", 0)}.String(),
+			keys:        []crypto.PublicKey{&privateKey.PublicKey},
+			wantErr:     signature.ErrLayerNotInArtifact,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			err := signature.VerifyCosignLayer(context.Background(), artifact.ref, testCase.layerDigest,
+				authn.DefaultKeychain, testCase.keys)
+			if testCase.wantErr != nil {
+				require.ErrorIs(t, err, signature.ErrVerificationFailed)
+				require.ErrorIs(t, err, testCase.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestVerifyCosignLayer_UnsignedArtifact(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	artifact, err := random.Image(64, 1)
+	require.NoError(t, err)
+	ref := serverURL.Host + "/component-descriptors/kyma-project.io/module/unsigned:1.0.0"
+	require.NoError(t, crane.Push(artifact, ref))
+	layers, err := artifact.Layers()
+	require.NoError(t, err)
+	layerDigest, err := layers[0].Digest()
+	require.NoError(t, err)
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	err = signature.VerifyCosignLayer(context.Background(), ref, layerDigest.String(),
+		authn.DefaultKeychain, []crypto.PublicKey{&privateKey.PublicKey})
+	require.ErrorIs(t, err, signature.ErrVerificationFailed)
+}
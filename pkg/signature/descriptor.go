@@ -0,0 +1,32 @@
+package signature
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	// registers the normalisation algorithms referenced by OCM descriptor signatures.
+	_ "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/normalizations"
+	"github.com/open-component-model/ocm/pkg/signing"
+	// registers the RSA signature handler used for OCM descriptor signatures.
+	_ "github.com/open-component-model/ocm/pkg/signing/handlers/rsa"
+)
+
+// VerifyDescriptor verifies that the component descriptor carries a signature with the given name
+// that is valid for at least one of the public keys.
+func VerifyDescriptor(descriptor *compdesc.ComponentDescriptor, name string, keys []crypto.PublicKey) error {
+	errs := make([]error, 0, len(keys))
+	for _, key := range keys {
+		keyRegistry := signing.NewKeyRegistry()
+		keyRegistry.RegisterPublicKey(name, key)
+		registry := signing.NewRegistry(signing.DefaultHandlerRegistry(), keyRegistry)
+		err := compdesc.Verify(descriptor, registry, name)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return fmt.Errorf("%w: descriptor %s:%s with signature %s: %w",
+		ErrVerificationFailed, descriptor.GetName(), descriptor.GetVersion(), name, errors.Join(errs...))
+}
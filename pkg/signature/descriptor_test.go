@@ -0,0 +1,92 @@
+package signature_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/open-component-model/ocm/pkg/contexts/credentials"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	// registers the descriptor schema versions used for normalisation.
+	_ "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/versions"
+	ocmrsa "github.com/open-component-model/ocm/pkg/signing/handlers/rsa"
+	"github.com/open-component-model/ocm/pkg/signing/hasher/sha256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/lifecycle-manager/pkg/signature"
+)
+
+const testSignatureName = "kyma-module-signature"
+
+func signedDescriptor(t *testing.T, privateKey *rsa.PrivateKey) *compdesc.ComponentDescriptor {
+	t.Helper()
+	descriptor := compdesc.New("kyma-project.io/module/sample", "1.0.0")
+	require.NoError(t, compdesc.Sign(credentials.DefaultContext(), descriptor, privateKey,
+		ocmrsa.Handler{}, sha256.Handler{}, testSignatureName, "kyma-project.io"))
+	return descriptor
+}
+
+func TestVerifyDescriptor(t *testing.T) {
+	t.Parallel()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		signatureName string
+		keys          []crypto.PublicKey
+		modify        func(descriptor *compdesc.ComponentDescriptor)
+		wantErr       bool
+	}{
+		{
+			name:          "valid signature",
+			signatureName: testSignatureName,
+			keys:          []crypto.PublicKey{&privateKey.PublicKey},
+		},
+		{
+			name:          "valid signature with one of several keys",
+			signatureName: testSignatureName,
+			keys:          []crypto.PublicKey{&otherKey.PublicKey, &privateKey.PublicKey},
+		},
+		{
+			name:          "signature of unknown key",
+			signatureName: testSignatureName,
+			keys:          []crypto.PublicKey{&otherKey.PublicKey},
+			wantErr:       true,
+		},
+		{
+			name:          "missing signature",
+			signatureName: "other-signature",
+			keys:          []crypto.PublicKey{&privateKey.PublicKey},
+			wantErr:       true,
+		},
+		{
+			name:          "descriptor changed after signing",
+			signatureName: testSignatureName,
+			keys:          []crypto.PublicKey{&privateKey.PublicKey},
+			modify: func(descriptor *compdesc.ComponentDescriptor) {
+				descriptor.Version = "1.0.1"
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			descriptor := signedDescriptor(t, privateKey)
+			if testCase.modify != nil {
+				testCase.modify(descriptor)
+			}
+			err := signature.VerifyDescriptor(descriptor, testCase.signatureName, testCase.keys)
+			if testCase.wantErr {
+				require.ErrorIs(t, err, signature.ErrVerificationFailed)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
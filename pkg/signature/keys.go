@@ -0,0 +1,73 @@
+package signature
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	apicorev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+)
+
+// PublicKeySecretKey is the key in the data of a signature Secret containing the public key.
+const PublicKeySecretKey = "key"
+
+var (
+	ErrVerificationFailed = errors.New("signature verification failed")
+	ErrNoPublicKeys       = errors.New("no public keys found for signature")
+	ErrInvalidPublicKey   = errors.New("invalid public key")
+)
+
+// NameFor returns the name of the signature the given object has to be verified against.
+// The operator.kyma-project.io/signature label of the object takes precedence over the default name.
+// An empty name indicates that no verification is required.
+func NameFor(obj client.Object, defaultName string) string {
+	if name, found := obj.GetLabels()[shared.Signature]; found && name != "" {
+		return name
+	}
+	return defaultName
+}
+
+// PublicKeys returns the public keys of all Secrets in the namespace labeled with the signature name.
+func PublicKeys(ctx context.Context, clnt client.Reader, namespace, name string) ([]crypto.PublicKey, error) {
+	secrets := &apicorev1.SecretList{}
+	if err := clnt.List(ctx, secrets,
+		client.InNamespace(namespace), client.MatchingLabels{shared.Signature: name}); err != nil {
+		return nil, fmt.Errorf("failed to list signature secrets for %s: %w", name, err)
+	}
+	keys := make([]crypto.PublicKey, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		key, err := ParsePublicKey(secret.Data[PublicKeySecretKey])
+		if err != nil {
+			return nil, fmt.Errorf("secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w %s in namespace %s", ErrNoPublicKeys, name, namespace)
+	}
+	return keys, nil
+}
+
+// ParsePublicKey parses a PEM encoded X.509 PKIX public key, PKCS1 public key or certificate.
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM data found", ErrInvalidPublicKey)
+	}
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		return cert.PublicKey, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported PEM block %s", ErrInvalidPublicKey, block.Type)
+}
@@ -0,0 +1,100 @@
+package signature_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apicorev1 "k8s.io/api/core/v1"
+	apimetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/signature"
+)
+
+func pkixPEM(t *testing.T, key any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestParsePublicKey(t *testing.T) {
+	t.Parallel()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"PKIX RSA public key", pkixPEM(t, &rsaKey.PublicKey), false},
+		{"PKIX ECDSA public key", pkixPEM(t, &ecdsaKey.PublicKey), false},
+		{
+			"PKCS1 RSA public key",
+			pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)}),
+			false,
+		},
+		{"no PEM data", []byte("not a key"), true},
+		{"invalid PEM block", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("invalid")}), true},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			key, err := signature.ParsePublicKey(testCase.data)
+			if testCase.wantErr {
+				require.ErrorIs(t, err, signature.ErrInvalidPublicKey)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, key)
+		})
+	}
+}
+
+func TestPublicKeys(t *testing.T) {
+	t.Parallel()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	secret := &apicorev1.Secret{
+		ObjectMeta: apimetav1.ObjectMeta{
+			Name:      "module-signature",
+			Namespace: "kcp-system",
+			Labels:    map[string]string{shared.Signature: testSignatureName},
+		},
+		Data: map[string][]byte{signature.PublicKeySecretKey: pkixPEM(t, &rsaKey.PublicKey)},
+	}
+	clnt := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	keys, err := signature.PublicKeys(context.Background(), clnt, "kcp-system", testSignatureName)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	_, err = signature.PublicKeys(context.Background(), clnt, "kcp-system", "other-signature")
+	require.ErrorIs(t, err, signature.ErrNoPublicKeys)
+	_, err = signature.PublicKeys(context.Background(), clnt, "default", testSignatureName)
+	require.ErrorIs(t, err, signature.ErrNoPublicKeys)
+}
+
+func TestNameFor(t *testing.T) {
+	t.Parallel()
+	labeled := &v1beta2.ModuleTemplate{}
+	labeled.SetLabels(map[string]string{shared.Signature: "template-signature"})
+
+	assert.Equal(t, "template-signature", signature.NameFor(labeled, testSignatureName))
+	assert.Equal(t, testSignatureName, signature.NameFor(&v1beta2.ModuleTemplate{}, testSignatureName))
+	assert.Empty(t, signature.NameFor(&v1beta2.ModuleTemplate{}, ""))
+}
@@ -0,0 +1,73 @@
+package statecheck
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/jellydator/ttlcache/v3"
+
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+)
+
+const (
+	// costLimit limits the cost of a single evaluation of an expression, so that an expression
+	// iterating over large lists of the Module CR cannot block the reconciliation.
+	costLimit = 1_000_000
+	// maxPrograms bounds the compiled programs that are kept, as every ModuleTemplate version can bring
+	// other expressions.
+	maxPrograms = 1000
+)
+
+var ErrInvalidExpression = errors.New("invalid customStateCheck expression")
+
+// Compiler compiles the Expressions of CustomStateChecks to CEL programs that evaluate to a bool
+// for the Module CR bound to the v1beta2.StateCheckObjectVariable.
+// Compiled programs are cached by their expression.
+type Compiler struct {
+	env      *cel.Env
+	envErr   error
+	programs *ttlcache.Cache[string, cel.Program]
+}
+
+func NewCompiler() *Compiler {
+	env, err := cel.NewEnv(cel.Variable(v1beta2.StateCheckObjectVariable, cel.DynType))
+	return &Compiler{
+		env:    env,
+		envErr: err,
+		programs: ttlcache.New[string, cel.Program](
+			ttlcache.WithCapacity[string, cel.Program](maxPrograms),
+		),
+	}
+}
+
+var _ v1beta2.StateCheckExpressionValidator = &Compiler{}
+
+// ValidateExpression rejects expressions that do not compile or cannot evaluate to a bool.
+func (c *Compiler) ValidateExpression(expression string) error {
+	_, err := c.Compile(expression)
+	return err
+}
+
+// Compile returns the program of the expression, which is only compiled if it is not cached yet.
+func (c *Compiler) Compile(expression string) (cel.Program, error) {
+	if item := c.programs.Get(expression); item != nil {
+		return item.Value(), nil
+	}
+	if c.envErr != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", c.envErr)
+	}
+	ast, issues := c.env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExpression, issues.Err())
+	}
+	if outputType := ast.OutputType(); outputType != cel.BoolType && outputType != cel.DynType {
+		return nil, fmt.Errorf("%w: expression has to evaluate to bool, not %s", ErrInvalidExpression, outputType)
+	}
+	program, err := c.env.Program(ast, cel.CostLimit(costLimit))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExpression, err)
+	}
+	c.programs.Set(expression, program, ttlcache.NoTTL)
+	return program, nil
+}
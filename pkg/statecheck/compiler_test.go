@@ -0,0 +1,48 @@
+package statecheck_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/lifecycle-manager/pkg/statecheck"
+)
+
+func TestCompiler_ValidateExpression(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{"bool expression", `object.status.state == "Ready"`, false},
+		{"dynamic expression", `object.status.ready`, false},
+		{"syntax error", `object.status.state ==`, true},
+		{"not evaluating to bool", `size(object.status.conditions)`, true},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			err := statecheck.NewCompiler().ValidateExpression(testCase.expression)
+			if testCase.wantErr {
+				require.ErrorIs(t, err, statecheck.ErrInvalidExpression)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCompiler_CompileCachesPrograms(t *testing.T) {
+	t.Parallel()
+	compiler := statecheck.NewCompiler()
+
+	first, err := compiler.Compile(`object.status.state == "Ready"`)
+	require.NoError(t, err)
+	second, err := compiler.Compile(`object.status.state == "Ready"`)
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/kyma-project/lifecycle-manager/api/shared"
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/pkg/adapter"
+	"github.com/kyma-project/lifecycle-manager/pkg/cloudevents"
 )
 
 type KymaHelper struct {
@@ -35,7 +37,9 @@ func Helper(handler HelperClient) *KymaHelper {
 func (k *KymaHelper) UpdateStatusForExistingModules(ctx context.Context,
 	kyma *v1beta2.Kyma, newState shared.State, message string,
 ) error {
+	previousState := kyma.Status.State
 	kyma.Status.State = newState
+	kyma.Status.RecordStateTransition(previousState, message)
 	kyma.ManagedFields = nil
 
 	switch newState {
@@ -59,6 +63,7 @@ func (k *KymaHelper) UpdateStatusForExistingModules(ctx context.Context,
 	}
 	if err := k.Patch(ctx, kyma, client.Apply, SubResourceOpts(client.ForceOwnership),
 		client.FieldOwner(fieldOwner)); err != nil {
+		adapter.DiscardPendingEvents(ctx)
 		return fmt.Errorf("status could not be updated: %w", err)
 	}
 
@@ -66,9 +71,37 @@ func (k *KymaHelper) UpdateStatusForExistingModules(ctx context.Context,
 		k.recordKymaStatusMetrics(ctx, kyma)
 	}
 
+	// the module events of the reconciliation are only published once the module status is persisted,
+	// as they would otherwise be published again by the next reconciliation.
+	publishKymaEvent(ctx, kyma, previousState, message)
+	adapter.PublishPendingEvents(ctx)
+
 	return nil
 }
 
+// publishKymaEvent publishes the lifecycle event of a Kyma that became Ready or entered the Error state.
+func publishKymaEvent(ctx context.Context, kyma *v1beta2.Kyma, previousState shared.State, message string) {
+	if previousState == kyma.Status.State {
+		return
+	}
+	var eventType string
+	switch kyma.Status.State {
+	case shared.StateReady:
+		eventType = cloudevents.TypeKymaReady
+	case shared.StateError:
+		eventType = cloudevents.TypeKymaError
+	default:
+		return
+	}
+	adapter.EventSinkFromContext(ctx).Publish(ctx, cloudevents.NewKymaEvent(eventType, cloudevents.KymaData{
+		Kyma:          kyma.GetName(),
+		Namespace:     kyma.GetNamespace(),
+		State:         kyma.Status.State,
+		PreviousState: previousState,
+		Message:       message,
+	}))
+}
+
 func SubResourceOpts(opts ...client.PatchOption) *client.SubResourcePatchOptions {
 	return &client.SubResourcePatchOptions{PatchOptions: *(&client.PatchOptions{}).ApplyOptions(opts)}
 }
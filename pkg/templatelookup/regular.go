@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -24,12 +26,14 @@ var (
 	ErrInvalidRemoteModuleConfiguration = errors.New("invalid remote module template configuration")
 	ErrTemplateNotAllowed               = errors.New("module template not allowed")
 	ErrTemplateUpdateNotAllowed         = errors.New("module template update not allowed")
+	ErrInvalidVersionConstraint         = errors.New("invalid module version constraint")
 )
 
 type ModuleTemplateInfo struct {
 	*v1beta2.ModuleTemplate
 	Err            error
 	DesiredChannel string
+	DesiredVersion string
 }
 
 func NewTemplateLookup(reader client.Reader, descriptorProvider *provider.CachedDescriptorProvider, syncEnabled bool) *TemplateLookup {
@@ -58,7 +62,11 @@ func (t *TemplateLookup) GetRegularTemplates(ctx context.Context, kyma *v1beta2.
 		}
 		switch {
 		case module.RemoteModuleTemplateRef == "":
-			template = t.GetAndValidate(ctx, module.Name, module.Channel, kyma.Spec.Channel)
+			if module.Version != "" {
+				template = t.GetAndValidateByVersion(ctx, module.Name, module.Version, module.Channel, kyma)
+			} else {
+				template = t.GetAndValidate(ctx, module.Name, module.Channel, kyma.Spec.Channel)
+			}
 			if template.Err != nil {
 				break
 			}
@@ -69,7 +77,7 @@ func (t *TemplateLookup) GetRegularTemplates(ctx context.Context, kyma *v1beta2.
 		case t.syncEnabled:
 			originalModuleName := module.Name
 			module.Name = module.RemoteModuleTemplateRef // To search template with the Remote Ref
-			template = t.remoteGetAndValidate(ctx, module.Name, module.Channel, kyma.Spec.Channel)
+			template = t.remoteGetAndValidate(ctx, module.Name, module.Channel, module.Version, kyma)
 			module.Name = originalModuleName
 		default:
 			template.Err = fmt.Errorf("enable sync to use a remote module template for %s: %w", module.Name,
@@ -134,7 +142,43 @@ func (t *TemplateLookup) GetAndValidate(ctx context.Context, name, channel, defa
 	return info
 }
 
-func (t *TemplateLookup) remoteGetAndValidate(ctx context.Context, name, channel, defaultChannel string) ModuleTemplateInfo {
+// GetAndValidateByVersion resolves the ModuleTemplate with the highest descriptor version satisfying the
+// given semantic version constraint among all ModuleTemplates of the module the Kyma is allowed to use,
+// independent of their channel. If several channels offer that version, the channel of the module
+// or the Kyma is preferred.
+func (t *TemplateLookup) GetAndValidateByVersion(ctx context.Context, name, versionConstraint, channel string,
+	kyma *v1beta2.Kyma,
+) ModuleTemplateInfo {
+	return t.getAndValidateByVersion(ctx, t, name, versionConstraint, getDesiredChannel(channel, kyma.Spec.Channel),
+		kyma)
+}
+
+func (t *TemplateLookup) getAndValidateByVersion(ctx context.Context, clnt client.Reader,
+	name, versionConstraint, preferredChannel string, kyma *v1beta2.Kyma,
+) ModuleTemplateInfo {
+	info := ModuleTemplateInfo{
+		DesiredVersion: versionConstraint,
+	}
+
+	template, err := t.getTemplateByVersion(ctx, clnt, name, versionConstraint, preferredChannel, kyma)
+	if err != nil {
+		info.Err = err
+		return info
+	}
+
+	logf.FromContext(ctx).V(log.DebugLevel).Info(
+		fmt.Sprintf("using %s (%s) for module %s with version constraint %s",
+			template.Name, template.Spec.Channel, name, versionConstraint),
+	)
+	info.DesiredChannel = template.Spec.Channel
+	info.ModuleTemplate = template
+	return info
+}
+
+func (t *TemplateLookup) remoteGetAndValidate(ctx context.Context,
+	name, channel, versionConstraint string, kyma *v1beta2.Kyma,
+) ModuleTemplateInfo {
+	defaultChannel := kyma.Spec.Channel
 	desiredChannel := getDesiredChannel(channel, defaultChannel)
 	info := ModuleTemplateInfo{
 		DesiredChannel: desiredChannel,
@@ -146,6 +190,10 @@ func (t *TemplateLookup) remoteGetAndValidate(ctx context.Context, name, channel
 	}
 	runtimeClient := syncContext.RuntimeClient
 
+	if versionConstraint != "" {
+		return t.getAndValidateByVersion(ctx, runtimeClient, name, versionConstraint, desiredChannel, kyma)
+	}
+
 	template, err := t.getTemplate(ctx, runtimeClient, name, desiredChannel)
 	if err != nil {
 		info.Err = err
@@ -196,6 +244,8 @@ func moduleMatch(moduleStatus *v1beta2.ModuleStatus, moduleName string) bool {
 // It does this by looking into selected key properties:
 // 1. If the generation of ModuleTemplate changes, it means the spec is outdated
 // 2. If the channel of ModuleTemplate changes, it means the kyma has an old reference to a previous channel.
+// 3. If the ModuleTemplate was resolved through a version constraint, the resolved version must not be lower
+// than the previously installed one.
 func (t *TemplateLookup) checkValidTemplateUpdate(
 	ctx context.Context, moduleTemplate *ModuleTemplateInfo, moduleStatus *v1beta2.ModuleStatus,
 ) {
@@ -211,6 +261,11 @@ func (t *TemplateLookup) checkValidTemplateUpdate(
 		"previousTemplateChannel", moduleStatus.Channel,
 	)
 
+	if moduleTemplate.DesiredVersion != "" {
+		t.checkNoVersionDowngrade(checkLog, moduleTemplate, moduleStatus)
+		return
+	}
+
 	if moduleTemplate.Spec.Channel != moduleStatus.Channel {
 		checkLog.Info("outdated ModuleTemplate: channel skew")
 
@@ -272,6 +327,47 @@ func (t *TemplateLookup) checkValidTemplateUpdate(
 	}
 }
 
+// checkNoVersionDowngrade prevents a version constraint from resolving a lower version than the one previously
+// installed, since downgrades are not supported. To move to a lower version, a module has to be uninstalled first.
+func (t *TemplateLookup) checkNoVersionDowngrade(checkLog logr.Logger,
+	moduleTemplate *ModuleTemplateInfo, moduleStatus *v1beta2.ModuleStatus,
+) {
+	descriptor, err := t.descriptorProvider.GetDescriptor(moduleTemplate.ModuleTemplate)
+	if err != nil {
+		msg := "could not verify version constraint as descriptor from template cannot be fetched"
+		checkLog.Error(err, msg)
+		moduleTemplate.Err = fmt.Errorf("%w: %s", ErrTemplateUpdateNotAllowed, msg)
+		return
+	}
+
+	versionInTemplate, err := semver.NewVersion(descriptor.Version)
+	if err != nil {
+		msg := "could not verify version constraint as descriptor from template contains invalid version"
+		checkLog.Error(err, msg)
+		moduleTemplate.Err = fmt.Errorf("%w: %s", ErrTemplateUpdateNotAllowed, msg)
+		return
+	}
+
+	if moduleStatus.Version == "" {
+		return
+	}
+	versionInStatus, err := semver.NewVersion(moduleStatus.Version)
+	if err != nil {
+		msg := "could not verify version constraint as Modules contains invalid version"
+		checkLog.Error(err, msg)
+		moduleTemplate.Err = fmt.Errorf("%w: %s", ErrTemplateUpdateNotAllowed, msg)
+		return
+	}
+
+	if !v1beta2.IsValidVersionChange(versionInTemplate, versionInStatus) {
+		msg := fmt.Sprintf("ignore version constraint %s (resolved to %s), "+
+			"as a higher version (%s) of the module was previously installed",
+			moduleTemplate.DesiredVersion, versionInTemplate.String(), versionInStatus.String())
+		checkLog.Info(msg)
+		moduleTemplate.Err = fmt.Errorf("%w: %s", ErrTemplateUpdateNotAllowed, msg)
+	}
+}
+
 func getDesiredChannel(moduleChannel, globalChannel string) string {
 	var desiredChannel string
 
@@ -299,26 +395,15 @@ func (t *TemplateLookup) getTemplate(ctx context.Context, clnt client.Reader, na
 	var filteredTemplates []*v1beta2.ModuleTemplate
 	for _, template := range templateList.Items {
 		template := template // capture unique address
-		if template.Labels[shared.ModuleName] == name && template.Spec.Channel == desiredChannel {
-			filteredTemplates = append(filteredTemplates, &template)
+		if template.Spec.Channel != desiredChannel {
 			continue
 		}
-		if fmt.Sprintf("%s/%s", template.Namespace, template.Name) == name &&
-			template.Spec.Channel == desiredChannel {
-			filteredTemplates = append(filteredTemplates, &template)
-			continue
-		}
-		if template.ObjectMeta.Name == name && template.Spec.Channel == desiredChannel {
-			filteredTemplates = append(filteredTemplates, &template)
-			continue
-		}
-		descriptor, err := t.descriptorProvider.GetDescriptor(&template)
+		isModuleTemplate, err := t.isTemplateOfModule(&template, name)
 		if err != nil {
-			return nil, fmt.Errorf("invalid ModuleTemplate descriptor: %w", err)
+			return nil, err
 		}
-		if descriptor.Name == name && template.Spec.Channel == desiredChannel {
+		if isModuleTemplate {
 			filteredTemplates = append(filteredTemplates, &template)
-			continue
 		}
 	}
 
@@ -336,6 +421,121 @@ func (t *TemplateLookup) getTemplate(ctx context.Context, clnt client.Reader, na
 	return filteredTemplates[0], nil
 }
 
+func (t *TemplateLookup) getTemplateByVersion(ctx context.Context, clnt client.Reader,
+	name, versionConstraint, preferredChannel string, kyma *v1beta2.Kyma,
+) (*v1beta2.ModuleTemplate, error) {
+	constraint, err := semver.NewConstraint(versionConstraint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s for module %s: %w", ErrInvalidVersionConstraint, versionConstraint, name, err)
+	}
+
+	templateList := &v1beta2.ModuleTemplateList{}
+	if err := clnt.List(ctx, templateList); err != nil {
+		return nil, fmt.Errorf("failed to list module templates on lookup: %w", err)
+	}
+
+	var allowed, notAllowed versionCandidates
+	for i := range templateList.Items {
+		template := &templateList.Items[i]
+		isModuleTemplate, err := t.isTemplateOfModule(template, name)
+		if err != nil {
+			return nil, err
+		}
+		if !isModuleTemplate {
+			continue
+		}
+		descriptor, err := t.descriptorProvider.GetDescriptor(template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ModuleTemplate descriptor: %w", err)
+		}
+		version, err := semver.NewVersion(descriptor.Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ModuleTemplate descriptor version %s in %s: %w",
+				descriptor.Version, template.Name, err)
+		}
+		if !constraint.Check(version) {
+			continue
+		}
+		if isTemplateAllowed(template, kyma) {
+			allowed.add(template, version)
+		} else {
+			notAllowed.add(template, version)
+		}
+	}
+
+	// without an allowed template, the highest one is returned so that it is reported as not allowed
+	candidates := allowed
+	if len(candidates.templates) == 0 {
+		candidates = notAllowed
+	}
+	if len(candidates.templates) == 0 {
+		return nil, fmt.Errorf("%w: satisfying version %s for module %s",
+			ErrNoTemplatesInListResult, versionConstraint, name)
+	}
+	template := candidates.preferred(preferredChannel)
+	if template.Spec.Mandatory {
+		return nil, fmt.Errorf("%w: in version %s for module %s",
+			ErrTemplateMarkedAsMandatory, candidates.version.String(), name)
+	}
+	return template, nil
+}
+
+// isTemplateAllowed reports whether the Kyma can use the ModuleTemplate as a module.
+func isTemplateAllowed(template *v1beta2.ModuleTemplate, kyma *v1beta2.Kyma) bool {
+	return !template.Spec.Mandatory &&
+		(!template.IsInternal() || kyma.IsInternal()) &&
+		(!template.IsBeta() || kyma.IsBeta())
+}
+
+// versionCandidates collects the ModuleTemplates with the highest version satisfying a version constraint.
+type versionCandidates struct {
+	version   *semver.Version
+	templates []*v1beta2.ModuleTemplate
+}
+
+func (c *versionCandidates) add(template *v1beta2.ModuleTemplate, version *semver.Version) {
+	switch {
+	case c.version == nil || version.GreaterThan(c.version):
+		c.version = version
+		c.templates = []*v1beta2.ModuleTemplate{template}
+	case version.Equal(c.version):
+		c.templates = append(c.templates, template)
+	}
+}
+
+// preferred returns the candidate in the given channel. Without such a candidate, the first candidate by
+// namespace and name is returned, so that every reconciliation selects the same ModuleTemplate.
+func (c *versionCandidates) preferred(channel string) *v1beta2.ModuleTemplate {
+	sort.Slice(c.templates, func(i, j int) bool {
+		return client.ObjectKeyFromObject(c.templates[i]).String() < client.ObjectKeyFromObject(c.templates[j]).String()
+	})
+	for _, template := range c.templates {
+		if template.Spec.Channel == channel {
+			return template
+		}
+	}
+	return c.templates[0]
+}
+
+// isTemplateOfModule checks if the template belongs to the module with the given name, which is either
+// the module name label, the Namespace/Name or Name of the template, or the FQDN from its descriptor.
+func (t *TemplateLookup) isTemplateOfModule(template *v1beta2.ModuleTemplate, name string) (bool, error) {
+	if template.Labels[shared.ModuleName] == name {
+		return true, nil
+	}
+	if fmt.Sprintf("%s/%s", template.Namespace, template.Name) == name {
+		return true, nil
+	}
+	if template.ObjectMeta.Name == name {
+		return true, nil
+	}
+	descriptor, err := t.descriptorProvider.GetDescriptor(template)
+	if err != nil {
+		return false, fmt.Errorf("invalid ModuleTemplate descriptor: %w", err)
+	}
+	return descriptor.Name == name, nil
+}
+
 func NewMoreThanOneTemplateCandidateErr(moduleName string,
 	candidateTemplates []v1beta2.ModuleTemplate,
 ) error {
@@ -0,0 +1,231 @@
+package templatelookup_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	ocmmetav1 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/meta/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/internal/descriptor/provider"
+	"github.com/kyma-project/lifecycle-manager/pkg/templatelookup"
+	"github.com/kyma-project/lifecycle-manager/pkg/testutils/builder"
+)
+
+const testModuleName = "test-module"
+
+type fakeModuleTemplateReader struct {
+	client.Reader
+	templates []v1beta2.ModuleTemplate
+}
+
+func (f *fakeModuleTemplateReader) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	templateList, ok := list.(*v1beta2.ModuleTemplateList)
+	if ok {
+		templateList.Items = append(templateList.Items, f.templates...)
+	}
+	return nil
+}
+
+func moduleTemplateWithVersion(name, channel, version string) v1beta2.ModuleTemplate {
+	return *builder.NewModuleTemplateBuilder().
+		WithName(name).
+		WithModuleName(testModuleName).
+		WithChannel(channel).
+		WithDescriptor(&v1beta2.Descriptor{
+			ComponentDescriptor: &compdesc.ComponentDescriptor{
+				ComponentSpec: compdesc.ComponentSpec{
+					ObjectMeta: ocmmetav1.ObjectMeta{
+						Name:    "kyma-project.io/module/" + testModuleName,
+						Version: version,
+					},
+				},
+			},
+		}).Build()
+}
+
+func TestGetRegularTemplates_WithVersionConstraint(t *testing.T) {
+	t.Parallel()
+	templates := []v1beta2.ModuleTemplate{
+		moduleTemplateWithVersion("test-module-1.3.0", "regular", "1.3.0"),
+		moduleTemplateWithVersion("test-module-1.4.1", "regular", "1.4.1"),
+		moduleTemplateWithVersion("test-module-1.4.2", "fast", "1.4.2"),
+		moduleTemplateWithVersion("test-module-1.5.0", "experimental", "1.5.0"),
+	}
+
+	tests := []struct {
+		name             string
+		version          string
+		installedVersion string
+		wantTemplate     string
+		wantChannel      string
+		wantErr          error
+	}{
+		{
+			name:         "resolves highest matching version across channels",
+			version:      "~1.4",
+			wantTemplate: "test-module-1.4.2",
+			wantChannel:  "fast",
+		},
+		{
+			name:         "resolves exact version",
+			version:      "1.3.0",
+			wantTemplate: "test-module-1.3.0",
+			wantChannel:  "regular",
+		},
+		{
+			name:    "fails if no template satisfies the constraint",
+			version: "^2.0",
+			wantErr: templatelookup.ErrNoTemplatesInListResult,
+		},
+		{
+			name:    "fails on invalid constraint",
+			version: "not-a-version",
+			wantErr: templatelookup.ErrInvalidVersionConstraint,
+		},
+		{
+			name:             "allows upgrade of installed version",
+			version:          "~1.4",
+			installedVersion: "1.3.0",
+			wantTemplate:     "test-module-1.4.2",
+			wantChannel:      "fast",
+		},
+		{
+			name:             "prevents downgrade of installed version",
+			version:          "~1.4",
+			installedVersion: "1.5.0",
+			wantTemplate:     "test-module-1.4.2",
+			wantChannel:      "fast",
+			wantErr:          templatelookup.ErrTemplateUpdateNotAllowed,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			kyma := builder.NewKymaBuilder().
+				WithModule(v1beta2.Module{Name: testModuleName, Version: testCase.version}).
+				Build()
+			if testCase.installedVersion != "" {
+				kyma.Status.Modules = []v1beta2.ModuleStatus{{
+					Name:     testModuleName,
+					Channel:  "experimental",
+					Version:  testCase.installedVersion,
+					Template: &v1beta2.TrackingObject{PartialMeta: v1beta2.PartialMeta{Name: "previous-template"}},
+				}}
+			}
+			lookup := templatelookup.NewTemplateLookup(&fakeModuleTemplateReader{templates: templates},
+				provider.NewCachedDescriptorProvider(nil), false)
+
+			result := lookup.GetRegularTemplates(context.Background(), kyma)
+
+			require.Contains(t, result, testModuleName)
+			info := result[testModuleName]
+			if testCase.wantErr != nil {
+				require.ErrorIs(t, info.Err, testCase.wantErr)
+			} else {
+				require.NoError(t, info.Err)
+			}
+			if testCase.wantTemplate != "" {
+				require.NotNil(t, info.ModuleTemplate)
+				assert.Equal(t, testCase.wantTemplate, info.Name)
+				assert.Equal(t, testCase.wantChannel, info.DesiredChannel)
+			}
+			assert.Equal(t, testCase.version, info.DesiredVersion)
+		})
+	}
+}
+
+func TestGetRegularTemplates_WithVersionConstraint_SameVersionInSeveralChannels(t *testing.T) {
+	t.Parallel()
+	templates := []v1beta2.ModuleTemplate{
+		moduleTemplateWithVersion("test-module-regular", "regular", "1.4.1"),
+		moduleTemplateWithVersion("test-module-fast", "fast", "1.4.1"),
+		moduleTemplateWithVersion("test-module-experimental", "experimental", "1.4.1"),
+	}
+	tests := []struct {
+		name          string
+		kymaChannel   string
+		moduleChannel string
+		wantTemplate  string
+	}{
+		{"module channel", "regular", "fast", "test-module-fast"},
+		{"kyma channel", "fast", "", "test-module-fast"},
+		{"default channel", "", "", "test-module-regular"},
+		{"channel without the version", "regular", "dev", "test-module-experimental"},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			kyma := builder.NewKymaBuilder().
+				WithChannel(testCase.kymaChannel).
+				WithModule(v1beta2.Module{Name: testModuleName, Version: "1.4.1", Channel: testCase.moduleChannel}).
+				Build()
+			lookup := templatelookup.NewTemplateLookup(&fakeModuleTemplateReader{templates: templates},
+				provider.NewCachedDescriptorProvider(nil), false)
+
+			result := lookup.GetRegularTemplates(context.Background(), kyma)
+
+			require.NoError(t, result[testModuleName].Err)
+			assert.Equal(t, testCase.wantTemplate, result[testModuleName].Name)
+		})
+	}
+}
+
+func TestGetRegularTemplates_WithVersionConstraint_SkipsNotAllowedTemplates(t *testing.T) {
+	t.Parallel()
+	beta := moduleTemplateWithVersion("test-module-beta", "fast", "1.5.0")
+	beta.Labels[shared.BetaLabel] = shared.EnableLabelValue
+	internal := moduleTemplateWithVersion("test-module-internal", "fast", "1.6.0")
+	internal.Labels[shared.InternalLabel] = shared.EnableLabelValue
+	templates := []v1beta2.ModuleTemplate{
+		moduleTemplateWithVersion("test-module-1.4.0", "regular", "1.4.0"),
+		beta,
+		internal,
+	}
+	tests := []struct {
+		name         string
+		version      string
+		kymaLabels   map[string]string
+		wantTemplate string
+		wantErr      error
+	}{
+		{"highest allowed version", ">=1.4.0", nil, "test-module-1.4.0", nil},
+		{"beta Kyma", ">=1.4.0", map[string]string{shared.BetaLabel: shared.EnableLabelValue}, "test-module-beta", nil},
+		{
+			"internal Kyma",
+			">=1.4.0",
+			map[string]string{shared.InternalLabel: shared.EnableLabelValue},
+			"test-module-internal",
+			nil,
+		},
+		{"only not allowed versions", ">=1.5.0", nil, "", templatelookup.ErrTemplateNotAllowed},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			kyma := builder.NewKymaBuilder().
+				WithModule(v1beta2.Module{Name: testModuleName, Version: testCase.version}).
+				Build()
+			kyma.SetLabels(testCase.kymaLabels)
+			lookup := templatelookup.NewTemplateLookup(&fakeModuleTemplateReader{templates: templates},
+				provider.NewCachedDescriptorProvider(nil), false)
+
+			result := lookup.GetRegularTemplates(context.Background(), kyma)
+
+			if testCase.wantErr != nil {
+				require.ErrorIs(t, result[testModuleName].Err, testCase.wantErr)
+				return
+			}
+			require.NoError(t, result[testModuleName].Err)
+			assert.Equal(t, testCase.wantTemplate, result[testModuleName].Name)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package templatelookup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+)
+
+var _ v1beta2.KymaModuleValidator = &TemplateLookup{}
+
+// ValidateModules rejects modules with a channel that no ModuleTemplate of the module offers, and internal or beta
+// modules that the labels of the Kyma do not allow. Modules without any ModuleTemplate and modules with a
+// RemoteModuleTemplateRef are not validated, as their ModuleTemplates can be created after the Kyma.
+// All other lookup errors are reported in the module status during the reconciliation.
+func (t *TemplateLookup) ValidateModules(ctx context.Context, kyma *v1beta2.Kyma) field.ErrorList {
+	var errs field.ErrorList
+	for i, module := range kyma.Spec.Modules {
+		if module.RemoteModuleTemplateRef != "" {
+			continue
+		}
+		path := field.NewPath("spec").Child("modules").Index(i)
+		var template ModuleTemplateInfo
+		if module.Version != "" {
+			template = t.GetAndValidateByVersion(ctx, module.Name, module.Version, module.Channel, kyma)
+		} else {
+			template = t.GetAndValidate(ctx, module.Name, module.Channel, kyma.Spec.Channel)
+		}
+
+		switch {
+		case errors.Is(template.Err, ErrNoTemplatesInListResult) && module.Version == "":
+			channels, err := t.channelsOfModule(ctx, module.Name)
+			if err != nil {
+				errs = append(errs, field.InternalError(path, err))
+				continue
+			}
+			if len(channels) == 0 {
+				continue
+			}
+			channelPath := path.Child("channel")
+			if module.Channel == "" {
+				channelPath = field.NewPath("spec").Child("channel")
+			}
+			errs = append(errs, field.NotSupported(channelPath, template.DesiredChannel, channels))
+		case template.Err != nil:
+			continue
+		case template.IsInternal() && !kyma.IsInternal():
+			errs = append(errs, field.Forbidden(path.Child("name"),
+				fmt.Sprintf("module %s is internal and requires the Kyma to be labeled as internal", module.Name)))
+		case template.IsBeta() && !kyma.IsBeta():
+			errs = append(errs, field.Forbidden(path.Child("name"),
+				fmt.Sprintf("module %s is beta and requires the Kyma to be labeled as beta", module.Name)))
+		}
+	}
+	return errs
+}
+
+// channelsOfModule returns the sorted channels offered by the ModuleTemplates of the module
+// that are not marked as mandatory.
+func (t *TemplateLookup) channelsOfModule(ctx context.Context, name string) ([]string, error) {
+	templateList := &v1beta2.ModuleTemplateList{}
+	if err := t.List(ctx, templateList); err != nil {
+		return nil, fmt.Errorf("failed to list module templates on lookup: %w", err)
+	}
+	offered := make(map[string]bool)
+	for i := range templateList.Items {
+		template := &templateList.Items[i]
+		if template.Spec.Mandatory || template.Spec.Channel == "" {
+			continue
+		}
+		isModuleTemplate, err := t.isTemplateOfModule(template, name)
+		if err != nil {
+			return nil, err
+		}
+		if isModuleTemplate {
+			offered[template.Spec.Channel] = true
+		}
+	}
+	channels := make([]string, 0, len(offered))
+	for channel := range offered {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels, nil
+}
@@ -0,0 +1,99 @@
+package templatelookup_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kyma-project/lifecycle-manager/api/shared"
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/kyma-project/lifecycle-manager/internal/descriptor/provider"
+	"github.com/kyma-project/lifecycle-manager/pkg/templatelookup"
+	"github.com/kyma-project/lifecycle-manager/pkg/testutils/builder"
+)
+
+func TestValidateModules(t *testing.T) {
+	t.Parallel()
+	betaTemplate := moduleTemplateWithVersion("test-module-fast", "fast", "1.1.0")
+	betaTemplate.Labels[shared.BetaLabel] = shared.EnableLabelValue
+	internalTemplate := moduleTemplateWithVersion("test-module-experimental", "experimental", "1.2.0")
+	internalTemplate.Labels[shared.InternalLabel] = shared.EnableLabelValue
+	templates := []v1beta2.ModuleTemplate{
+		moduleTemplateWithVersion("test-module-regular", "regular", "1.0.0"),
+		betaTemplate,
+		internalTemplate,
+	}
+
+	tests := []struct {
+		name         string
+		kyma         *v1beta2.Kyma
+		expectedErrs field.ErrorList
+	}{
+		{
+			"offered channel",
+			builder.NewKymaBuilder().WithChannel("regular").
+				WithModule(v1beta2.Module{Name: testModuleName}).Build(),
+			nil,
+		},
+		{
+			"module channel not offered",
+			builder.NewKymaBuilder().WithChannel("regular").
+				WithModule(v1beta2.Module{Name: testModuleName, Channel: "stable"}).Build(),
+			field.ErrorList{field.NotSupported(field.NewPath("spec", "modules").Index(0).Child("channel"),
+				"stable", []string{"experimental", "fast", "regular"})},
+		},
+		{
+			"kyma channel not offered",
+			builder.NewKymaBuilder().WithChannel("stable").
+				WithModule(v1beta2.Module{Name: testModuleName}).Build(),
+			field.ErrorList{field.NotSupported(field.NewPath("spec", "channel"),
+				"stable", []string{"experimental", "fast", "regular"})},
+		},
+		{
+			"module without templates",
+			builder.NewKymaBuilder().WithChannel("stable").
+				WithModule(v1beta2.Module{Name: "unknown-module"}).Build(),
+			nil,
+		},
+		{
+			"remote module template",
+			builder.NewKymaBuilder().WithChannel("stable").
+				WithModule(v1beta2.Module{Name: testModuleName, RemoteModuleTemplateRef: "remote"}).Build(),
+			nil,
+		},
+		{
+			"beta module of non-beta kyma",
+			builder.NewKymaBuilder().WithChannel("regular").
+				WithModule(v1beta2.Module{Name: testModuleName, Channel: "fast"}).Build(),
+			field.ErrorList{field.Forbidden(field.NewPath("spec", "modules").Index(0).Child("name"),
+				"module test-module is beta and requires the Kyma to be labeled as beta")},
+		},
+		{
+			"beta module of beta kyma",
+			builder.NewKymaBuilder().WithChannel("regular").WithLabel(shared.BetaLabel, shared.EnableLabelValue).
+				WithModule(v1beta2.Module{Name: testModuleName, Channel: "fast"}).Build(),
+			nil,
+		},
+		{
+			"internal module of non-internal kyma",
+			builder.NewKymaBuilder().WithChannel("regular").
+				WithModule(v1beta2.Module{Name: testModuleName, Version: "1.2.x"}).Build(),
+			field.ErrorList{field.Forbidden(field.NewPath("spec", "modules").Index(0).Child("name"),
+				"module test-module is internal and requires the Kyma to be labeled as internal")},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			lookup := templatelookup.NewTemplateLookup(&fakeModuleTemplateReader{templates: templates},
+				provider.NewCachedDescriptorProvider(nil), false)
+
+			errs := lookup.ValidateModules(context.Background(), testCase.kyma)
+
+			assert.Equal(t, testCase.expectedErrs, errs)
+		})
+	}
+}
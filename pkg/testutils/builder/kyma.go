@@ -79,6 +79,12 @@ func (kb KymaBuilder) WithChannel(channel string) KymaBuilder {
 	return kb
 }
 
+// WithModule adds a Module to v1beta2.Kyma.Spec.Modules.
+func (kb KymaBuilder) WithModule(module v1beta2.Module) KymaBuilder {
+	kb.kyma.Spec.Modules = append(kb.kyma.Spec.Modules, module)
+	return kb
+}
+
 // WithCondition adds a Condition to v1beta2.Kyma.Status.Conditions.
 func (kb KymaBuilder) WithCondition(condition apimetav1.Condition) KymaBuilder {
 	if kb.kyma.Status.Conditions == nil {
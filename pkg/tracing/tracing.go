@@ -0,0 +1,133 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TracerName is the name of the tracer all spans of the lifecycle-manager are created with.
+	TracerName = "github.com/kyma-project/lifecycle-manager"
+	// ServiceName identifies the lifecycle-manager in the exported traces.
+	ServiceName = "lifecycle-manager"
+
+	traceParentHeader = "traceparent"
+)
+
+const (
+	KymaNameKey       = attribute.Key("kyma.name")
+	KymaNamespaceKey  = attribute.Key("kyma.namespace")
+	ManifestNameKey   = attribute.Key("manifest.name")
+	ModuleNameKey     = attribute.Key("module.name")
+	ResourceCountKey  = attribute.Key("resources.count")
+	ImageReferenceKey = attribute.Key("image.reference")
+)
+
+// Options configure the export of the traces.
+type Options struct {
+	// Endpoint is the host and port of the OTLP HTTP receiver. Tracing is disabled if it is empty.
+	Endpoint string
+	// Insecure disables TLS for the connection to the Endpoint.
+	Insecure bool
+	// SamplingRatio is the fraction of root traces that are sampled.
+	// Spans with a sampled parent are always sampled.
+	SamplingRatio float64
+	// Version is recorded as the service version of the traces.
+	Version string
+}
+
+// NewTracerProvider creates a TracerProvider exporting spans to the OTLP HTTP receiver of the Options and
+// registers it, together with the W3C trace context propagator, as the global provider.
+// The returned provider has to be shut down to flush the remaining spans.
+func NewTracerProvider(ctx context.Context, opts Options) (*sdktrace.TracerProvider, error) {
+	exporterOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(ServiceName), semconv.ServiceVersion(opts.Version)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SamplingRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider, nil
+}
+
+// Start starts a span with the global TracerProvider. Without a registered provider, the span is a no-op.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, opts...)
+}
+
+// End records the error, if any, on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// KymaAttributes returns the attributes identifying the Kyma with the given key.
+func KymaAttributes(key client.ObjectKey) trace.SpanStartEventOption {
+	return trace.WithAttributes(KymaNameKey.String(key.Name), KymaNamespaceKey.String(key.Namespace))
+}
+
+// InstrumentConfig returns a copy of the rest.Config with a wrapped transport, so that every API call with a client
+// created from it is recorded as a span and carries the trace context to the API server.
+func InstrumentConfig(config *rest.Config) *rest.Config {
+	instrumented := rest.CopyConfig(config)
+	instrumented.Wrap(func(transport http.RoundTripper) http.RoundTripper {
+		return otelhttp.NewTransport(transport, otelhttp.WithSpanNameFormatter(
+			func(_ string, req *http.Request) string {
+				return "SKR " + req.Method
+			}))
+	})
+	return instrumented
+}
+
+// TraceParent returns the W3C traceparent of the span in the context. It is empty if the span is not sampled,
+// e.g. if tracing is disabled.
+func TraceParent(ctx context.Context) string {
+	if !trace.SpanContextFromContext(ctx).IsSampled() {
+		return ""
+	}
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get(traceParentHeader)
+}
+
+// LinkTo returns an option linking the started span to the span of the W3C traceparent.
+// Invalid or empty traceparents are ignored.
+func LinkTo(traceParent string) trace.SpanStartOption {
+	ctx := propagation.TraceContext{}.Extract(context.Background(),
+		propagation.MapCarrier{traceParentHeader: traceParent})
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return trace.WithLinks()
+	}
+	return trace.WithLinks(trace.Link{SpanContext: spanContext})
+}
@@ -0,0 +1,96 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/client-go/rest"
+
+	"github.com/kyma-project/lifecycle-manager/pkg/tracing"
+)
+
+func spanContext(t *testing.T, flags trace.TraceFlags) trace.SpanContext {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	return trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: flags})
+}
+
+func TestTraceParent(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{"no span", context.Background(), ""},
+		{
+			"sampled span",
+			trace.ContextWithSpanContext(context.Background(), spanContext(t, trace.FlagsSampled)),
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{"span not sampled", trace.ContextWithSpanContext(context.Background(), spanContext(t, 0)), ""},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.expected, tracing.TraceParent(testCase.ctx))
+		})
+	}
+}
+
+func TestLinkTo(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		traceParent   string
+		expectedLinks []trace.SpanContext
+	}{
+		{
+			"valid traceparent",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			[]trace.SpanContext{spanContext(t, trace.FlagsSampled)},
+		},
+		{"empty traceparent", "", nil},
+		{"invalid traceparent", "00-invalid-01", nil},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			_, span := provider.Tracer(tracing.TracerName).Start(context.Background(), "linked",
+				tracing.LinkTo(testCase.traceParent))
+			span.End()
+
+			require.Len(t, recorder.Ended(), 1)
+			var links []trace.SpanContext
+			for _, link := range recorder.Ended()[0].Links() {
+				links = append(links, link.SpanContext.WithRemote(false))
+			}
+			assert.Equal(t, testCase.expectedLinks, links)
+		})
+	}
+}
+
+func TestInstrumentConfig_KeepsConfig(t *testing.T) {
+	t.Parallel()
+	config := &rest.Config{Host: "https://skr.example.com", QPS: 150}
+
+	instrumented := tracing.InstrumentConfig(config)
+
+	assert.Nil(t, config.WrapTransport)
+	assert.NotNil(t, instrumented.WrapTransport)
+	assert.Equal(t, config.Host, instrumented.Host)
+	assert.InDelta(t, config.QPS, instrumented.QPS, 0)
+}
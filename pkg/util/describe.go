@@ -0,0 +1,23 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DescribeFirst joins the descriptions of the first limit items and summarizes the others as "and N more".
+// The total is the number of all items, which is higher than the number of given items if they were truncated.
+func DescribeFirst[T fmt.Stringer](items []T, limit, total int) string {
+	total = max(total, len(items))
+	descriptions := make([]string, 0, min(len(items), limit)+1)
+	for i, item := range items {
+		if i == limit {
+			break
+		}
+		descriptions = append(descriptions, item.String())
+	}
+	if more := total - len(descriptions); more > 0 {
+		descriptions = append(descriptions, fmt.Sprintf("and %d more", more))
+	}
+	return strings.Join(descriptions, "; ")
+}
@@ -0,0 +1,73 @@
+package watch
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
+)
+
+// RolloutChangeHandler enqueues the Kymas whose module upgrade waits for a ModuleRollout,
+// so a released wave is applied without waiting for the next periodic reconciliation.
+type RolloutChangeHandler struct {
+	client.Reader
+}
+
+func NewRolloutChangeHandler(handlerClient ChangeHandlerClient) *RolloutChangeHandler {
+	return &RolloutChangeHandler{Reader: handlerClient}
+}
+
+func (h *RolloutChangeHandler) Watch() handler.MapFunc {
+	return func(ctx context.Context, o client.Object) []reconcile.Request {
+		emptyRequest := make([]reconcile.Request, 0)
+		// the object is used as it is, so deleted rollouts release their Kymas as well
+		moduleRollout, ok := o.(*v1beta2.ModuleRollout)
+		if !ok {
+			return emptyRequest
+		}
+
+		kymas, err := getKymaList(ctx, h)
+		if err != nil {
+			return emptyRequest
+		}
+
+		return getRequestItems(filterKymasWaitingForRollout(kymas, moduleRollout))
+	}
+}
+
+func filterKymasWaitingForRollout(kymas *v1beta2.KymaList, moduleRollout *v1beta2.ModuleRollout) []v1beta2.Kyma {
+	items := []v1beta2.Kyma{}
+	for _, kyma := range kymas.Items {
+		for _, moduleStatus := range kyma.Status.Modules {
+			if moduleStatus.Name == moduleRollout.Spec.Module && moduleStatus.DeferredUpgrade != nil &&
+				moduleStatus.DeferredUpgrade.Rollout == moduleRollout.GetName() {
+				items = append(items, kyma)
+				break
+			}
+		}
+	}
+	return items
+}
+
+// RolloutReleasePredicate passes the changes of a ModuleRollout that can release its version to further Kymas.
+// The released waves are part of the status, so status changes are passed as well.
+func RolloutReleasePredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+			oldRollout, oldOk := updateEvent.ObjectOld.(*v1beta2.ModuleRollout)
+			newRollout, newOk := updateEvent.ObjectNew.(*v1beta2.ModuleRollout)
+			if !oldOk || !newOk {
+				return false
+			}
+			return oldRollout.GetGeneration() != newRollout.GetGeneration() ||
+				oldRollout.Status.Version != newRollout.Status.Version ||
+				oldRollout.Status.CurrentWave != newRollout.Status.CurrentWave ||
+				oldRollout.Status.State != newRollout.Status.State
+		},
+	}
+}
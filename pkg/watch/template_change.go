@@ -2,12 +2,14 @@ package watch
 
 import (
 	"context"
+	"fmt"
 
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/kyma-project/lifecycle-manager/api/shared"
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
 )
 
@@ -43,7 +45,7 @@ func (h *TemplateChangeHandler) Watch() handler.MapFunc {
 func filterKymasWithTemplate(kymas *v1beta2.KymaList, template *v1beta2.ModuleTemplate) []v1beta2.Kyma {
 	items := []v1beta2.Kyma{}
 	for _, kyma := range kymas.Items {
-		templateUsed := false
+		templateUsed := isTemplateCandidateForVersionedModule(kyma, template)
 		for _, moduleStatus := range kyma.Status.Modules {
 			if moduleStatus.Template == nil {
 				continue
@@ -62,3 +64,18 @@ func filterKymasWithTemplate(kymas *v1beta2.KymaList, template *v1beta2.ModuleTe
 
 	return items
 }
+
+// isTemplateCandidateForVersionedModule checks if the template could be resolved for a module that is pinned
+// to a version constraint, since a newly created template can then satisfy the constraint with a higher version.
+func isTemplateCandidateForVersionedModule(kyma v1beta2.Kyma, template *v1beta2.ModuleTemplate) bool {
+	for _, module := range kyma.Spec.Modules {
+		if module.Version == "" {
+			continue
+		}
+		if module.Name == template.Labels[shared.ModuleName] || module.Name == template.GetName() ||
+			module.Name == fmt.Sprintf("%s/%s", template.GetNamespace(), template.GetName()) {
+			return true
+		}
+	}
+	return false
+}
@@ -106,6 +106,26 @@ var _ = Describe("Webhook ValidationCreate Strict", Ordered, func() {
 		Expect(k8sClient.Delete(webhookServerContext, crd)).Should(Succeed())
 	},
 	)
+
+	It("should deny a customStateCheck with an invalid expression", func() {
+		template := builder.NewModuleTemplateBuilder().
+			WithModuleName("test-module").
+			WithModuleCR(&data).
+			WithChannel(v1beta2.DefaultChannel).
+			WithOCM(compdescv2.SchemaVersion).Build()
+		template.Spec.CustomStateCheck = []*v1beta2.CustomStateCheck{
+			{Expression: `object.status.state == "Ready"`, MappedState: shared.StateReady},
+			{Expression: `object.status.state ==`, MappedState: shared.StateError},
+		}
+
+		err := k8sClient.Create(webhookServerContext, template)
+
+		Expect(err).To(HaveOccurred())
+		var statusErr *apierrors.StatusError
+		Expect(errors.As(err, &statusErr)).To(BeTrue())
+		Expect(string(statusErr.ErrStatus.Reason)).To(Equal("Invalid"))
+		Expect(statusErr.ErrStatus.Message).To(ContainSubstring("spec.customStateCheck[1].expression"))
+	})
 },
 )
 
@@ -39,6 +39,7 @@ import (
 	"github.com/kyma-project/lifecycle-manager/api"
 	"github.com/kyma-project/lifecycle-manager/api/v1beta2"
 	"github.com/kyma-project/lifecycle-manager/pkg/log"
+	"github.com/kyma-project/lifecycle-manager/pkg/statecheck"
 	"github.com/kyma-project/lifecycle-manager/tests/integration"
 
 	_ "github.com/open-component-model/ocm/pkg/contexts/ocm"
@@ -126,8 +127,9 @@ func SetupWebhook() {
 		})
 	Expect(err).NotTo(HaveOccurred())
 
-	Expect((&v1beta2.ModuleTemplate{}).SetupWebhookWithManager(mgr)).NotTo(HaveOccurred())
-	Expect((&v1beta2.Kyma{}).SetupWebhookWithManager(mgr)).NotTo(HaveOccurred())
+	Expect((&v1beta2.ModuleTemplate{}).SetupWebhookWithManager(mgr,
+		&v1beta2.ModuleTemplateValidator{Expressions: statecheck.NewCompiler()})).NotTo(HaveOccurred())
+	Expect((&v1beta2.Kyma{}).SetupWebhookWithManager(mgr, &v1beta2.KymaValidator{})).NotTo(HaveOccurred())
 	Expect((&v1beta2.Manifest{}).SetupWebhookWithManager(mgr)).NotTo(HaveOccurred())
 	Expect((&v1beta2.Watcher{}).SetupWebhookWithManager(mgr)).NotTo(HaveOccurred())
 	// +kubebuilder:scaffold:webhook
@@ -111,12 +111,19 @@ func verifyDeploymentInstallation(ctx context.Context, clnt client.Client, deplo
 		return err
 	}
 	deploy.Status.Replicas = *deploy.Spec.Replicas
+	deploy.Status.UpdatedReplicas = *deploy.Spec.Replicas
 	deploy.Status.ReadyReplicas = *deploy.Spec.Replicas
 	deploy.Status.AvailableReplicas = *deploy.Spec.Replicas
+	deploy.Status.ObservedGeneration = deploy.Generation
 	deploy.Status.Conditions = append(deploy.Status.Conditions,
 		apiappsv1.DeploymentCondition{
 			Type:   apiappsv1.DeploymentAvailable,
 			Status: apicorev1.ConditionTrue,
+		},
+		apiappsv1.DeploymentCondition{
+			Type:   apiappsv1.DeploymentProgressing,
+			Status: apicorev1.ConditionTrue,
+			Reason: "NewReplicaSetAvailable",
 		})
 	err = clnt.Status().Update(ctx, deploy)
 	if err != nil {
@@ -41,6 +41,8 @@ import (
 	"github.com/kyma-project/lifecycle-manager/internal"
 	declarativev2 "github.com/kyma-project/lifecycle-manager/internal/declarative/v2"
 	"github.com/kyma-project/lifecycle-manager/internal/manifest"
+	"github.com/kyma-project/lifecycle-manager/internal/manifest/layercache"
+	"github.com/kyma-project/lifecycle-manager/internal/pkg/flags"
 	"github.com/kyma-project/lifecycle-manager/internal/pkg/metrics"
 	"github.com/kyma-project/lifecycle-manager/pkg/log"
 	"github.com/kyma-project/lifecycle-manager/pkg/queue"
@@ -132,11 +134,13 @@ var _ = BeforeSuite(func() {
 	controlPlaneClient = k8sManager.GetClient()
 
 	kcp := &declarativev2.ClusterInfo{Config: cfg, Client: controlPlaneClient}
+	layerCache, err := layercache.New(filepath.Join(os.TempDir(), "layer-cache"), flags.DefaultLayerCacheMaxSize, nil)
+	Expect(err).ToNot(HaveOccurred())
 	reconciler = declarativev2.NewFromManager(k8sManager, &v1beta2.Manifest{}, queue.RequeueIntervals{
 		Success: 1 * time.Second, Busy: 1 * time.Second,
 	},
 		metrics.NewManifestMetrics(metrics.NewSharedMetrics()), declarativev2.WithSpecResolver(
-			manifest.NewSpecResolver(kcp),
+			manifest.NewSpecResolver(kcp, layerCache, false),
 		), declarativev2.WithRemoteTargetCluster(
 			func(_ context.Context, _ declarativev2.Object) (*declarativev2.ClusterInfo, error) {
 				return &declarativev2.ClusterInfo{Config: authUser.Config()}, nil